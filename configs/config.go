@@ -3,6 +3,8 @@ package configs
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +19,54 @@ type Config struct {
 	SQLPassword string
 	SQLDatabase string
 	SQLSSL      string
+
+	WebhookMaxAttempts int
+	WebhookBackoff     time.Duration
+	WebhookTimeout     time.Duration
+
+	FileStorage     string
+	UploadMaxSize   int64
+	UploadMaxFiles  int
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3UsePathStyle  bool
+	S3PresignExpiry time.Duration
+	S3Timeout       time.Duration
+
+	ApprovalRequiredDocuments     []string
+	DisbursementRequiredDocuments []string
+	ApprovalVisitMaxDistance      float64
+	ApprovalVisitMaxAge           time.Duration
+	ApprovalTiers                 []string
+
+	CreditAutoRejectScore int
+
+	PaymentGateway         string
+	PaymentCallbackURL     string
+	PaymentCallbackSecret  string
+	PaymentCallbackDelay   time.Duration
+	PaymentCallbackTimeout time.Duration
+
+	LoanFundingPeriod  time.Duration
+	LoanExpiryInterval time.Duration
+
+	LateFeePercent           float64
+	LateFeeGraceDays         int
+	LoanDelinquentAfterDays  int
+	LoanDefaultAfterDays     int
+	DelinquencyCheckInterval time.Duration
+
+	FieldSyncSecret string
+
+	InvestmentMinTicket           float64
+	InvestmentMaxTicket           float64
+	InvestmentLotSize             float64
+	InvestmentMaxLoanShare        float64
+	InvestmentMaxInvestorExposure float64
+	InvestmentMaxBorrowerExposure float64
 }
 
 func LoadConfig() (c *Config) {
@@ -30,6 +80,96 @@ func LoadConfig() (c *Config) {
 		port = "8888"
 	}
 
+	webhookMaxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || webhookMaxAttempts < 1 {
+		webhookMaxAttempts = 5
+	}
+
+	webhookBackoff, err := time.ParseDuration(os.Getenv("WEBHOOK_BACKOFF"))
+	if err != nil {
+		webhookBackoff = 2 * time.Second
+	}
+
+	webhookTimeout, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT"))
+	if err != nil {
+		webhookTimeout = 10 * time.Second
+	}
+
+	fileStorage := os.Getenv("FILE_STORAGE")
+	if fileStorage == "" {
+		fileStorage = "local"
+	}
+
+	uploadMaxSize, err := strconv.ParseInt(os.Getenv("UPLOAD_MAX_SIZE"), 10, 64)
+	if err != nil || uploadMaxSize < 1 {
+		uploadMaxSize = 5 << 20
+	}
+
+	uploadMaxFiles, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_FILES"))
+	if err != nil || uploadMaxFiles < 1 {
+		uploadMaxFiles = 10
+	}
+
+	s3UsePathStyle, err := strconv.ParseBool(os.Getenv("S3_USE_PATH_STYLE"))
+	if err != nil {
+		s3UsePathStyle = true
+	}
+
+	s3PresignExpiry, err := time.ParseDuration(os.Getenv("S3_PRESIGN_EXPIRY"))
+	if err != nil {
+		s3PresignExpiry = 15 * time.Minute
+	}
+
+	s3Timeout, err := time.ParseDuration(os.Getenv("S3_TIMEOUT"))
+	if err != nil {
+		s3Timeout = 30 * time.Second
+	}
+
+	approvalVisitMaxAge, err := time.ParseDuration(os.Getenv("APPROVAL_VISIT_MAX_AGE"))
+	if err != nil || approvalVisitMaxAge < 0 {
+		approvalVisitMaxAge = 24 * time.Hour
+	}
+
+	creditAutoRejectScore, err := strconv.Atoi(os.Getenv("CREDIT_AUTO_REJECT_SCORE"))
+	if err != nil || creditAutoRejectScore < 0 {
+		creditAutoRejectScore = 0
+	}
+
+	paymentGateway := os.Getenv("PAYMENT_GATEWAY")
+	if paymentGateway == "" {
+		paymentGateway = "simulated"
+	}
+
+	paymentCallbackURL := os.Getenv("PAYMENT_CALLBACK_URL")
+	if paymentCallbackURL == "" {
+		paymentCallbackURL = "http://localhost:8080/payments/callback"
+	}
+
+	paymentCallbackDelay, err := time.ParseDuration(os.Getenv("PAYMENT_CALLBACK_DELAY"))
+	if err != nil {
+		paymentCallbackDelay = 3 * time.Second
+	}
+
+	paymentCallbackTimeout, err := time.ParseDuration(os.Getenv("PAYMENT_CALLBACK_TIMEOUT"))
+	if err != nil {
+		paymentCallbackTimeout = 10 * time.Second
+	}
+
+	loanFundingPeriod, err := time.ParseDuration(os.Getenv("LOAN_FUNDING_PERIOD"))
+	if err != nil || loanFundingPeriod <= 0 {
+		loanFundingPeriod = 30 * 24 * time.Hour
+	}
+
+	loanExpiryInterval, err := time.ParseDuration(os.Getenv("LOAN_EXPIRY_INTERVAL"))
+	if err != nil || loanExpiryInterval <= 0 {
+		loanExpiryInterval = time.Hour
+	}
+
+	delinquencyCheckInterval, err := time.ParseDuration(os.Getenv("DELINQUENCY_CHECK_INTERVAL"))
+	if err != nil || delinquencyCheckInterval <= 0 {
+		delinquencyCheckInterval = 24 * time.Hour
+	}
+
 	return &Config{
 		Env:  os.Getenv("ENV"),
 		Port: port,
@@ -40,5 +180,90 @@ func LoadConfig() (c *Config) {
 		SQLPassword: os.Getenv("SQL_PASSWORD"),
 		SQLDatabase: os.Getenv("SQL_DATABASE"),
 		SQLSSL:      os.Getenv("SQL_SSL"),
+
+		WebhookMaxAttempts: webhookMaxAttempts,
+		WebhookBackoff:     webhookBackoff,
+		WebhookTimeout:     webhookTimeout,
+
+		FileStorage:     fileStorage,
+		UploadMaxSize:   uploadMaxSize,
+		UploadMaxFiles:  uploadMaxFiles,
+		S3Endpoint:      os.Getenv("S3_ENDPOINT"),
+		S3Region:        os.Getenv("S3_REGION"),
+		S3Bucket:        os.Getenv("S3_BUCKET"),
+		S3AccessKey:     os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:     os.Getenv("S3_SECRET_KEY"),
+		S3UsePathStyle:  s3UsePathStyle,
+		S3PresignExpiry: s3PresignExpiry,
+		S3Timeout:       s3Timeout,
+
+		ApprovalRequiredDocuments:     splitList(os.Getenv("APPROVAL_REQUIRED_DOCUMENTS")),
+		DisbursementRequiredDocuments: splitList(os.Getenv("DISBURSEMENT_REQUIRED_DOCUMENTS")),
+		ApprovalVisitMaxDistance:      parseAmount(os.Getenv("APPROVAL_VISIT_MAX_DISTANCE"), 500),
+		ApprovalVisitMaxAge:           approvalVisitMaxAge,
+		ApprovalTiers:                 splitList(os.Getenv("APPROVAL_TIERS")),
+
+		CreditAutoRejectScore: creditAutoRejectScore,
+
+		PaymentGateway:         paymentGateway,
+		PaymentCallbackURL:     paymentCallbackURL,
+		PaymentCallbackSecret:  os.Getenv("PAYMENT_CALLBACK_SECRET"),
+		PaymentCallbackDelay:   paymentCallbackDelay,
+		PaymentCallbackTimeout: paymentCallbackTimeout,
+
+		LoanFundingPeriod:  loanFundingPeriod,
+		LoanExpiryInterval: loanExpiryInterval,
+
+		LateFeePercent:           parseAmount(os.Getenv("LATE_FEE_PERCENT"), 5),
+		LateFeeGraceDays:         parseDays(os.Getenv("LATE_FEE_GRACE_DAYS"), 3),
+		LoanDelinquentAfterDays:  parseDays(os.Getenv("LOAN_DELINQUENT_AFTER_DAYS"), 1),
+		LoanDefaultAfterDays:     parseDays(os.Getenv("LOAN_DEFAULT_AFTER_DAYS"), 90),
+		DelinquencyCheckInterval: delinquencyCheckInterval,
+
+		FieldSyncSecret: os.Getenv("FIELD_SYNC_SECRET"),
+
+		InvestmentMinTicket:           parseAmount(os.Getenv("INVESTMENT_MIN_TICKET"), 100000),
+		InvestmentMaxTicket:           parseAmount(os.Getenv("INVESTMENT_MAX_TICKET"), 0),
+		InvestmentLotSize:             parseAmount(os.Getenv("INVESTMENT_LOT_SIZE"), 50000),
+		InvestmentMaxLoanShare:        parseAmount(os.Getenv("INVESTMENT_MAX_LOAN_SHARE"), 25),
+		InvestmentMaxInvestorExposure: parseAmount(os.Getenv("INVESTMENT_MAX_INVESTOR_EXPOSURE"), 0),
+		InvestmentMaxBorrowerExposure: parseAmount(os.Getenv("INVESTMENT_MAX_BORROWER_EXPOSURE"), 0),
 	}
 }
+
+// UploadMaxRequestSize caps a whole upload request, room for the allowed
+// number of files at their max size plus a megabyte for the other fields.
+func (c *Config) UploadMaxRequestSize() int64 {
+	return c.UploadMaxSize*int64(c.UploadMaxFiles) + 1<<20
+}
+
+// parseAmount reads a non negative number, zero switching the rule off
+func parseAmount(value string, fallback float64) float64 {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return fallback
+	}
+
+	return amount
+}
+
+// parseDays reads a non negative number of days, zero switching the rule off
+func parseDays(value string, fallback int) int {
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return fallback
+	}
+
+	return days
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
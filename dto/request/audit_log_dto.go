@@ -0,0 +1,14 @@
+package dto_request
+
+type AuditLogListDTO struct {
+	Page       string
+	PerPage    string
+	Sort       string
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	LoanID     string
+	From       string
+	To         string
+}
@@ -0,0 +1,36 @@
+package dto_request
+
+type CreateAutoInvestPlanDTO struct {
+	InvestorID    uint     `validate:"required"`
+	Budget        float64  `validate:"required" json:"budget"`
+	AmountPerLoan float64  `validate:"required" json:"amount_per_loan"`
+	MinRate       *float64 `json:"min_rate"`
+	MaxRate       *float64 `json:"max_rate"`
+	Tenors        []int    `json:"tenors"`
+	RiskGrades    []string `json:"risk_grades"`
+	Regions       []string `json:"regions"`
+}
+
+type UpdateAutoInvestPlanDTO struct {
+	InvestorID       uint     `validate:"required"`
+	AutoInvestPlanID string   `validate:"required"`
+	Budget           *float64 `json:"budget"`
+	AmountPerLoan    *float64 `json:"amount_per_loan"`
+	MinRate          *float64 `json:"min_rate"`
+	MaxRate          *float64 `json:"max_rate"`
+	Tenors           []int    `json:"tenors"`
+	RiskGrades       []string `json:"risk_grades"`
+	Regions          []string `json:"regions"`
+	Active           *bool    `json:"active"`
+}
+
+type DeleteAutoInvestPlanDTO struct {
+	InvestorID       uint   `validate:"required"`
+	AutoInvestPlanID string `validate:"required"`
+}
+
+type AutoInvestPlanListDTO struct {
+	InvestorID uint `validate:"required"`
+	Page       string
+	PerPage    string
+}
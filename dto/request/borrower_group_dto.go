@@ -0,0 +1,74 @@
+package dto_request
+
+import "github.com/peang/amartha-loan-service/models"
+
+type CreateBorrowerGroupDTO struct {
+	UserID         uint            `validate:"required"`
+	Role           models.UserRole `validate:"required"`
+	Name           string          `validate:"required" json:"name"`
+	Region         string          `json:"region"`
+	FieldOfficerID uint            `json:"field_officer_id"`
+	LeaderID       uint            `validate:"required" json:"leader_id"`
+	MemberIDs      []uint          `validate:"required" json:"member_ids"`
+}
+
+type BorrowerGroupDetailDTO struct {
+	GroupID string          `validate:"required"`
+	UserID  uint            `validate:"required"`
+	Role    models.UserRole `validate:"required"`
+}
+
+type BorrowerGroupListDTO struct {
+	UserID  uint            `validate:"required"`
+	Role    models.UserRole `validate:"required"`
+	Page    string
+	PerPage string
+	Region  string
+}
+
+type BorrowerGroupMemberDTO struct {
+	GroupID    string          `validate:"required"`
+	UserID     uint            `validate:"required"`
+	Role       models.UserRole `validate:"required"`
+	BorrowerID uint            `validate:"required" json:"borrower_id"`
+}
+
+type ProposeGroupLoanDTO struct {
+	GroupID        string               `validate:"required"`
+	FieldOfficerID uint                 `validate:"required"`
+	ProductID      string               `validate:"required" json:"product_id"`
+	Members        []GroupLoanMemberDTO `validate:"required" json:"members"`
+}
+
+type GroupLoanMemberDTO struct {
+	BorrowerID uint    `validate:"required" json:"borrower_id"`
+	Amount     float64 `validate:"required" json:"amount"`
+	Tenor      int     `validate:"required" json:"tenor"`
+}
+
+type GroupLoanDetailDTO struct {
+	GroupLoanID string          `validate:"required"`
+	UserID      uint            `validate:"required"`
+	Role        models.UserRole `validate:"required"`
+}
+
+type ApproveGroupLoanDTO struct {
+	GroupLoanID      string              `validate:"required"`
+	FieldValidatorID uint                `validate:"required"`
+	Documents        []DocumentUploadDTO `validate:"required"`
+	Visit            FieldVisitDTO
+}
+
+type CollectGroupLoanDTO struct {
+	GroupLoanID    string                         `validate:"required"`
+	FieldOfficerID uint                           `validate:"required"`
+	Amount         float64                        `validate:"required" json:"amount"`
+	MeetingDate    string                         `json:"meeting_date"`
+	Reference      string                         `json:"reference"`
+	Allocations    []GroupCollectionAllocationDTO `json:"allocations"`
+}
+
+type GroupCollectionAllocationDTO struct {
+	BorrowerID uint    `validate:"required" json:"borrower_id"`
+	Amount     float64 `validate:"required" json:"amount"`
+}
@@ -0,0 +1,9 @@
+package dto_request
+
+import "github.com/peang/amartha-loan-service/models"
+
+type DownloadDocumentDTO struct {
+	DocumentID string          `validate:"required"`
+	UserID     uint            `validate:"required"`
+	Role       models.UserRole `validate:"required"`
+}
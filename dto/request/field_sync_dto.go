@@ -0,0 +1,28 @@
+package dto_request
+
+import "time"
+
+// SyncCollectionsDTO carries the raw bundle so the signature is checked on
+// the exact bytes the field app signed
+type SyncCollectionsDTO struct {
+	FieldOfficerID uint   `validate:"required"`
+	Body           []byte `validate:"required"`
+	Signature      string `validate:"required"`
+}
+
+type CollectionBundleDTO struct {
+	DeviceID string                `json:"device_id"`
+	SignedAt time.Time             `json:"signed_at"`
+	Records  []CollectionRecordDTO `json:"records"`
+}
+
+// CollectionRecordDTO is one collection captured offline, ID is generated by
+// the field app. A record repays either a single loan or a group meeting.
+type CollectionRecordDTO struct {
+	ID          string                         `json:"id"`
+	LoanID      string                         `json:"loan_id"`
+	GroupLoanID string                         `json:"group_loan_id"`
+	Amount      float64                        `json:"amount"`
+	CollectedAt time.Time                      `json:"collected_at"`
+	Allocations []GroupCollectionAllocationDTO `json:"allocations"`
+}
@@ -0,0 +1,34 @@
+package dto_request
+
+import "github.com/peang/amartha-loan-service/models"
+
+type CreateInvestmentListingDTO struct {
+	InvestorID   uint    `validate:"required"`
+	InvestmentID string  `validate:"required" json:"investment_id"`
+	Price        float64 `validate:"required" json:"price"`
+}
+
+type CancelInvestmentListingDTO struct {
+	InvestorID uint   `validate:"required"`
+	ListingID  string `validate:"required"`
+}
+
+type BuyInvestmentListingDTO struct {
+	InvestorID uint   `validate:"required"`
+	ListingID  string `validate:"required"`
+}
+
+type InvestmentListingListDTO struct {
+	UserID  uint `validate:"required"`
+	Page    string
+	PerPage string
+	LoanID  string
+	Mine    bool
+}
+
+type InvestmentTransferListDTO struct {
+	UserID  uint            `validate:"required"`
+	Role    models.UserRole `validate:"required"`
+	Page    string
+	PerPage string
+}
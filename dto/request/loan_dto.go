@@ -1,21 +1,101 @@
 package dto_request
 
-import "mime/multipart"
+import (
+	"errors"
+	"mime/multipart"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
 
 type ProposeLoanDTO struct {
 	BorowwerID uint    `validate:"required"`
+	ProductID  string  `validate:"required" json:"product_id"`
 	Amount     float64 `validate:"required" json:"amount"`
+	Tenor      int     `validate:"required" json:"tenor"`
+	// GroupLoanID is set when the loan is the sub-loan of a group member
+	GroupLoanID *uint
+	// ProposedBy is the field officer proposing on behalf of the borrower
+	ProposedBy uint
+}
+
+// ValidateProduct enforces the limits of the chosen product on the proposal
+func (d *ProposeLoanDTO) ValidateProduct(product *models.LoanProduct, borrower *models.User, riskGrade string) error {
+	if !product.Active {
+		return errors.New("loan_product_inactive")
+	}
+
+	if d.Amount < product.MinAmount {
+		return errors.New("amount_below_product_minimum")
+	}
+
+	if d.Amount > product.MaxAmount {
+		return errors.New("amount_above_product_maximum")
+	}
+
+	if !product.AllowsTenor(d.Tenor) {
+		return errors.New("tenor_not_allowed")
+	}
+
+	if !product.Eligible(borrower, riskGrade) {
+		return errors.New("borrower_not_eligible")
+	}
+
+	return nil
 }
 
 type ApproveLoanDTO struct {
-	LoanID           string                `validate:"required"`
-	FieldValidatorID uint                  `validate:"required"`
-	ProveImage       *multipart.FileHeader `validate:"required"`
+	LoanID           string              `validate:"required"`
+	FieldValidatorID uint                `validate:"required"`
+	Documents        []DocumentUploadDTO `validate:"required"`
+	Visit            FieldVisitDTO
+}
+
+// FieldVisitDTO is where and when the field app says the validator was when
+// approving on site
+type FieldVisitDTO struct {
+	Latitude  *float64
+	Longitude *float64
+	VisitedAt *time.Time
+}
+
+type SignOffLoanDTO struct {
+	LoanID     string          `validate:"required"`
+	ApproverID uint            `validate:"required"`
+	Role       models.UserRole `validate:"required"`
+	Note       string          `json:"note"`
+}
+
+type PendingApprovalListDTO struct {
+	Role    models.UserRole
+	Page    string
+	PerPage string
 }
 
 type ApprovedLoanListDTO struct {
+	Page         string
+	PerPage      string
+	Cursor       *string
+	Sort         string
+	MinAmount    string
+	MaxAmount    string
+	MinRate      string
+	MaxRate      string
+	MinRemaining string
+	MaxRemaining string
+	Tenor        string
+	Sector       string
+	Region       string
+}
+
+type InvestmentListDTO struct {
+	UserID  uint            `validate:"required"`
+	Role    models.UserRole `validate:"required"`
 	Page    string
 	PerPage string
+	Cursor  *string
+	Sort    string
+	LoanID  string
 }
 
 type InvestLoanDTO struct {
@@ -24,8 +104,72 @@ type InvestLoanDTO struct {
 	Amount     float64 `validate:"required" json:"amount"`
 }
 
+type CancelLoanDTO struct {
+	LoanID string          `validate:"required"`
+	UserID uint            `validate:"required"`
+	Role   models.UserRole `validate:"required"`
+	Reason string          `json:"reason"`
+}
+
 type DisburseLoanDTO struct {
-	LoanID           string                `validate:"required"`
-	FieldOfficerID   uint                  `validate:"required"`
-	AggreementLetter *multipart.FileHeader `validate:"required"`
+	LoanID         string              `validate:"required"`
+	FieldOfficerID uint                `validate:"required"`
+	Documents      []DocumentUploadDTO `validate:"required"`
+}
+
+type DocumentUploadDTO struct {
+	Type models.DocumentType   `validate:"required"`
+	File *multipart.FileHeader `validate:"required"`
+}
+
+type LoanDetailDTO struct {
+	LoanID string          `validate:"required"`
+	UserID uint            `validate:"required"`
+	Role   models.UserRole `validate:"required"`
+}
+
+type CollectionListDTO struct {
+	Page    string
+	PerPage string
+	Bucket  string
+}
+
+type RequestRestructuringDTO struct {
+	LoanID            string `validate:"required"`
+	UserID            uint   `validate:"required"`
+	ExtraInstallments int    `json:"extra_installments"`
+	HolidayPeriods    int    `json:"holiday_periods"`
+	Reason            string `validate:"required" json:"reason"`
+}
+
+type DecideRestructuringDTO struct {
+	RestructuringID string `validate:"required"`
+	SupervisorID    uint   `validate:"required"`
+	Note            string `json:"note"`
+}
+
+type RestructuringListDTO struct {
+	Page    string
+	PerPage string
+	Status  string
+}
+
+type WriteOffLoanDTO struct {
+	LoanID       string `validate:"required"`
+	SupervisorID uint   `validate:"required"`
+	Reason       string `validate:"required" json:"reason"`
+}
+
+type PayoffQuoteDTO struct {
+	LoanID string          `validate:"required"`
+	UserID uint            `validate:"required"`
+	Role   models.UserRole `validate:"required"`
+	Date   string
+}
+
+type SettleLoanDTO struct {
+	LoanID     string  `validate:"required"`
+	BorrowerID uint    `validate:"required"`
+	Amount     float64 `validate:"required" json:"amount"`
+	Method     string  `validate:"required" json:"method"`
 }
@@ -0,0 +1,38 @@
+package dto_request
+
+import "github.com/peang/amartha-loan-service/models"
+
+type CreateLoanProductDTO struct {
+	Code                      string                      `validate:"required" json:"code"`
+	Name                      string                      `validate:"required" json:"name"`
+	MinAmount                 float64                     `validate:"required" json:"min_amount"`
+	MaxAmount                 float64                     `validate:"required" json:"max_amount"`
+	Tenors                    []int                       `validate:"required" json:"tenors"`
+	InstallmentFrequency      models.InstallmentFrequency `validate:"required" json:"installment_frequency"`
+	GracePeriodDays           int                         `json:"grace_period_days"`
+	EarlySettlementFeePercent float64                     `json:"early_settlement_fee_percent"`
+	EligibleRiskGrades        []string                    `json:"eligible_risk_grades"`
+	EligibleSectors           []string                    `json:"eligible_sectors"`
+	EligibleRegions           []string                    `json:"eligible_regions"`
+}
+
+type UpdateLoanProductDTO struct {
+	LoanProductID             string                       `validate:"required"`
+	Name                      *string                      `json:"name"`
+	MinAmount                 *float64                     `json:"min_amount"`
+	MaxAmount                 *float64                     `json:"max_amount"`
+	Tenors                    []int                        `json:"tenors"`
+	InstallmentFrequency      *models.InstallmentFrequency `json:"installment_frequency"`
+	GracePeriodDays           *int                         `json:"grace_period_days"`
+	EarlySettlementFeePercent *float64                     `json:"early_settlement_fee_percent"`
+	EligibleRiskGrades        []string                     `json:"eligible_risk_grades"`
+	EligibleSectors           []string                     `json:"eligible_sectors"`
+	EligibleRegions           []string                     `json:"eligible_regions"`
+	Active                    *bool                        `json:"active"`
+}
+
+type LoanProductListDTO struct {
+	Page       string
+	PerPage    string
+	ActiveOnly bool
+}
@@ -0,0 +1,22 @@
+package dto_request
+
+import "github.com/peang/amartha-loan-service/models"
+
+type PaymentCallbackDTO struct {
+	Body      []byte `validate:"required"`
+	Timestamp string `validate:"required"`
+	Signature string `validate:"required"`
+}
+
+type CreateVirtualAccountDTO struct {
+	LoanID     string `validate:"required"`
+	BorrowerID uint   `validate:"required"`
+}
+
+type PaymentListDTO struct {
+	LoanID  string          `validate:"required"`
+	UserID  uint            `validate:"required"`
+	Role    models.UserRole `validate:"required"`
+	Page    string
+	PerPage string
+}
@@ -0,0 +1,28 @@
+package dto_request
+
+type CreateRateCardDTO struct {
+	Product         string  `validate:"required" json:"product"`
+	RiskGrade       string  `validate:"required" json:"risk_grade"`
+	MinTenor        int     `json:"min_tenor"`
+	MaxTenor        int     `validate:"required" json:"max_tenor"`
+	Rate            float64 `validate:"required" json:"rate"`
+	PlatformFeeRate float64 `json:"platform_fee_rate"`
+	AdminID         uint    `validate:"required"`
+}
+
+type UpdateRateCardDTO struct {
+	RateCardID      string   `validate:"required"`
+	MinTenor        *int     `json:"min_tenor"`
+	MaxTenor        *int     `json:"max_tenor"`
+	Rate            *float64 `json:"rate"`
+	PlatformFeeRate *float64 `json:"platform_fee_rate"`
+	AdminID         uint     `validate:"required"`
+}
+
+type RateCardListDTO struct {
+	Page      string
+	PerPage   string
+	Product   string
+	RiskGrade string
+	Active    string
+}
@@ -0,0 +1,19 @@
+package dto_request
+
+type ReportDTO struct {
+	From   string
+	To     string
+	Region string
+}
+
+type DisbursementReportDTO struct {
+	ReportDTO
+	Period string
+}
+
+type InvestorReturnReportDTO struct {
+	ReportDTO
+	Page    string
+	PerPage string
+	Sort    string
+}
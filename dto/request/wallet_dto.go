@@ -0,0 +1,19 @@
+package dto_request
+
+type DepositDTO struct {
+	UserID uint    `validate:"required"`
+	Amount float64 `validate:"required" json:"amount"`
+	Method string  `validate:"required" json:"method"`
+}
+
+type WithdrawDTO struct {
+	UserID uint    `validate:"required"`
+	Amount float64 `validate:"required" json:"amount"`
+}
+
+type WalletTransactionListDTO struct {
+	UserID  uint `validate:"required"`
+	Page    string
+	PerPage string
+	Type    string
+}
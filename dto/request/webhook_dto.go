@@ -0,0 +1,30 @@
+package dto_request
+
+type CreateWebhookDTO struct {
+	URL        string   `validate:"required" json:"url"`
+	EventTypes []string `validate:"required" json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+type UpdateWebhookDTO struct {
+	WebhookID  string    `validate:"required"`
+	URL        *string   `json:"url"`
+	EventTypes *[]string `json:"event_types"`
+	Secret     *string   `json:"secret"`
+	Active     *bool     `json:"active"`
+}
+
+type WebhookListDTO struct {
+	Page    string
+	PerPage string
+}
+
+type WebhookDeliveryListDTO struct {
+	WebhookID string `validate:"required"`
+	Page      string
+	PerPage   string
+}
+
+type RedeliverWebhookDTO struct {
+	DeliveryID string `validate:"required"`
+}
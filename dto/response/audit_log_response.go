@@ -0,0 +1,76 @@
+package dto_response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type auditLogDetail struct {
+	ID         string          `json:"id"`
+	ActorID    *uint           `json:"actor_id,omitempty"`
+	ActorRole  string          `json:"actor_role,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	ClientIP   string          `json:"client_ip,omitempty"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func AuditLogDetailResponse(log *models.AuditLog) auditLogDetail {
+	response := auditLogDetail{
+		ID:         log.UUID.String(),
+		ActorID:    log.ActorID,
+		Action:     log.Action,
+		EntityType: log.EntityType,
+		EntityID:   log.EntityID,
+		RequestID:  log.RequestID,
+		ClientIP:   log.ClientIP,
+		PrevHash:   log.PrevHash,
+		Hash:       log.Hash,
+		CreatedAt:  log.CreatedAt,
+	}
+	if log.ActorID != nil {
+		response.ActorRole = log.ActorRole.String()
+	}
+	if log.Before != "" {
+		response.Before = json.RawMessage(log.Before)
+	}
+	if log.After != "" {
+		response.After = json.RawMessage(log.After)
+	}
+
+	return response
+}
+
+func AuditLogListResponse(logs *[]models.AuditLog) []auditLogDetail {
+	var responses = make([]auditLogDetail, 0)
+	for _, log := range *logs {
+		responses = append(responses, AuditLogDetailResponse(&log))
+	}
+	return responses
+}
+
+type auditVerification struct {
+	Checked  int    `json:"checked"`
+	Intact   bool   `json:"intact"`
+	BrokenAt string `json:"broken_at,omitempty"`
+}
+
+func AuditVerificationResponse(verification *models.AuditVerification) auditVerification {
+	response := auditVerification{
+		Checked: verification.Checked,
+		Intact:  verification.Intact,
+	}
+	if verification.BrokenAt != nil {
+		response.BrokenAt = verification.BrokenAt.String()
+	}
+
+	return response
+}
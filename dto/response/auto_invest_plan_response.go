@@ -0,0 +1,49 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type autoInvestPlanDetail struct {
+	ID              string     `json:"id"`
+	Budget          float64    `json:"budget"`
+	InvestedAmount  float64    `json:"invested_amount"`
+	RemainingBudget float64    `json:"remaining_budget"`
+	AmountPerLoan   float64    `json:"amount_per_loan"`
+	MinRate         *float64   `json:"min_rate,omitempty"`
+	MaxRate         *float64   `json:"max_rate,omitempty"`
+	Tenors          []int      `json:"tenors,omitempty"`
+	RiskGrades      []string   `json:"risk_grades,omitempty"`
+	Regions         []string   `json:"regions,omitempty"`
+	Active          bool       `json:"active"`
+	LastMatchedAt   *time.Time `json:"last_matched_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func AutoInvestPlanDetailResponse(plan *models.AutoInvestPlan) autoInvestPlanDetail {
+	return autoInvestPlanDetail{
+		ID:              plan.UUID.String(),
+		Budget:          plan.Budget,
+		InvestedAmount:  plan.InvestedAmount,
+		RemainingBudget: plan.RemainingBudget(),
+		AmountPerLoan:   plan.AmountPerLoan,
+		MinRate:         plan.MinRate,
+		MaxRate:         plan.MaxRate,
+		Tenors:          plan.Tenors,
+		RiskGrades:      plan.RiskGrades,
+		Regions:         plan.Regions,
+		Active:          plan.Active,
+		LastMatchedAt:   plan.LastMatchedAt,
+		CreatedAt:       plan.CreatedAt,
+	}
+}
+
+func AutoInvestPlanListResponse(plans *[]models.AutoInvestPlan) []autoInvestPlanDetail {
+	var responses = make([]autoInvestPlanDetail, 0)
+	for _, plan := range *plans {
+		responses = append(responses, AutoInvestPlanDetailResponse(&plan))
+	}
+	return responses
+}
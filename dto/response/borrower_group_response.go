@@ -0,0 +1,124 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type borrowerGroupMember struct {
+	BorrowerID uint   `json:"borrower_id"`
+	Name       string `json:"name,omitempty"`
+	Leader     bool   `json:"leader"`
+}
+
+type borrowerGroupDetail struct {
+	ID             string                `json:"id"`
+	Name           string                `json:"name"`
+	Region         string                `json:"region,omitempty"`
+	LeaderID       uint                  `json:"leader_id"`
+	FieldOfficerID uint                  `json:"field_officer_id"`
+	Members        []borrowerGroupMember `json:"members"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+func BorrowerGroupDetailResponse(group *models.BorrowerGroup) borrowerGroupDetail {
+	members := make([]borrowerGroupMember, 0, len(group.Members))
+	for _, member := range group.Members {
+		response := borrowerGroupMember{
+			BorrowerID: member.BorrowerID,
+			Leader:     member.BorrowerID == group.LeaderID,
+		}
+		if member.Borrower != nil {
+			response.Name = member.Borrower.Name
+		}
+		members = append(members, response)
+	}
+
+	return borrowerGroupDetail{
+		ID:             group.UUID.String(),
+		Name:           group.Name,
+		Region:         group.Region,
+		LeaderID:       group.LeaderID,
+		FieldOfficerID: group.FieldOfficerID,
+		Members:        members,
+		CreatedAt:      group.CreatedAt,
+	}
+}
+
+func BorrowerGroupListResponse(groups *[]models.BorrowerGroup) []borrowerGroupDetail {
+	var responses = make([]borrowerGroupDetail, 0)
+	for _, group := range *groups {
+		responses = append(responses, BorrowerGroupDetailResponse(&group))
+	}
+	return responses
+}
+
+type groupLoanDetail struct {
+	ID             string       `json:"id"`
+	GroupID        string       `json:"group_id,omitempty"`
+	Status         string       `json:"status"`
+	ProposedBy     uint         `json:"proposed_by"`
+	ApprovedBy     *uint        `json:"approved_by,omitempty"`
+	ProposedAmount float64      `json:"proposed_amount"`
+	Loans          []loanDetail `json:"loans"`
+	ApprovedAt     *time.Time   `json:"approved_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+func GroupLoanDetailResponse(groupLoan *models.GroupLoan) groupLoanDetail {
+	response := groupLoanDetail{
+		ID:         groupLoan.UUID.String(),
+		Status:     string(groupLoan.Status),
+		ProposedBy: groupLoan.ProposedBy,
+		ApprovedBy: groupLoan.ApprovedBy,
+		Loans:      make([]loanDetail, 0, len(groupLoan.Loans)),
+		ApprovedAt: groupLoan.ApprovedAt,
+		CreatedAt:  groupLoan.CreatedAt,
+	}
+	if groupLoan.Group != nil {
+		response.GroupID = groupLoan.Group.UUID.String()
+	}
+
+	for _, loan := range groupLoan.Loans {
+		response.ProposedAmount += loan.ProposedAmount
+		response.Loans = append(response.Loans, LoanDetailResponse(&loan))
+	}
+
+	return response
+}
+
+type groupCollectionAllocation struct {
+	BorrowerID uint    `json:"borrower_id"`
+	Amount     float64 `json:"amount"`
+}
+
+type groupCollectionDetail struct {
+	ID          string                      `json:"id"`
+	Reference   string                      `json:"reference"`
+	Amount      float64                     `json:"amount"`
+	MeetingDate string                      `json:"meeting_date"`
+	CollectedBy uint                        `json:"collected_by"`
+	Allocations []groupCollectionAllocation `json:"allocations"`
+	CreatedAt   time.Time                   `json:"created_at"`
+}
+
+func GroupCollectionDetailResponse(collection *models.GroupCollection) groupCollectionDetail {
+	allocations := make([]groupCollectionAllocation, 0, len(collection.Allocations))
+	for _, allocation := range collection.Allocations {
+		allocations = append(allocations, groupCollectionAllocation{
+			BorrowerID: allocation.BorrowerID,
+			Amount:     allocation.Amount,
+		})
+	}
+
+	return groupCollectionDetail{
+		ID:          collection.UUID.String(),
+		Reference:   collection.Reference,
+		Amount:      collection.Amount,
+		MeetingDate: collection.MeetingDate.Format(time.DateOnly),
+		CollectedBy: collection.CollectedBy,
+		Allocations: allocations,
+		CreatedAt:   collection.CreatedAt,
+	}
+}
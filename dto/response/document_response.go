@@ -0,0 +1,39 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type documentDetail struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func DocumentDetailResponse(document *models.Document) documentDetail {
+	return documentDetail{
+		ID:          document.UUID.String(),
+		Type:        string(document.Type),
+		ContentType: document.ContentType,
+		Size:        document.Size,
+		Checksum:    document.Checksum,
+		CreatedAt:   document.CreatedAt,
+	}
+}
+
+func DocumentListResponse(documents []models.Document) []documentDetail {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	var responses = make([]documentDetail, 0, len(documents))
+	for _, document := range documents {
+		responses = append(responses, DocumentDetailResponse(&document))
+	}
+	return responses
+}
@@ -0,0 +1,61 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type syncRecordResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type loanPosition struct {
+	ID            string     `json:"id"`
+	BorowwerID    uint       `json:"borowwer_id"`
+	Status        string     `json:"status"`
+	DaysPastDue   int        `json:"days_past_due"`
+	Bucket        string     `json:"delinquency_bucket,omitempty"`
+	OverdueAmount float64    `json:"overdue_amount"`
+	Outstanding   float64    `json:"outstanding"`
+	NextDueDate   *time.Time `json:"next_due_date,omitempty"`
+	NextDueAmount float64    `json:"next_due_amount"`
+}
+
+type syncReport struct {
+	Results []syncRecordResult `json:"results"`
+	Loans   []loanPosition     `json:"loans"`
+}
+
+func SyncReportResponse(report *models.SyncReport) syncReport {
+	response := syncReport{
+		Results: make([]syncRecordResult, 0, len(report.Results)),
+		Loans:   make([]loanPosition, 0, len(report.Loans)),
+	}
+
+	for _, result := range report.Results {
+		response.Results = append(response.Results, syncRecordResult{
+			ID:     result.RecordID,
+			Status: string(result.Status),
+			Reason: result.Reason,
+		})
+	}
+
+	for _, position := range report.Loans {
+		response.Loans = append(response.Loans, loanPosition{
+			ID:            position.Loan.UUID.String(),
+			BorowwerID:    position.Loan.BorrowerID,
+			Status:        position.Loan.Status.String(),
+			DaysPastDue:   position.Loan.DaysPastDue,
+			Bucket:        position.Loan.DelinquencyBucket,
+			OverdueAmount: position.Loan.OverdueAmount,
+			Outstanding:   position.Outstanding,
+			NextDueDate:   position.NextDueDate,
+			NextDueAmount: position.NextDueAmount,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,87 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type investmentListingDetail struct {
+	ID           string     `json:"id"`
+	InvestmentID string     `json:"investment_id"`
+	LoanID       string     `json:"loan_id"`
+	Amount       float64    `json:"amount"`
+	ROI          float64    `json:"roi"`
+	Rate         float64    `json:"rate"`
+	Tenor        int        `json:"tenor"`
+	RiskGrade    string     `json:"risk_grade"`
+	Price        float64    `json:"price"`
+	Status       string     `json:"status"`
+	SoldAt       *time.Time `json:"sold_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func InvestmentListingDetailResponse(listing *models.InvestmentListing) investmentListingDetail {
+	return investmentListingDetail{
+		ID:           listing.UUID.String(),
+		InvestmentID: listing.Investment.UUID.String(),
+		LoanID:       listing.Investment.Loan.UUID.String(),
+		Amount:       listing.Investment.Amount,
+		ROI:          listing.Investment.ROI,
+		Rate:         listing.Investment.Loan.Rate,
+		Tenor:        listing.Investment.Loan.Tenor,
+		RiskGrade:    listing.Investment.Loan.RiskGrade,
+		Price:        listing.Price,
+		Status:       string(listing.Status),
+		SoldAt:       listing.SoldAt,
+		CreatedAt:    listing.CreatedAt,
+	}
+}
+
+func InvestmentListingListResponse(listings *[]models.InvestmentListing) []investmentListingDetail {
+	var responses = make([]investmentListingDetail, 0)
+	for _, listing := range *listings {
+		responses = append(responses, InvestmentListingDetailResponse(&listing))
+	}
+	return responses
+}
+
+type investmentTransferDetail struct {
+	ID           string    `json:"id"`
+	InvestmentID string    `json:"investment_id"`
+	LoanID       string    `json:"loan_id"`
+	Direction    string    `json:"direction,omitempty"`
+	Amount       float64   `json:"amount"`
+	Price        float64   `json:"price"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// InvestmentTransferDetailResponse tells the viewer whether they bought or
+// sold, an admin viewing someone else's trade gets no direction.
+func InvestmentTransferDetailResponse(transfer *models.InvestmentTransfer, viewerID uint) investmentTransferDetail {
+	direction := ""
+	switch viewerID {
+	case transfer.BuyerID:
+		direction = "bought"
+	case transfer.SellerID:
+		direction = "sold"
+	}
+
+	return investmentTransferDetail{
+		ID:           transfer.UUID.String(),
+		InvestmentID: transfer.Investment.UUID.String(),
+		LoanID:       transfer.Investment.Loan.UUID.String(),
+		Direction:    direction,
+		Amount:       transfer.Amount,
+		Price:        transfer.Price,
+		CreatedAt:    transfer.CreatedAt,
+	}
+}
+
+func InvestmentTransferListResponse(transfers *[]models.InvestmentTransfer, viewerID uint) []investmentTransferDetail {
+	var responses = make([]investmentTransferDetail, 0)
+	for _, transfer := range *transfers {
+		responses = append(responses, InvestmentTransferDetailResponse(&transfer, viewerID))
+	}
+	return responses
+}
@@ -7,6 +7,7 @@ import (
 )
 
 type invstmentDetail struct {
+	ID        string     `json:"id"`
 	Loan      loanDetail `json:"loan"`
 	Amount    float64    `json:"amount"`
 	ROI       float64    `json:"roi"`
@@ -15,9 +16,18 @@ type invstmentDetail struct {
 
 func InvestmentDetailResponse(investment *models.Investment) invstmentDetail {
 	return invstmentDetail{
+		ID:        investment.UUID.String(),
 		Loan:      LoanDetailResponse(investment.Loan),
 		Amount:    investment.Amount,
 		ROI:       investment.ROI,
 		CreatedAt: investment.CreatedAt,
 	}
 }
+
+func InvestmentListResponse(investments *[]models.Investment) []invstmentDetail {
+	var responses = make([]invstmentDetail, 0)
+	for _, investment := range *investments {
+		responses = append(responses, InvestmentDetailResponse(&investment))
+	}
+	return responses
+}
@@ -0,0 +1,51 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type loanProductDetail struct {
+	ID                        string    `json:"id"`
+	Code                      string    `json:"code"`
+	Name                      string    `json:"name"`
+	MinAmount                 float64   `json:"min_amount"`
+	MaxAmount                 float64   `json:"max_amount"`
+	Tenors                    []int     `json:"tenors"`
+	InstallmentFrequency      string    `json:"installment_frequency"`
+	GracePeriodDays           int       `json:"grace_period_days"`
+	EarlySettlementFeePercent float64   `json:"early_settlement_fee_percent"`
+	EligibleRiskGrades        []string  `json:"eligible_risk_grades,omitempty"`
+	EligibleSectors           []string  `json:"eligible_sectors,omitempty"`
+	EligibleRegions           []string  `json:"eligible_regions,omitempty"`
+	Active                    bool      `json:"active"`
+	CreatedAt                 time.Time `json:"created_at"`
+}
+
+func LoanProductDetailResponse(product *models.LoanProduct) loanProductDetail {
+	return loanProductDetail{
+		ID:                        product.UUID.String(),
+		Code:                      product.Code,
+		Name:                      product.Name,
+		MinAmount:                 product.MinAmount,
+		MaxAmount:                 product.MaxAmount,
+		Tenors:                    product.Tenors,
+		InstallmentFrequency:      string(product.InstallmentFrequency),
+		GracePeriodDays:           product.GracePeriodDays,
+		EarlySettlementFeePercent: product.EarlySettlementFeePercent,
+		EligibleRiskGrades:        product.EligibleRiskGrades,
+		EligibleSectors:           product.EligibleSectors,
+		EligibleRegions:           product.EligibleRegions,
+		Active:                    product.Active,
+		CreatedAt:                 product.CreatedAt,
+	}
+}
+
+func LoanProductListResponse(products *[]models.LoanProduct) []loanProductDetail {
+	var responses = make([]loanProductDetail, 0)
+	for _, product := range *products {
+		responses = append(responses, LoanProductDetailResponse(&product))
+	}
+	return responses
+}
@@ -7,26 +7,216 @@ import (
 )
 
 type loanDetail struct {
-	ID              string    `json:"id"`
-	BorowwerID      uint      `json:"borowwer_id"`
-	ProposedAmount  float64   `json:"proposed_amount"`
-	PrincipalAmount float64   `json:"principal_amount"`
-	Rate            float64   `json:"rate"`
-	ROI             float64   `json:"roi"`
-	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID                        string              `json:"id"`
+	BorowwerID                uint                `json:"borowwer_id"`
+	ProposedAmount            float64             `json:"proposed_amount"`
+	PrincipalAmount           float64             `json:"principal_amount"`
+	Rate                      float64             `json:"rate"`
+	ROI                       float64             `json:"roi"`
+	PlatformFee               float64             `json:"platform_fee"`
+	RiskGrade                 string              `json:"risk_grade,omitempty"`
+	CreditScore               int                 `json:"credit_score,omitempty"`
+	RejectionReason           string              `json:"rejection_reason,omitempty"`
+	RateCardVersion           int                 `json:"rate_card_version,omitempty"`
+	Tenor                     int                 `json:"tenor"`
+	Frequency                 string              `json:"installment_frequency,omitempty"`
+	GracePeriodDays           int                 `json:"grace_period_days"`
+	EarlySettlementFeePercent float64             `json:"early_settlement_fee_percent"`
+	Status                    string              `json:"status"`
+	VirtualAccount            string              `json:"virtual_account_number,omitempty"`
+	DaysPastDue               int                 `json:"days_past_due"`
+	Bucket                    string              `json:"delinquency_bucket,omitempty"`
+	OverdueAmount             float64             `json:"overdue_amount"`
+	EarlySettlementFee        float64             `json:"early_settlement_fee,omitempty"`
+	ClosedAt                  *time.Time          `json:"closed_at,omitempty"`
+	Approval                  *approvalDetail     `json:"approval,omitempty"`
+	Documents                 []documentDetail    `json:"documents,omitempty"`
+	Installments              []installmentDetail `json:"installments,omitempty"`
+	Timeline                  []timelineStage     `json:"timeline,omitempty"`
+	CreatedAt                 time.Time           `json:"created_at"`
+}
+
+type approvalDetail struct {
+	FieldValidatorID uint            `json:"field_validator_id"`
+	VisitLatitude    *float64        `json:"visit_latitude,omitempty"`
+	VisitLongitude   *float64        `json:"visit_longitude,omitempty"`
+	VisitedAt        *time.Time      `json:"visited_at,omitempty"`
+	PhotoLatitude    *float64        `json:"photo_latitude,omitempty"`
+	PhotoLongitude   *float64        `json:"photo_longitude,omitempty"`
+	PhotoTakenAt     *time.Time      `json:"photo_taken_at,omitempty"`
+	PhotoDistance    *float64        `json:"photo_distance,omitempty"`
+	Flagged          bool            `json:"flagged"`
+	Flags            []string        `json:"flags"`
+	RequiredRoles    []string        `json:"required_sign_offs"`
+	MissingRoles     []string        `json:"missing_sign_offs"`
+	SignOffs         []signOffDetail `json:"sign_offs"`
+	ApprovedAt       *time.Time      `json:"approved_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+type signOffDetail struct {
+	ApproverID uint      `json:"approver_id"`
+	Role       string    `json:"role"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ApprovalDetailResponse(approval *models.Approval) *approvalDetail {
+	if approval == nil {
+		return nil
+	}
+
+	flags := approval.Flags
+	if flags == nil {
+		flags = []string{}
+	}
+
+	requiredRoles := make([]string, 0, len(approval.RequiredRoles))
+	for _, role := range approval.RequiredRoles {
+		requiredRoles = append(requiredRoles, models.UserRole(role).String())
+	}
+
+	missingRoles := []string{}
+	for _, role := range approval.MissingRoles(approval.SignOffs) {
+		missingRoles = append(missingRoles, role.String())
+	}
+
+	signOffs := make([]signOffDetail, 0, len(approval.SignOffs))
+	for _, signOff := range approval.SignOffs {
+		signOffs = append(signOffs, signOffDetail{
+			ApproverID: signOff.ApproverID,
+			Role:       signOff.Role.String(),
+			Note:       signOff.Note,
+			CreatedAt:  signOff.CreatedAt,
+		})
+	}
+
+	return &approvalDetail{
+		FieldValidatorID: approval.FieldValidatorID,
+		VisitLatitude:    approval.VisitLatitude,
+		VisitLongitude:   approval.VisitLongitude,
+		VisitedAt:        approval.VisitedAt,
+		PhotoLatitude:    approval.PhotoLatitude,
+		PhotoLongitude:   approval.PhotoLongitude,
+		PhotoTakenAt:     approval.PhotoTakenAt,
+		PhotoDistance:    approval.PhotoDistance,
+		Flagged:          approval.Flagged(),
+		Flags:            flags,
+		RequiredRoles:    requiredRoles,
+		MissingRoles:     missingRoles,
+		SignOffs:         signOffs,
+		ApprovedAt:       approval.ApprovedAt,
+		CreatedAt:        approval.CreatedAt,
+	}
+}
+
+// timelineStage is a status the loan went through and how long it stayed
+// there, a stage still running has no end.
+type timelineStage struct {
+	Status          string     `json:"status"`
+	FromStatus      string     `json:"from_status,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	ActorID         *uint      `json:"actor_id,omitempty"`
+	ActorRole       string     `json:"actor_role,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds *int64     `json:"duration_seconds,omitempty"`
+}
+
+func TimelineResponse(history []models.LoanStatusChange) []timelineStage {
+	if len(history) == 0 {
+		return nil
+	}
+
+	stages := make([]timelineStage, 0, len(history))
+	for index, change := range history {
+		stage := timelineStage{
+			Status:    change.ToStatus.String(),
+			Reason:    change.Reason,
+			ActorID:   change.ActorID,
+			StartedAt: change.CreatedAt,
+		}
+		if change.FromStatus != nil {
+			stage.FromStatus = change.FromStatus.String()
+		}
+		if change.ActorID != nil {
+			stage.ActorRole = change.ActorRole.String()
+		}
+		if index+1 < len(history) {
+			endedAt := history[index+1].CreatedAt
+			duration := int64(endedAt.Sub(change.CreatedAt).Seconds())
+			stage.EndedAt = &endedAt
+			stage.DurationSeconds = &duration
+		}
+		stages = append(stages, stage)
+	}
+
+	return stages
+}
+
+type installmentDetail struct {
+	Sequence        int        `json:"sequence"`
+	DueDate         time.Time  `json:"due_date"`
+	PrincipalAmount float64    `json:"principal_amount"`
+	InterestAmount  float64    `json:"interest_amount"`
+	LateFee         float64    `json:"late_fee"`
+	PaidAmount      float64    `json:"paid_amount"`
+	Outstanding     float64    `json:"outstanding"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	SupersededAt    *time.Time `json:"superseded_at,omitempty"`
+}
+
+func InstallmentListResponse(installments []models.Installment) []installmentDetail {
+	if len(installments) == 0 {
+		return nil
+	}
+
+	var responses = make([]installmentDetail, 0, len(installments))
+	for _, installment := range installments {
+		responses = append(responses, installmentDetail{
+			Sequence:        installment.Sequence,
+			DueDate:         installment.DueDate,
+			PrincipalAmount: installment.PrincipalAmount,
+			InterestAmount:  installment.InterestAmount,
+			LateFee:         installment.LateFee,
+			PaidAmount:      installment.PaidAmount,
+			Outstanding:     installment.Outstanding(),
+			PaidAt:          installment.PaidAt,
+			SupersededAt:    installment.SupersededAt,
+		})
+	}
+	return responses
 }
 
 func LoanDetailResponse(loan *models.Loan) loanDetail {
 	return loanDetail{
-		ID:              loan.UUID.String(),
-		BorowwerID:      loan.BorrowerID,
-		ProposedAmount:  loan.ProposedAmount,
-		PrincipalAmount: loan.PrincipalAmount,
-		Rate:            loan.Rate,
-		ROI:             loan.ROI,
-		Status:          loan.Status.String(),
-		CreatedAt:       loan.CreatedAt,
+		ID:                        loan.UUID.String(),
+		BorowwerID:                loan.BorrowerID,
+		ProposedAmount:            loan.ProposedAmount,
+		PrincipalAmount:           loan.PrincipalAmount,
+		Rate:                      loan.Rate,
+		ROI:                       loan.ROI,
+		PlatformFee:               loan.PlatformFee,
+		RiskGrade:                 loan.RiskGrade,
+		CreditScore:               loan.CreditScore,
+		RejectionReason:           loan.RejectionReason,
+		RateCardVersion:           loan.RateCardVersion,
+		Tenor:                     loan.Tenor,
+		Frequency:                 string(loan.Frequency),
+		GracePeriodDays:           loan.GracePeriodDays,
+		EarlySettlementFeePercent: loan.EarlySettlementFeePercent,
+		Status:                    loan.Status.String(),
+		VirtualAccount:            loan.VirtualAccount,
+		DaysPastDue:               loan.DaysPastDue,
+		Bucket:                    loan.DelinquencyBucket,
+		OverdueAmount:             loan.OverdueAmount,
+		EarlySettlementFee:        loan.EarlySettlementFee,
+		ClosedAt:                  loan.ClosedAt,
+		Approval:                  ApprovalDetailResponse(loan.Approval),
+		Documents:                 DocumentListResponse(loan.Documents),
+		Installments:              InstallmentListResponse(loan.Installments),
+		Timeline:                  TimelineResponse(loan.StatusHistory),
+		CreatedAt:                 loan.CreatedAt,
 	}
 }
 
@@ -35,8 +225,12 @@ type loanList struct {
 	BorowwerID      uint    `json:"borowwer_id"`
 	ProposedAmount  float64 `json:"proposed_amount"`
 	PrincipalAmount float64 `json:"principal_amount"`
+	RemainingAmount float64 `json:"remaining_amount"`
 	Rate            float64 `json:"rate"`
 	ROI             float64 `json:"roi"`
+	Tenor           int     `json:"tenor"`
+	Sector          string  `json:"sector,omitempty"`
+	Region          string  `json:"region,omitempty"`
 	Status          string  `json:"status"`
 }
 
@@ -48,11 +242,72 @@ func LoanListResponse(loans *[]models.Loan) []loanList {
 			BorowwerID:      loan.BorrowerID,
 			ProposedAmount:  loan.ProposedAmount,
 			PrincipalAmount: loan.PrincipalAmount,
+			RemainingAmount: loan.RemainingAmount(),
 			Rate:            loan.Rate,
 			ROI:             loan.ROI,
+			Tenor:           loan.Tenor,
+			Status:          loan.Status.String(),
+		}
+		if loan.Borrower != nil {
+			response.Sector = loan.Borrower.Sector
+			response.Region = loan.Borrower.Region
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+type collectionItem struct {
+	ID              string  `json:"id"`
+	BorowwerID      uint    `json:"borowwer_id"`
+	BorrowerName    string  `json:"borrower_name,omitempty"`
+	Region          string  `json:"region,omitempty"`
+	PrincipalAmount float64 `json:"principal_amount"`
+	DaysPastDue     int     `json:"days_past_due"`
+	Bucket          string  `json:"delinquency_bucket"`
+	OverdueAmount   float64 `json:"overdue_amount"`
+	VirtualAccount  string  `json:"virtual_account_number,omitempty"`
+	Status          string  `json:"status"`
+}
+
+func CollectionListResponse(loans *[]models.Loan) []collectionItem {
+	var responses = make([]collectionItem, 0)
+	for _, loan := range *loans {
+		response := collectionItem{
+			ID:              loan.UUID.String(),
+			BorowwerID:      loan.BorrowerID,
+			PrincipalAmount: loan.PrincipalAmount,
+			DaysPastDue:     loan.DaysPastDue,
+			Bucket:          loan.DelinquencyBucket,
+			OverdueAmount:   loan.OverdueAmount,
+			VirtualAccount:  loan.VirtualAccount,
 			Status:          loan.Status.String(),
 		}
+		if loan.Borrower != nil {
+			response.BorrowerName = loan.Borrower.Name
+			response.Region = loan.Borrower.Region
+		}
 		responses = append(responses, response)
 	}
 	return responses
 }
+
+type payoffQuoteDetail struct {
+	Date                 string  `json:"date"`
+	OutstandingPrincipal float64 `json:"outstanding_principal"`
+	AccruedInterest      float64 `json:"accrued_interest"`
+	LateFees             float64 `json:"late_fees"`
+	EarlySettlementFee   float64 `json:"early_settlement_fee"`
+	Total                float64 `json:"total"`
+}
+
+func PayoffQuoteResponse(quote *models.PayoffQuote) payoffQuoteDetail {
+	return payoffQuoteDetail{
+		Date:                 quote.Date.Format(time.DateOnly),
+		OutstandingPrincipal: quote.OutstandingPrincipal,
+		AccruedInterest:      quote.AccruedInterest,
+		LateFees:             quote.LateFees,
+		EarlySettlementFee:   quote.EarlySettlementFee,
+		Total:                quote.Total,
+	}
+}
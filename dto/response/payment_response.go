@@ -0,0 +1,41 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type paymentDetail struct {
+	ID                   string     `json:"id"`
+	Direction            string     `json:"direction"`
+	Method               string     `json:"method"`
+	Status               string     `json:"status"`
+	Amount               float64    `json:"amount"`
+	VirtualAccountNumber string     `json:"virtual_account_number,omitempty"`
+	FailureReason        string     `json:"failure_reason,omitempty"`
+	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+func PaymentDetailResponse(payment *models.Payment) paymentDetail {
+	return paymentDetail{
+		ID:                   payment.UUID.String(),
+		Direction:            string(payment.Direction),
+		Method:               payment.Method,
+		Status:               string(payment.Status),
+		Amount:               payment.Amount,
+		VirtualAccountNumber: payment.VirtualAccountNumber,
+		FailureReason:        payment.FailureReason,
+		CompletedAt:          payment.CompletedAt,
+		CreatedAt:            payment.CreatedAt,
+	}
+}
+
+func PaymentListResponse(payments *[]models.Payment) []paymentDetail {
+	var responses = make([]paymentDetail, 0)
+	for _, payment := range *payments {
+		responses = append(responses, PaymentDetailResponse(&payment))
+	}
+	return responses
+}
@@ -0,0 +1,43 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type rateCardDetail struct {
+	ID              string    `json:"id"`
+	Version         int       `json:"version"`
+	Product         string    `json:"product"`
+	RiskGrade       string    `json:"risk_grade"`
+	MinTenor        int       `json:"min_tenor"`
+	MaxTenor        int       `json:"max_tenor"`
+	Rate            float64   `json:"rate"`
+	PlatformFeeRate float64   `json:"platform_fee_rate"`
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func RateCardDetailResponse(rateCard *models.RateCard) rateCardDetail {
+	return rateCardDetail{
+		ID:              rateCard.UUID.String(),
+		Version:         rateCard.Version,
+		Product:         rateCard.Product,
+		RiskGrade:       rateCard.RiskGrade,
+		MinTenor:        rateCard.MinTenor,
+		MaxTenor:        rateCard.MaxTenor,
+		Rate:            rateCard.Rate,
+		PlatformFeeRate: rateCard.PlatformFeeRate,
+		Active:          rateCard.Active,
+		CreatedAt:       rateCard.CreatedAt,
+	}
+}
+
+func RateCardListResponse(rateCards *[]models.RateCard) []rateCardDetail {
+	var responses = make([]rateCardDetail, 0)
+	for _, rateCard := range *rateCards {
+		responses = append(responses, RateCardDetailResponse(&rateCard))
+	}
+	return responses
+}
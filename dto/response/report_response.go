@@ -0,0 +1,273 @@
+package dto_response
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+// Every report also has a CSV form, a header row followed by one row per
+// line of the report.
+
+type statusReport struct {
+	Status         string  `json:"status"`
+	Loans          int     `json:"loans"`
+	ProposedAmount float64 `json:"proposed_amount"`
+	InvestedAmount float64 `json:"invested_amount"`
+}
+
+func StatusReportResponse(reports []models.StatusReport) []statusReport {
+	var responses = make([]statusReport, 0)
+	for _, report := range reports {
+		responses = append(responses, statusReport{
+			Status:         report.Status.String(),
+			Loans:          report.Loans,
+			ProposedAmount: report.ProposedAmount,
+			InvestedAmount: report.InvestedAmount,
+		})
+	}
+	return responses
+}
+
+func StatusReportCSV(reports []models.StatusReport) [][]string {
+	rows := [][]string{{"status", "loans", "proposed_amount", "invested_amount"}}
+	for _, report := range reports {
+		rows = append(rows, []string{
+			report.Status.String(),
+			strconv.Itoa(report.Loans),
+			formatAmount(report.ProposedAmount),
+			formatAmount(report.InvestedAmount),
+		})
+	}
+	return rows
+}
+
+type disbursementReport struct {
+	Period time.Time `json:"period"`
+	Loans  int       `json:"loans"`
+	Amount float64   `json:"amount"`
+}
+
+func DisbursementReportResponse(reports []models.DisbursementReport) []disbursementReport {
+	var responses = make([]disbursementReport, 0)
+	for _, report := range reports {
+		responses = append(responses, disbursementReport{
+			Period: report.Period,
+			Loans:  report.Loans,
+			Amount: report.Amount,
+		})
+	}
+	return responses
+}
+
+func DisbursementReportCSV(reports []models.DisbursementReport) [][]string {
+	rows := [][]string{{"period", "loans", "amount"}}
+	for _, report := range reports {
+		rows = append(rows, []string{
+			report.Period.Format(time.DateOnly),
+			strconv.Itoa(report.Loans),
+			formatAmount(report.Amount),
+		})
+	}
+	return rows
+}
+
+type timeToFundReport struct {
+	Loans          int     `json:"loans"`
+	AverageSeconds float64 `json:"average_seconds"`
+	MinSeconds     float64 `json:"min_seconds"`
+	MaxSeconds     float64 `json:"max_seconds"`
+}
+
+func TimeToFundReportResponse(report *models.TimeToFundReport) timeToFundReport {
+	return timeToFundReport{
+		Loans:          report.Loans,
+		AverageSeconds: report.AverageSeconds,
+		MinSeconds:     report.MinSeconds,
+		MaxSeconds:     report.MaxSeconds,
+	}
+}
+
+func TimeToFundReportCSV(report *models.TimeToFundReport) [][]string {
+	return [][]string{
+		{"loans", "average_seconds", "min_seconds", "max_seconds"},
+		{
+			strconv.Itoa(report.Loans),
+			formatAmount(report.AverageSeconds),
+			formatAmount(report.MinSeconds),
+			formatAmount(report.MaxSeconds),
+		},
+	}
+}
+
+type fundingReport struct {
+	Loans          int     `json:"loans"`
+	FundedLoans    int     `json:"funded_loans"`
+	ProposedAmount float64 `json:"proposed_amount"`
+	InvestedAmount float64 `json:"invested_amount"`
+	FundingRatio   float64 `json:"funding_ratio"`
+}
+
+func FundingReportResponse(report *models.FundingReport) fundingReport {
+	return fundingReport{
+		Loans:          report.Loans,
+		FundedLoans:    report.FundedLoans,
+		ProposedAmount: report.ProposedAmount,
+		InvestedAmount: report.InvestedAmount,
+		FundingRatio:   report.Ratio(),
+	}
+}
+
+func FundingReportCSV(report *models.FundingReport) [][]string {
+	return [][]string{
+		{"loans", "funded_loans", "proposed_amount", "invested_amount", "funding_ratio"},
+		{
+			strconv.Itoa(report.Loans),
+			strconv.Itoa(report.FundedLoans),
+			formatAmount(report.ProposedAmount),
+			formatAmount(report.InvestedAmount),
+			formatAmount(report.Ratio()),
+		},
+	}
+}
+
+type portfolioAtRiskReport struct {
+	Loans       int     `json:"loans"`
+	Outstanding float64 `json:"outstanding"`
+	PAR30Loans  int     `json:"par30_loans"`
+	PAR30Amount float64 `json:"par30_amount"`
+	PAR30       float64 `json:"par30"`
+	PAR90Loans  int     `json:"par90_loans"`
+	PAR90Amount float64 `json:"par90_amount"`
+	PAR90       float64 `json:"par90"`
+}
+
+func PortfolioAtRiskReportResponse(report *models.PortfolioAtRiskReport) portfolioAtRiskReport {
+	return portfolioAtRiskReport{
+		Loans:       report.Loans,
+		Outstanding: report.Outstanding,
+		PAR30Loans:  report.PAR30Loans,
+		PAR30Amount: report.PAR30Amount,
+		PAR30:       report.PAR30(),
+		PAR90Loans:  report.PAR90Loans,
+		PAR90Amount: report.PAR90Amount,
+		PAR90:       report.PAR90(),
+	}
+}
+
+func PortfolioAtRiskReportCSV(report *models.PortfolioAtRiskReport) [][]string {
+	return [][]string{
+		{"loans", "outstanding", "par30_loans", "par30_amount", "par30", "par90_loans", "par90_amount", "par90"},
+		{
+			strconv.Itoa(report.Loans),
+			formatAmount(report.Outstanding),
+			strconv.Itoa(report.PAR30Loans),
+			formatAmount(report.PAR30Amount),
+			formatAmount(report.PAR30()),
+			strconv.Itoa(report.PAR90Loans),
+			formatAmount(report.PAR90Amount),
+			formatAmount(report.PAR90()),
+		},
+	}
+}
+
+type investorReturnReport struct {
+	InvestorID      uint    `json:"investor_id"`
+	InvestorName    string  `json:"investor_name"`
+	Investments     int     `json:"investments"`
+	InvestedAmount  float64 `json:"invested_amount"`
+	RepaidPrincipal float64 `json:"repaid_principal"`
+	Payouts         float64 `json:"payouts"`
+	Losses          float64 `json:"losses"`
+	NetReturn       float64 `json:"net_return"`
+	ReturnRate      float64 `json:"return_rate"`
+}
+
+func InvestorReturnReportResponse(reports []models.InvestorReturnReport) []investorReturnReport {
+	var responses = make([]investorReturnReport, 0)
+	for _, report := range reports {
+		responses = append(responses, investorReturnReport{
+			InvestorID:      report.InvestorID,
+			InvestorName:    report.InvestorName,
+			Investments:     report.Investments,
+			InvestedAmount:  report.InvestedAmount,
+			RepaidPrincipal: report.RepaidPrincipal,
+			Payouts:         report.Payouts,
+			Losses:          report.Losses,
+			NetReturn:       report.NetReturn(),
+			ReturnRate:      report.ReturnRate(),
+		})
+	}
+	return responses
+}
+
+func InvestorReturnReportCSV(reports []models.InvestorReturnReport) [][]string {
+	rows := [][]string{{"investor_id", "investor_name", "investments", "invested_amount", "repaid_principal", "payouts", "losses", "net_return", "return_rate"}}
+	for _, report := range reports {
+		rows = append(rows, []string{
+			strconv.FormatUint(uint64(report.InvestorID), 10),
+			report.InvestorName,
+			strconv.Itoa(report.Investments),
+			formatAmount(report.InvestedAmount),
+			formatAmount(report.RepaidPrincipal),
+			formatAmount(report.Payouts),
+			formatAmount(report.Losses),
+			formatAmount(report.NetReturn()),
+			formatAmount(report.ReturnRate()),
+		})
+	}
+	return rows
+}
+
+type branchReport struct {
+	Region           string  `json:"region"`
+	Loans            int     `json:"loans"`
+	DisbursedLoans   int     `json:"disbursed_loans"`
+	DisbursedAmount  float64 `json:"disbursed_amount"`
+	Outstanding      float64 `json:"outstanding"`
+	PAR30Amount      float64 `json:"par30_amount"`
+	PAR30            float64 `json:"par30"`
+	WrittenOffAmount float64 `json:"written_off_amount"`
+	RepaidLoans      int     `json:"repaid_loans"`
+}
+
+func BranchReportResponse(reports []models.BranchReport) []branchReport {
+	var responses = make([]branchReport, 0)
+	for _, report := range reports {
+		responses = append(responses, branchReport{
+			Region:           report.Region,
+			Loans:            report.Loans,
+			DisbursedLoans:   report.DisbursedLoans,
+			DisbursedAmount:  report.DisbursedAmount,
+			Outstanding:      report.Outstanding,
+			PAR30Amount:      report.PAR30Amount,
+			PAR30:            report.PAR30(),
+			WrittenOffAmount: report.WrittenOffAmount,
+			RepaidLoans:      report.RepaidLoans,
+		})
+	}
+	return responses
+}
+
+func BranchReportCSV(reports []models.BranchReport) [][]string {
+	rows := [][]string{{"region", "loans", "disbursed_loans", "disbursed_amount", "outstanding", "par30_amount", "par30", "written_off_amount", "repaid_loans"}}
+	for _, report := range reports {
+		rows = append(rows, []string{
+			report.Region,
+			strconv.Itoa(report.Loans),
+			strconv.Itoa(report.DisbursedLoans),
+			formatAmount(report.DisbursedAmount),
+			formatAmount(report.Outstanding),
+			formatAmount(report.PAR30Amount),
+			formatAmount(report.PAR30()),
+			formatAmount(report.WrittenOffAmount),
+			strconv.Itoa(report.RepaidLoans),
+		})
+	}
+	return rows
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,78 @@
+package dto_response
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+func TestReportCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+		want []string
+	}{
+		{
+			name: "status",
+			rows: StatusReportCSV([]models.StatusReport{{Status: models.LoanStatusApproved, Loans: 3, ProposedAmount: 1500000, InvestedAmount: 250000.5}}),
+			want: []string{"approved", "3", "1500000.00", "250000.50"},
+		},
+		{
+			name: "disbursement",
+			rows: DisbursementReportCSV([]models.DisbursementReport{{Period: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Loans: 2, Amount: 700000}}),
+			want: []string{"2024-03-01", "2", "700000.00"},
+		},
+		{
+			name: "time to fund",
+			rows: TimeToFundReportCSV(&models.TimeToFundReport{Loans: 4, AverageSeconds: 3600.456, MinSeconds: 60, MaxSeconds: 86400}),
+			want: []string{"4", "3600.46", "60.00", "86400.00"},
+		},
+		{
+			name: "funding",
+			rows: FundingReportCSV(&models.FundingReport{Loans: 3, FundedLoans: 2, ProposedAmount: 3000000, InvestedAmount: 2000000}),
+			want: []string{"3", "2", "3000000.00", "2000000.00", "66.67"},
+		},
+		{
+			name: "portfolio at risk",
+			rows: PortfolioAtRiskReportCSV(&models.PortfolioAtRiskReport{Loans: 10, Outstanding: 9000000, PAR30Loans: 2, PAR30Amount: 1500000, PAR90Loans: 1, PAR90Amount: 450000}),
+			want: []string{"10", "9000000.00", "2", "1500000.00", "16.67", "1", "450000.00", "5.00"},
+		},
+		{
+			name: "investor return",
+			rows: InvestorReturnReportCSV([]models.InvestorReturnReport{{InvestorID: 7, InvestorName: "Ayu, Putri", Investments: 2, InvestedAmount: 3000000, RepaidPrincipal: 1000000, Payouts: 1080000}}),
+			want: []string{"7", "Ayu, Putri", "2", "3000000.00", "1000000.00", "1080000.00", "0.00", "80000.00", "2.67"},
+		},
+		{
+			name: "branch",
+			rows: BranchReportCSV([]models.BranchReport{{Region: "Bogor", Loans: 5, DisbursedLoans: 4, DisbursedAmount: 1000000, Outstanding: 800000, PAR30Amount: 100000, RepaidLoans: 1}}),
+			want: []string{"Bogor", "5", "4", "1000000.00", "800000.00", "100000.00", "12.50", "0.00", "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.rows) != 2 {
+				t.Fatalf("got %d rows, want the header and one line", len(tt.rows))
+			}
+
+			header, row := tt.rows[0], tt.rows[1]
+			if len(row) != len(header) {
+				t.Fatalf("row has %d columns, header %d", len(row), len(header))
+			}
+
+			for index, value := range row {
+				if value != tt.want[index] {
+					t.Errorf("%s = %q, want %q", header[index], value, tt.want[index])
+				}
+			}
+		})
+	}
+}
+
+func TestReportCSVWithoutLines(t *testing.T) {
+	rows := StatusReportCSV(nil)
+	if len(rows) != 1 || rows[0][0] != "status" {
+		t.Errorf("rows = %v, want only the header", rows)
+	}
+}
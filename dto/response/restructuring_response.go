@@ -0,0 +1,78 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type restructuringDetail struct {
+	ID                string     `json:"id"`
+	LoanID            string     `json:"loan_id"`
+	Status            string     `json:"status"`
+	ExtraInstallments int        `json:"extra_installments"`
+	HolidayPeriods    int        `json:"holiday_periods"`
+	Reason            string     `json:"reason"`
+	RequestedBy       uint       `json:"requested_by"`
+	DecidedBy         *uint      `json:"decided_by,omitempty"`
+	DecisionNote      string     `json:"decision_note,omitempty"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func RestructuringDetailResponse(restructuring *models.Restructuring) restructuringDetail {
+	return restructuringDetail{
+		ID:                restructuring.UUID.String(),
+		LoanID:            restructuring.Loan.UUID.String(),
+		Status:            string(restructuring.Status),
+		ExtraInstallments: restructuring.ExtraInstallments,
+		HolidayPeriods:    restructuring.HolidayPeriods,
+		Reason:            restructuring.Reason,
+		RequestedBy:       restructuring.RequestedBy,
+		DecidedBy:         restructuring.DecidedBy,
+		DecisionNote:      restructuring.DecisionNote,
+		DecidedAt:         restructuring.DecidedAt,
+		CreatedAt:         restructuring.CreatedAt,
+	}
+}
+
+func RestructuringListResponse(restructurings *[]models.Restructuring) []restructuringDetail {
+	var responses = make([]restructuringDetail, 0)
+	for _, restructuring := range *restructurings {
+		responses = append(responses, RestructuringDetailResponse(&restructuring))
+	}
+	return responses
+}
+
+type investmentLoss struct {
+	InvestorID uint    `json:"investor_id"`
+	Amount     float64 `json:"amount"`
+}
+
+type writeOffDetail struct {
+	ID                   string           `json:"id"`
+	Reason               string           `json:"reason"`
+	OutstandingPrincipal float64          `json:"outstanding_principal"`
+	OutstandingInterest  float64          `json:"outstanding_interest"`
+	Losses               []investmentLoss `json:"losses"`
+	CreatedAt            time.Time        `json:"created_at"`
+}
+
+func WriteOffDetailResponse(writeOff *models.WriteOff) writeOffDetail {
+	losses := make([]investmentLoss, 0, len(writeOff.Losses))
+	for _, loss := range writeOff.Losses {
+		losses = append(losses, investmentLoss{
+			InvestorID: loss.InvestorID,
+			Amount:     loss.Amount,
+		})
+	}
+
+	return writeOffDetail{
+		ID:                   writeOff.UUID.String(),
+		Reason:               writeOff.Reason,
+		OutstandingPrincipal: writeOff.OutstandingPrincipal,
+		OutstandingInterest:  writeOff.OutstandingInterest,
+		Losses:               losses,
+		CreatedAt:            writeOff.CreatedAt,
+	}
+}
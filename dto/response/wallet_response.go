@@ -0,0 +1,57 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type walletDetail struct {
+	ID               string  `json:"id"`
+	Balance          float64 `json:"balance"`
+	ReservedBalance  float64 `json:"reserved_balance"`
+	AvailableBalance float64 `json:"available_balance"`
+}
+
+func WalletDetailResponse(wallet *models.Wallet) walletDetail {
+	return walletDetail{
+		ID:               wallet.UUID.String(),
+		Balance:          wallet.Balance,
+		ReservedBalance:  wallet.ReservedBalance,
+		AvailableBalance: wallet.AvailableBalance(),
+	}
+}
+
+type walletTransactionDetail struct {
+	ID                   string    `json:"id"`
+	Type                 string    `json:"type"`
+	Status               string    `json:"status"`
+	Amount               float64   `json:"amount"`
+	BalanceAfter         float64   `json:"balance_after"`
+	ReservedBalanceAfter float64   `json:"reserved_balance_after"`
+	Reference            string    `json:"reference,omitempty"`
+	FailureReason        string    `json:"failure_reason,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+func WalletTransactionDetailResponse(transaction *models.WalletTransaction) walletTransactionDetail {
+	return walletTransactionDetail{
+		ID:                   transaction.UUID.String(),
+		Type:                 string(transaction.Type),
+		Status:               string(transaction.Status),
+		Amount:               transaction.Amount,
+		BalanceAfter:         transaction.BalanceAfter,
+		ReservedBalanceAfter: transaction.ReservedBalanceAfter,
+		Reference:            transaction.Reference,
+		FailureReason:        transaction.FailureReason,
+		CreatedAt:            transaction.CreatedAt,
+	}
+}
+
+func WalletTransactionListResponse(transactions *[]models.WalletTransaction) []walletTransactionDetail {
+	var responses = make([]walletTransactionDetail, 0)
+	for _, transaction := range *transactions {
+		responses = append(responses, WalletTransactionDetailResponse(&transaction))
+	}
+	return responses
+}
@@ -0,0 +1,83 @@
+package dto_response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type webhookDetail struct {
+	ID         string     `json:"id"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	Secret     string     `json:"secret,omitempty"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// WebhookDetailResponse only exposes the secret when withSecret is set, so it
+// is shown once on creation and never again in listings.
+func WebhookDetailResponse(subscription *models.WebhookSubscription, withSecret bool) webhookDetail {
+	response := webhookDetail{
+		ID:         subscription.UUID.String(),
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+
+	if withSecret {
+		response.Secret = subscription.Secret
+	}
+
+	return response
+}
+
+func WebhookListResponse(subscriptions *[]models.WebhookSubscription) []webhookDetail {
+	var responses = make([]webhookDetail, 0)
+	for _, subscription := range *subscriptions {
+		responses = append(responses, WebhookDetailResponse(&subscription, false))
+	}
+	return responses
+}
+
+type webhookDelivery struct {
+	ID           string          `json:"id"`
+	EventID      string          `json:"event_id"`
+	EventType    string          `json:"event_type"`
+	Payload      json.RawMessage `json:"payload"`
+	Attempt      int             `json:"attempt"`
+	StatusCode   int             `json:"status_code"`
+	ResponseBody string          `json:"response_body"`
+	Error        string          `json:"error"`
+	Success      bool            `json:"success"`
+	DurationMs   int64           `json:"duration_ms"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+func WebhookDeliveryResponse(delivery *models.WebhookDelivery) webhookDelivery {
+	return webhookDelivery{
+		ID:           delivery.UUID.String(),
+		EventID:      delivery.EventID.String(),
+		EventType:    delivery.EventType,
+		Payload:      delivery.Payload,
+		Attempt:      delivery.Attempt,
+		StatusCode:   delivery.StatusCode,
+		ResponseBody: delivery.ResponseBody,
+		Error:        delivery.Error,
+		Success:      delivery.Success,
+		DurationMs:   delivery.DurationMs,
+		CreatedAt:    delivery.CreatedAt,
+	}
+}
+
+func WebhookDeliveryListResponse(deliveries *[]models.WebhookDelivery) []webhookDelivery {
+	var responses = make([]webhookDelivery, 0)
+	for _, delivery := range *deliveries {
+		responses = append(responses, WebhookDeliveryResponse(&delivery))
+	}
+	return responses
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type auditLogHandler struct {
+	auditLogUsecase usecases.AuditLogUsecaseInterface
+}
+
+func NewAuditLogHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	auditLogUsecase usecases.AuditLogUsecaseInterface,
+) {
+	handler := &auditLogHandler{
+		auditLogUsecase: auditLogUsecase,
+	}
+
+	auditLogGroup := e.Group("/audit-logs", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Admin User
+	auditLogGroup.GET("", handler.list)
+	auditLogGroup.GET("/verify", handler.verify)
+}
+
+func (h *auditLogHandler) list(ctx echo.Context) error {
+	dto := dto_request.AuditLogListDTO{
+		Page:       ctx.QueryParam("page"),
+		PerPage:    ctx.QueryParam("per_page"),
+		Sort:       ctx.QueryParam("sort"),
+		ActorID:    ctx.QueryParam("actor_id"),
+		Action:     ctx.QueryParam("action"),
+		EntityType: ctx.QueryParam("entity_type"),
+		EntityID:   ctx.QueryParam("entity_id"),
+		LoanID:     ctx.QueryParam("loan_id"),
+		From:       ctx.QueryParam("from"),
+		To:         ctx.QueryParam("to"),
+	}
+
+	logs, count, err := h.auditLogUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Audit Log List",
+		Data:    dto_response.AuditLogListResponse(logs),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *auditLogHandler) verify(ctx echo.Context) error {
+	verification, err := h.auditLogUsecase.Verify(ctx.Request().Context())
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Audit Log Verification",
+		Data:    dto_response.AuditVerificationResponse(verification),
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type autoInvestPlanHandler struct {
+	autoInvestPlanUsecase usecases.AutoInvestPlanUsecaseInterface
+}
+
+func NewAutoInvestPlanHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	autoInvestPlanUsecase usecases.AutoInvestPlanUsecaseInterface,
+) {
+	handler := &autoInvestPlanHandler{
+		autoInvestPlanUsecase: autoInvestPlanUsecase,
+	}
+
+	autoInvestPlanGroup := e.Group("/auto-invest-plans", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Investor User
+	autoInvestPlanGroup.GET("", handler.list)
+	autoInvestPlanGroup.POST("", handler.create)
+	autoInvestPlanGroup.PUT("/:id", handler.update)
+	autoInvestPlanGroup.DELETE("/:id", handler.delete)
+}
+
+func (h *autoInvestPlanHandler) create(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.CreateAutoInvestPlanDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.InvestorID = context.ID
+
+	plan, err := h.autoInvestPlanUsecase.Create(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Auto Invest Plan Created",
+		Data:    dto_response.AutoInvestPlanDetailResponse(plan),
+	})
+}
+
+func (h *autoInvestPlanHandler) list(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.AutoInvestPlanListDTO{
+		InvestorID: context.ID,
+		Page:       ctx.QueryParam("page"),
+		PerPage:    ctx.QueryParam("per_page"),
+	}
+
+	plans, count, err := h.autoInvestPlanUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Auto Invest Plan List",
+		Data:    dto_response.AutoInvestPlanListResponse(plans),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *autoInvestPlanHandler) update(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.UpdateAutoInvestPlanDTO{}
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.InvestorID = context.ID
+	dto.AutoInvestPlanID = ctx.Param("id")
+
+	plan, err := h.autoInvestPlanUsecase.Update(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Auto Invest Plan Updated",
+		Data:    dto_response.AutoInvestPlanDetailResponse(plan),
+	})
+}
+
+func (h *autoInvestPlanHandler) delete(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.DeleteAutoInvestPlanDTO{
+		InvestorID:       context.ID,
+		AutoInvestPlanID: ctx.Param("id"),
+	}
+
+	err := h.autoInvestPlanUsecase.Delete(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Auto Invest Plan Deleted",
+	})
+}
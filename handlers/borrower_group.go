@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type borrowerGroupHandler struct {
+	borrowerGroupUsecase usecases.BorrowerGroupUsecaseInterface
+	groupLoanUsecase     usecases.GroupLoanUsecaseInterface
+}
+
+func NewBorrowerGroupHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	borrowerGroupUsecase usecases.BorrowerGroupUsecaseInterface,
+	groupLoanUsecase usecases.GroupLoanUsecaseInterface,
+) {
+	handler := &borrowerGroupHandler{
+		borrowerGroupUsecase: borrowerGroupUsecase,
+		groupLoanUsecase:     groupLoanUsecase,
+	}
+
+	borrowerGroupGroup := e.Group("/borrower-groups", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Field Officer and Admin User
+	borrowerGroupGroup.GET("", handler.list)
+	borrowerGroupGroup.POST("", handler.create)
+	borrowerGroupGroup.GET("/:id", handler.detail)
+	borrowerGroupGroup.POST("/:id/members", handler.addMember)
+	borrowerGroupGroup.DELETE("/:id/members/:borrower_id", handler.removeMember)
+	borrowerGroupGroup.PUT("/:id/leader", handler.changeLeader)
+
+	// For Field Officer User
+	borrowerGroupGroup.POST("/:id/loans", handler.proposeLoan)
+}
+
+func (h *borrowerGroupHandler) create(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.CreateBorrowerGroupDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.UserID = context.ID
+	dto.Role = context.Role
+
+	group, err := h.borrowerGroupUsecase.Create(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Borrower Group Created",
+		Data:    dto_response.BorrowerGroupDetailResponse(group),
+	})
+}
+
+func (h *borrowerGroupHandler) list(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.BorrowerGroupListDTO{
+		UserID:  context.ID,
+		Role:    context.Role,
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+		Region:  ctx.QueryParam("region"),
+	}
+
+	groups, count, err := h.borrowerGroupUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Borrower Group List",
+		Data:    dto_response.BorrowerGroupListResponse(groups),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *borrowerGroupHandler) detail(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.BorrowerGroupDetailDTO{
+		GroupID: ctx.Param("id"),
+		UserID:  context.ID,
+		Role:    context.Role,
+	}
+
+	group, err := h.borrowerGroupUsecase.Detail(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Borrower Group Detail",
+		Data:    dto_response.BorrowerGroupDetailResponse(group),
+	})
+}
+
+func (h *borrowerGroupHandler) addMember(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.BorrowerGroupMemberDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.GroupID = ctx.Param("id")
+	dto.UserID = context.ID
+	dto.Role = context.Role
+
+	group, err := h.borrowerGroupUsecase.AddMember(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Group Member Added",
+		Data:    dto_response.BorrowerGroupDetailResponse(group),
+	})
+}
+
+func (h *borrowerGroupHandler) removeMember(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	borrowerID, err := strconv.ParseUint(ctx.Param("borrower_id"), 10, 64)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	dto := dto_request.BorrowerGroupMemberDTO{
+		GroupID:    ctx.Param("id"),
+		UserID:     context.ID,
+		Role:       context.Role,
+		BorrowerID: uint(borrowerID),
+	}
+
+	group, err := h.borrowerGroupUsecase.RemoveMember(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Group Member Removed",
+		Data:    dto_response.BorrowerGroupDetailResponse(group),
+	})
+}
+
+func (h *borrowerGroupHandler) changeLeader(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.BorrowerGroupMemberDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.GroupID = ctx.Param("id")
+	dto.UserID = context.ID
+	dto.Role = context.Role
+
+	group, err := h.borrowerGroupUsecase.ChangeLeader(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Group Leader Changed",
+		Data:    dto_response.BorrowerGroupDetailResponse(group),
+	})
+}
+
+func (h *borrowerGroupHandler) proposeLoan(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.ProposeGroupLoanDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.GroupID = ctx.Param("id")
+	dto.FieldOfficerID = context.ID
+
+	groupLoan, err := h.groupLoanUsecase.Propose(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Group Loan Proposed",
+		Data:    dto_response.GroupLoanDetailResponse(groupLoan),
+	})
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type collectionHandler struct {
+	collectionUsecase usecases.CollectionUsecaseInterface
+}
+
+func NewCollectionHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	collectionUsecase usecases.CollectionUsecaseInterface,
+) {
+	handler := &collectionHandler{
+		collectionUsecase: collectionUsecase,
+	}
+
+	collectionGroup := e.Group("/collections", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Field Officer and Admin User
+	collectionGroup.GET("", handler.workList)
+
+	loanGroup := e.Group("/loans", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Supervisor User
+	loanGroup.POST("/:id/write-off", handler.writeOff)
+
+	// For Borowwer, Field Officer and Admin User
+	loanGroup.GET("/:id/payoff-quote", handler.payoffQuote)
+
+	// For Borowwer User
+	loanGroup.POST("/:id/settle", handler.settle)
+}
+
+// workList lists the overdue loans, the longest overdue first
+func (h *collectionHandler) workList(ctx echo.Context) error {
+	dto := dto_request.CollectionListDTO{
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+		Bucket:  ctx.QueryParam("bucket"),
+	}
+
+	loans, count, err := h.collectionUsecase.WorkList(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Collection List",
+		Data:    dto_response.CollectionListResponse(loans),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *collectionHandler) writeOff(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.WriteOffLoanDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.LoanID = ctx.Param("id")
+	dto.SupervisorID = context.ID
+
+	writeOff, err := h.collectionUsecase.WriteOff(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan Written Off",
+		Data:    dto_response.WriteOffDetailResponse(writeOff),
+	})
+}
+
+func (h *collectionHandler) payoffQuote(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.PayoffQuoteDTO{
+		LoanID: ctx.Param("id"),
+		UserID: context.ID,
+		Role:   context.Role,
+		Date:   ctx.QueryParam("date"),
+	}
+
+	quote, err := h.collectionUsecase.PayoffQuote(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Payoff Quote",
+		Data:    dto_response.PayoffQuoteResponse(quote),
+	})
+}
+
+func (h *collectionHandler) settle(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.SettleLoanDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.LoanID = ctx.Param("id")
+	dto.BorrowerID = context.ID
+
+	loan, err := h.collectionUsecase.Settle(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan Settled",
+		Data:    dto_response.LoanDetailResponse(loan),
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type documentHandler struct {
+	documentUsecase usecases.DocumentUsecaseInterface
+}
+
+func NewDocumentHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	documentUsecase usecases.DocumentUsecaseInterface,
+) {
+	handler := &documentHandler{
+		documentUsecase: documentUsecase,
+	}
+
+	documentGroup := e.Group("/documents", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For every user involved in the loan
+	documentGroup.GET("/:id", handler.download)
+}
+
+func (h *documentHandler) download(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.DownloadDocumentDTO{
+		DocumentID: ctx.Param("id"),
+		UserID:     context.ID,
+		Role:       context.Role,
+	}
+
+	document, file, url, err := h.documentUsecase.Download(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	if url != "" {
+		return ctx.Redirect(http.StatusFound, url)
+	}
+	defer file.Close()
+
+	response := ctx.Response()
+	response.Header().Set(echo.HeaderContentType, document.ContentType)
+	response.Header().Set(echo.HeaderContentDisposition, "inline")
+	response.Header().Set("X-Checksum-Sha256", document.Checksum)
+
+	// ServeContent takes care of Range, If-Range and conditional requests
+	http.ServeContent(response, ctx.Request(), "", document.CreatedAt, file)
+
+	return nil
+}
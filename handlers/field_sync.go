@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+// maxBundleSize bounds the bundle body read before the signature is checked
+const maxBundleSize = 4 << 20
+
+type fieldSyncHandler struct {
+	fieldSyncUsecase usecases.FieldSyncUsecaseInterface
+}
+
+func NewFieldSyncHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	fieldSyncUsecase usecases.FieldSyncUsecaseInterface,
+) {
+	handler := &fieldSyncHandler{
+		fieldSyncUsecase: fieldSyncUsecase,
+	}
+
+	fieldSyncGroup := e.Group("/field-sync", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Field Officer User
+	fieldSyncGroup.GET("/key", handler.key)
+	fieldSyncGroup.POST("/collections", handler.syncCollections)
+}
+
+func (h *fieldSyncHandler) key(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	key, err := h.fieldSyncUsecase.Key(ctx.Request().Context(), context.ID)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Field Sync Key",
+		Data:    map[string]string{"key": key},
+	})
+}
+
+func (h *fieldSyncHandler) syncCollections(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxBundleSize))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	dto := dto_request.SyncCollectionsDTO{
+		FieldOfficerID: context.ID,
+		Body:           body,
+		Signature:      ctx.Request().Header.Get("X-Bundle-Signature"),
+	}
+
+	report, err := h.fieldSyncUsecase.Sync(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Collections Synced",
+		Data:    dto_response.SyncReportResponse(report),
+	})
+}
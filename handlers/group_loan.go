@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type groupLoanHandler struct {
+	groupLoanUsecase usecases.GroupLoanUsecaseInterface
+}
+
+func NewGroupLoanHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	groupLoanUsecase usecases.GroupLoanUsecaseInterface,
+) {
+	handler := &groupLoanHandler{
+		groupLoanUsecase: groupLoanUsecase,
+	}
+
+	groupLoanGroup := e.Group("/group-loans", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Field Validator, Field Officer and Admin User
+	groupLoanGroup.GET("/:id", handler.detail)
+
+	// For Field Validator User
+	groupLoanGroup.POST("/:id/approve", handler.approve, middleware.UploadLimit())
+
+	// For Field Officer User
+	groupLoanGroup.POST("/:id/collections", handler.collect)
+}
+
+func (h *groupLoanHandler) detail(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.GroupLoanDetailDTO{
+		GroupLoanID: ctx.Param("id"),
+		UserID:      context.ID,
+		Role:        context.Role,
+	}
+
+	groupLoan, err := h.groupLoanUsecase.Detail(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Group Loan Detail",
+		Data:    dto_response.GroupLoanDetailResponse(groupLoan),
+	})
+}
+
+func (h *groupLoanHandler) approve(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.ApproveGroupLoanDTO{
+		GroupLoanID:      ctx.Param("id"),
+		FieldValidatorID: context.ID,
+	}
+
+	form, err := parseUploadForm(ctx)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	dto.Documents = collectDocuments(form, models.DocumentTypeApprovalProof)
+	if len(dto.Documents) == 0 {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "No Prove Uploaded",
+		})
+	}
+
+	dto.Visit, err = collectFieldVisit(form)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	groupLoan, err := h.groupLoanUsecase.Approve(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Group Loan Approved",
+		Data:    dto_response.GroupLoanDetailResponse(groupLoan),
+	})
+}
+
+func (h *groupLoanHandler) collect(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.CollectGroupLoanDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.GroupLoanID = ctx.Param("id")
+	dto.FieldOfficerID = context.ID
+
+	collection, err := h.groupLoanUsecase.Collect(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Group Collection Recorded",
+		Data:    dto_response.GroupCollectionDetailResponse(collection),
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gotidy/ptr"
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type investmentHandler struct {
+	loanUseCase usecases.LoanUsecaseInterface
+}
+
+func NewInvestmentHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	loanUseCase usecases.LoanUsecaseInterface,
+) {
+	handler := &investmentHandler{
+		loanUseCase: loanUseCase,
+	}
+
+	investmentGroup := e.Group("/investments", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Investor and Admin User
+	investmentGroup.GET("", handler.list)
+}
+
+func (h *investmentHandler) list(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.InvestmentListDTO{
+		UserID:  context.ID,
+		Role:    context.Role,
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+		Sort:    ctx.QueryParam("sort"),
+		LoanID:  ctx.QueryParam("loan_id"),
+	}
+
+	// Any cursor param, even empty for the first page, switches to cursor paging
+	if ctx.QueryParams().Has("cursor") {
+		dto.Cursor = ptr.Of(ctx.QueryParam("cursor"))
+	}
+
+	investments, meta, err := h.loanUseCase.ListInvestments(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Investment List",
+		Data:    dto_response.InvestmentListResponse(investments),
+		Meta:    meta,
+	})
+}
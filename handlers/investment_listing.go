@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type investmentListingHandler struct {
+	investmentMarketUsecase usecases.InvestmentMarketUsecaseInterface
+}
+
+func NewInvestmentListingHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	investmentMarketUsecase usecases.InvestmentMarketUsecaseInterface,
+) {
+	handler := &investmentListingHandler{
+		investmentMarketUsecase: investmentMarketUsecase,
+	}
+
+	listingGroup := e.Group("/investment-listings", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Investor User
+	listingGroup.GET("", handler.list)
+	listingGroup.POST("", handler.create)
+	listingGroup.POST("/:id/buy", handler.buy)
+	listingGroup.POST("/:id/cancel", handler.cancel)
+
+	// For Investor and Admin User
+	listingGroup.GET("/transfers", handler.listTransfers)
+}
+
+func (h *investmentListingHandler) list(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.InvestmentListingListDTO{
+		UserID:  context.ID,
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+		LoanID:  ctx.QueryParam("loan_id"),
+		Mine:    ctx.QueryParam("mine") == "true",
+	}
+
+	listings, count, err := h.investmentMarketUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Investment Listing List",
+		Data:    dto_response.InvestmentListingListResponse(listings),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *investmentListingHandler) create(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.CreateInvestmentListingDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.InvestorID = context.ID
+
+	listing, err := h.investmentMarketUsecase.Create(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Investment Listed",
+		Data:    dto_response.InvestmentListingDetailResponse(listing),
+	})
+}
+
+func (h *investmentListingHandler) buy(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.BuyInvestmentListingDTO{
+		InvestorID: context.ID,
+		ListingID:  ctx.Param("id"),
+	}
+
+	transfer, err := h.investmentMarketUsecase.Buy(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Investment Bought",
+		Data:    dto_response.InvestmentTransferDetailResponse(transfer, context.ID),
+	})
+}
+
+func (h *investmentListingHandler) cancel(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.CancelInvestmentListingDTO{
+		InvestorID: context.ID,
+		ListingID:  ctx.Param("id"),
+	}
+
+	listing, err := h.investmentMarketUsecase.Cancel(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Investment Listing Cancelled",
+		Data:    dto_response.InvestmentListingDetailResponse(listing),
+	})
+}
+
+func (h *investmentListingHandler) listTransfers(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.InvestmentTransferListDTO{
+		UserID:  context.ID,
+		Role:    context.Role,
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+	}
+
+	transfers, count, err := h.investmentMarketUsecase.ListTransfers(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Investment Transfer List",
+		Data:    dto_response.InvestmentTransferListResponse(transfers, context.ID),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
@@ -2,13 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"mime/multipart"
 	"net/http"
-	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gotidy/ptr"
 	"github.com/labstack/echo/v4"
 	dto_request "github.com/peang/amartha-loan-service/dto/request"
 	dto_response "github.com/peang/amartha-loan-service/dto/response"
 	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/models"
 	"github.com/peang/amartha-loan-service/usecases"
 	"github.com/peang/amartha-loan-service/utils"
 )
@@ -32,20 +39,32 @@ func NewLoanHandler(
 	loanGroup.POST("/propose", handler.propose)
 
 	// For Field Validator User
-	loanGroup.POST("/:id/approve", handler.approve)
+	loanGroup.POST("/:id/approve", handler.approve, middleware.UploadLimit())
+
+	// For Supervisor and Credit Committee User
+	loanGroup.GET("/pending-approval", handler.getListPendingApproval)
+	loanGroup.POST("/:id/sign-off", handler.signOff)
 
 	// For Investor user
 	loanGroup.GET("/available", handler.getListAvailable)
 	loanGroup.POST("/:id/invest", handler.invest)
 
 	// For Field Officer user
-	loanGroup.POST("/:id/disburse", handler.disburse)
+	loanGroup.POST("/:id/disburse", handler.disburse, middleware.UploadLimit())
+
+	// For Borowwer and Admin User
+	loanGroup.POST("/:id/cancel", handler.cancel)
+
+	// For Borowwer, Field Officer and Admin User
+	loanGroup.GET("/:id", handler.detail)
 }
 
 func (h *loanHandler) propose(ctx echo.Context) error {
 	context := ctx.Get("payload").(utils.Payload)
 	var payload struct {
-		Amount float64 `validate:"required"`
+		ProductID string  `json:"product_id" validate:"required"`
+		Amount    float64 `validate:"required"`
+		Tenor     int     `validate:"required"`
 	}
 
 	// This also could use Validator v10 to validate
@@ -59,19 +78,26 @@ func (h *loanHandler) propose(ctx echo.Context) error {
 
 	dto := dto_request.ProposeLoanDTO{
 		BorowwerID: context.ID,
+		ProductID:  payload.ProductID,
 		Amount:     payload.Amount,
+		Tenor:      payload.Tenor,
 	}
 
 	loan, err := h.loanUseCase.Propose(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, utils.Error{
-			Code:  http.StatusInternalServerError,
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
 			Error: err.Error(),
 		})
 	}
 
+	message := "Loan Created"
+	if loan.Status == models.LoanStatusRejected {
+		message = "Loan Rejected"
+	}
+
 	return ctx.JSON(http.StatusCreated, utils.Response{
-		Message: "Loan Created",
+		Message: message,
 		Data:    dto_response.LoanDetailResponse(loan),
 	})
 }
@@ -84,29 +110,27 @@ func (h *loanHandler) approve(ctx echo.Context) error {
 		FieldValidatorID: context.ID,
 	}
 
-	form, err := ctx.MultipartForm()
+	form, err := parseUploadForm(ctx)
 	if err != nil {
-		return err
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
 	}
 
-	files := form.File["file"]
-	if len(files) == 0 {
+	dto.Documents = collectDocuments(form, models.DocumentTypeApprovalProof)
+	if len(dto.Documents) == 0 {
 		return ctx.JSON(http.StatusBadRequest, utils.Error{
 			Code:  http.StatusBadRequest,
 			Error: "No Prove Uploaded",
 		})
 	}
-	dto.ProveImage = files[0]
 
-	allowedExtensions := map[string]bool{
-		".pdf":  true,
-		".jpeg": true,
-	}
-	ext := filepath.Ext(dto.ProveImage.Filename)
-	if !allowedExtensions[ext] {
-		return ctx.JSON(http.StatusBadRequest, utils.Error{
-			Code:  http.StatusBadRequest,
-			Error: "FILE TYPE NOT ALLOWED",
+	dto.Visit, err = collectFieldVisit(form)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
 		})
 	}
 
@@ -124,27 +148,110 @@ func (h *loanHandler) approve(ctx echo.Context) error {
 	})
 }
 
-func (h *loanHandler) getListAvailable(ctx echo.Context) error {
-	dto := dto_request.ApprovedLoanListDTO{
+func (h *loanHandler) signOff(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var payload struct {
+		Note string `json:"note"`
+	}
+
+	// The note is optional, an empty body is fine
+	if ctx.Request().ContentLength != 0 {
+		err := json.NewDecoder(ctx.Request().Body).Decode(&payload)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, utils.Error{
+				Code:  http.StatusBadRequest,
+				Error: "Invalid Payload",
+			})
+		}
+	}
+
+	dto := dto_request.SignOffLoanDTO{
+		LoanID:     ctx.Param("id"),
+		ApproverID: context.ID,
+		Role:       context.Role,
+		Note:       payload.Note,
+	}
+
+	loan, err := h.loanUseCase.SignOff(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	message := "Loan Signed Off"
+	if loan.Status == models.LoanStatusApproved {
+		message = "Loan Approved"
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: message,
+		Data:    dto_response.LoanDetailResponse(loan),
+	})
+}
+
+func (h *loanHandler) getListPendingApproval(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.PendingApprovalListDTO{
+		Role:    context.Role,
 		Page:    ctx.QueryParam("page"),
 		PerPage: ctx.QueryParam("per_page"),
 	}
 
-	loans, count, err := h.loanUseCase.GetAvailableLoans(ctx.Request().Context(), &dto)
+	loans, count, err := h.loanUseCase.PendingApprovals(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, utils.Error{
-			Code:  http.StatusInternalServerError,
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
 			Error: err.Error(),
 		})
 	}
 
 	return ctx.JSON(http.StatusOK, utils.Response{
-		Message: "Loan List",
+		Message: "Pending Approval Loan List",
 		Data:    dto_response.LoanListResponse(loans),
 		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
 	})
 }
 
+func (h *loanHandler) getListAvailable(ctx echo.Context) error {
+	dto := dto_request.ApprovedLoanListDTO{
+		Page:         ctx.QueryParam("page"),
+		PerPage:      ctx.QueryParam("per_page"),
+		Sort:         ctx.QueryParam("sort"),
+		MinAmount:    ctx.QueryParam("min_amount"),
+		MaxAmount:    ctx.QueryParam("max_amount"),
+		MinRate:      ctx.QueryParam("min_rate"),
+		MaxRate:      ctx.QueryParam("max_rate"),
+		MinRemaining: ctx.QueryParam("min_remaining_amount"),
+		MaxRemaining: ctx.QueryParam("max_remaining_amount"),
+		Tenor:        ctx.QueryParam("tenor"),
+		Sector:       ctx.QueryParam("sector"),
+		Region:       ctx.QueryParam("region"),
+	}
+
+	// Any cursor param, even empty for the first page, switches to cursor paging
+	if ctx.QueryParams().Has("cursor") {
+		dto.Cursor = ptr.Of(ctx.QueryParam("cursor"))
+	}
+
+	loans, meta, err := h.loanUseCase.GetAvailableLoans(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan List",
+		Data:    dto_response.LoanListResponse(loans),
+		Meta:    meta,
+	})
+}
+
 func (h *loanHandler) invest(ctx echo.Context) error {
 	context := ctx.Get("payload").(utils.Payload)
 
@@ -169,8 +276,8 @@ func (h *loanHandler) invest(ctx echo.Context) error {
 
 	investment, err := h.loanUseCase.Invest(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, utils.Error{
-			Code:  http.StatusInternalServerError,
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
 			Error: err.Error(),
 		})
 	}
@@ -189,33 +296,176 @@ func (h *loanHandler) disburse(ctx echo.Context) error {
 		FieldOfficerID: context.ID,
 	}
 
-	form, err := ctx.MultipartForm()
+	form, err := parseUploadForm(ctx)
 	if err != nil {
-		return err
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
 	}
 
-	files := form.File["file"]
-	if len(files) == 0 {
+	dto.Documents = collectDocuments(form, models.DocumentTypeDisbursementAgreement)
+	if len(dto.Documents) == 0 {
 		return ctx.JSON(http.StatusBadRequest, utils.Error{
 			Code:  http.StatusBadRequest,
 			Error: "No Prove Uploaded",
 		})
 	}
-	dto.AggreementLetter = files[0]
 
-	allowedExtensions := map[string]bool{
-		".pdf":  true,
-		".jpeg": true,
+	loan, err := h.loanUseCase.Disburse(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
 	}
-	ext := filepath.Ext(dto.AggreementLetter.Filename)
-	if !allowedExtensions[ext] {
-		return ctx.JSON(http.StatusBadRequest, utils.Error{
-			Code:  http.StatusBadRequest,
-			Error: "FILE TYPE NOT ALLOWED",
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Loan Disbursed",
+		Data:    dto_response.LoanDetailResponse(loan),
+	})
+}
+
+// parseUploadForm reads the multipart body, a body cut off by the
+// UploadLimit middleware comes back as file_too_large.
+func parseUploadForm(ctx echo.Context) (*multipart.Form, error) {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, errors.New("file_too_large")
+		}
+
+		return nil, err
+	}
+
+	return form, nil
+}
+
+// collectDocuments reads every file in the form, the field name being the
+// document type, while the legacy "file" field keeps its original meaning.
+// Type, size and name are checked against the actual bytes by the file service.
+func collectDocuments(form *multipart.Form, legacyType models.DocumentType) []dto_request.DocumentUploadDTO {
+	fields := make([]string, 0, len(form.File))
+	for field := range form.File {
+		fields = append(fields, field)
+	}
+
+	// Keep the order stable, the legacy field first, as the first file becomes the primary proof
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i] == "file" || fields[j] == "file" {
+			return fields[i] == "file"
+		}
+		return fields[i] < fields[j]
+	})
+
+	var documents []dto_request.DocumentUploadDTO
+	for _, field := range fields {
+		documentType := models.DocumentType(field)
+		if field == "file" {
+			documentType = legacyType
+		}
+
+		for _, file := range form.File[field] {
+			documents = append(documents, dto_request.DocumentUploadDTO{
+				Type: documentType,
+				File: file,
+			})
+		}
+	}
+
+	return documents
+}
+
+// collectFieldVisit reads the position and time the field app sends along
+// with the approval evidence, they are optional but flagged when missing.
+func collectFieldVisit(form *multipart.Form) (dto_request.FieldVisitDTO, error) {
+	var visit dto_request.FieldVisitDTO
+
+	latitude, longitude := formValue(form, "latitude"), formValue(form, "longitude")
+	if latitude != "" || longitude != "" {
+		lat, err := strconv.ParseFloat(latitude, 64)
+		if err != nil {
+			return visit, errors.New("invalid_visit_location")
+		}
+
+		lon, err := strconv.ParseFloat(longitude, 64)
+		if err != nil {
+			return visit, errors.New("invalid_visit_location")
+		}
+
+		visit.Latitude, visit.Longitude = &lat, &lon
+	}
+
+	if visitedAt := formValue(form, "visited_at"); visitedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, visitedAt)
+		if err != nil {
+			return visit, errors.New("invalid_visit_time")
+		}
+
+		visit.VisitedAt = &parsed
+	}
+
+	return visit, nil
+}
+
+func formValue(form *multipart.Form, field string) string {
+	if values := form.Value[field]; len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+
+	return ""
+}
+
+func (h *loanHandler) cancel(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+
+	// The reason is optional, an empty body is fine
+	if ctx.Request().ContentLength != 0 {
+		err := json.NewDecoder(ctx.Request().Body).Decode(&payload)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, utils.Error{
+				Code:  http.StatusBadRequest,
+				Error: "Invalid Payload",
+			})
+		}
+	}
+
+	dto := dto_request.CancelLoanDTO{
+		LoanID: ctx.Param("id"),
+		UserID: context.ID,
+		Role:   context.Role,
+		Reason: payload.Reason,
+	}
+
+	loan, err := h.loanUseCase.Cancel(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
 		})
 	}
 
-	loan, err := h.loanUseCase.Disburse(ctx.Request().Context(), &dto)
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan Cancelled",
+		Data:    dto_response.LoanDetailResponse(loan),
+	})
+}
+
+func (h *loanHandler) detail(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.LoanDetailDTO{
+		LoanID: ctx.Param("id"),
+		UserID: context.ID,
+		Role:   context.Role,
+	}
+
+	loan, err := h.loanUseCase.Detail(ctx.Request().Context(), &dto)
 	if err != nil {
 		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
 			Code:  utils.GetErrorCode(err.Error()),
@@ -223,8 +473,8 @@ func (h *loanHandler) disburse(ctx echo.Context) error {
 		})
 	}
 
-	return ctx.JSON(http.StatusCreated, utils.Response{
-		Message: "Loan Disbursed",
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan Detail",
 		Data:    dto_response.LoanDetailResponse(loan),
 	})
 }
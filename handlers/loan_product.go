@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type loanProductHandler struct {
+	loanProductUsecase usecases.LoanProductUsecaseInterface
+}
+
+func NewLoanProductHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	loanProductUsecase usecases.LoanProductUsecaseInterface,
+) {
+	handler := &loanProductHandler{
+		loanProductUsecase: loanProductUsecase,
+	}
+
+	loanProductGroup := e.Group("/loan-products", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Borowwer and Admin User
+	loanProductGroup.GET("", handler.list)
+
+	// For Admin User
+	loanProductGroup.POST("", handler.create)
+	loanProductGroup.PUT("/:id", handler.update)
+}
+
+func (h *loanProductHandler) create(ctx echo.Context) error {
+	var dto dto_request.CreateLoanProductDTO
+
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	product, err := h.loanProductUsecase.Create(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Loan Product Created",
+		Data:    dto_response.LoanProductDetailResponse(product),
+	})
+}
+
+func (h *loanProductHandler) list(ctx echo.Context) error {
+	payload := ctx.Get("payload").(utils.Payload)
+
+	// Borrowers only get to pick from products that are open for proposals
+	dto := dto_request.LoanProductListDTO{
+		Page:       ctx.QueryParam("page"),
+		PerPage:    ctx.QueryParam("per_page"),
+		ActiveOnly: payload.Role != models.RoleAdmin,
+	}
+
+	products, count, err := h.loanProductUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan Product List",
+		Data:    dto_response.LoanProductListResponse(products),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *loanProductHandler) update(ctx echo.Context) error {
+	dto := dto_request.UpdateLoanProductDTO{}
+
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.LoanProductID = ctx.Param("id")
+
+	product, err := h.loanProductUsecase.Update(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loan Product Updated",
+		Data:    dto_response.LoanProductDetailResponse(product),
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+// maxCallbackSize bounds the callback body read before the signature is checked
+const maxCallbackSize = 1 << 20
+
+type paymentHandler struct {
+	paymentUsecase usecases.PaymentUsecaseInterface
+}
+
+func NewPaymentHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	paymentUsecase usecases.PaymentUsecaseInterface,
+) {
+	handler := &paymentHandler{
+		paymentUsecase: paymentUsecase,
+	}
+
+	// For Payment Gateway, authenticated by the callback signature
+	e.POST("/payments/callback", handler.callback)
+
+	loanGroup := e.Group("/loans", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Borowwer User
+	loanGroup.POST("/:id/virtual-account", handler.createVirtualAccount)
+
+	// For Borowwer and Admin User
+	loanGroup.GET("/:id/payments", handler.list)
+}
+
+func (h *paymentHandler) callback(ctx echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxCallbackSize))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	dto := dto_request.PaymentCallbackDTO{
+		Body:      body,
+		Timestamp: ctx.Request().Header.Get("X-Callback-Timestamp"),
+		Signature: ctx.Request().Header.Get("X-Callback-Signature"),
+	}
+
+	err = h.paymentUsecase.HandleCallback(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Callback Received",
+	})
+}
+
+func (h *paymentHandler) createVirtualAccount(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.CreateVirtualAccountDTO{
+		LoanID:     ctx.Param("id"),
+		BorrowerID: context.ID,
+	}
+
+	loan, err := h.paymentUsecase.CreateVirtualAccount(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Virtual Account Created",
+		Data:    dto_response.LoanDetailResponse(loan),
+	})
+}
+
+func (h *paymentHandler) list(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.PaymentListDTO{
+		LoanID:  ctx.Param("id"),
+		UserID:  context.ID,
+		Role:    context.Role,
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+	}
+
+	payments, count, err := h.paymentUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Payment List",
+		Data:    dto_response.PaymentListResponse(payments),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type rateCardHandler struct {
+	rateCardUsecase usecases.RateCardUsecaseInterface
+}
+
+func NewRateCardHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	rateCardUsecase usecases.RateCardUsecaseInterface,
+) {
+	handler := &rateCardHandler{
+		rateCardUsecase: rateCardUsecase,
+	}
+
+	rateCardGroup := e.Group("/rate-cards", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Admin User
+	rateCardGroup.POST("", handler.create)
+	rateCardGroup.GET("", handler.list)
+	rateCardGroup.PUT("/:id", handler.update)
+	rateCardGroup.DELETE("/:id", handler.deactivate)
+}
+
+func (h *rateCardHandler) create(ctx echo.Context) error {
+	payload := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.CreateRateCardDTO
+
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.AdminID = payload.ID
+
+	rateCard, err := h.rateCardUsecase.Create(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Rate Card Created",
+		Data:    dto_response.RateCardDetailResponse(rateCard),
+	})
+}
+
+func (h *rateCardHandler) list(ctx echo.Context) error {
+	dto := dto_request.RateCardListDTO{
+		Page:      ctx.QueryParam("page"),
+		PerPage:   ctx.QueryParam("per_page"),
+		Product:   ctx.QueryParam("product"),
+		RiskGrade: ctx.QueryParam("risk_grade"),
+		Active:    ctx.QueryParam("active"),
+	}
+
+	rateCards, count, err := h.rateCardUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Rate Card List",
+		Data:    dto_response.RateCardListResponse(rateCards),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *rateCardHandler) update(ctx echo.Context) error {
+	payload := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.UpdateRateCardDTO{}
+
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.RateCardID = ctx.Param("id")
+	dto.AdminID = payload.ID
+
+	rateCard, err := h.rateCardUsecase.Update(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Rate Card Updated",
+		Data:    dto_response.RateCardDetailResponse(rateCard),
+	})
+}
+
+func (h *rateCardHandler) deactivate(ctx echo.Context) error {
+	rateCard, err := h.rateCardUsecase.Deactivate(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Rate Card Deactivated",
+		Data:    dto_response.RateCardDetailResponse(rateCard),
+	})
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type reportHandler struct {
+	reportUsecase usecases.ReportUsecaseInterface
+}
+
+func NewReportHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	reportUsecase usecases.ReportUsecaseInterface,
+) {
+	handler := &reportHandler{
+		reportUsecase: reportUsecase,
+	}
+
+	reportGroup := e.Group("/reports", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Admin and Supervisor User
+	reportGroup.GET("/loans-by-status", handler.loansByStatus)
+	reportGroup.GET("/disbursements", handler.disbursements)
+	reportGroup.GET("/time-to-fund", handler.timeToFund)
+	reportGroup.GET("/funding-ratio", handler.funding)
+	reportGroup.GET("/portfolio-at-risk", handler.portfolioAtRisk)
+	reportGroup.GET("/investor-returns", handler.investorReturns)
+	reportGroup.GET("/branches", handler.branches)
+}
+
+func (h *reportHandler) loansByStatus(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := reportDTO(ctx)
+	reports, err := h.reportUsecase.LoansByStatus(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "loans-by-status", dto_response.StatusReportCSV(reports))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Loans By Status Report",
+		Data:    dto_response.StatusReportResponse(reports),
+	})
+}
+
+func (h *reportHandler) disbursements(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := dto_request.DisbursementReportDTO{
+		ReportDTO: reportDTO(ctx),
+		Period:    ctx.QueryParam("period"),
+	}
+	reports, err := h.reportUsecase.Disbursements(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "disbursements", dto_response.DisbursementReportCSV(reports))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Disbursement Report",
+		Data:    dto_response.DisbursementReportResponse(reports),
+	})
+}
+
+func (h *reportHandler) timeToFund(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := reportDTO(ctx)
+	report, err := h.reportUsecase.TimeToFund(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "time-to-fund", dto_response.TimeToFundReportCSV(report))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Time To Fund Report",
+		Data:    dto_response.TimeToFundReportResponse(report),
+	})
+}
+
+func (h *reportHandler) funding(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := reportDTO(ctx)
+	report, err := h.reportUsecase.Funding(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "funding-ratio", dto_response.FundingReportCSV(report))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Funding Ratio Report",
+		Data:    dto_response.FundingReportResponse(report),
+	})
+}
+
+func (h *reportHandler) portfolioAtRisk(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := reportDTO(ctx)
+	report, err := h.reportUsecase.PortfolioAtRisk(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "portfolio-at-risk", dto_response.PortfolioAtRiskReportCSV(report))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Portfolio At Risk Report",
+		Data:    dto_response.PortfolioAtRiskReportResponse(report),
+	})
+}
+
+func (h *reportHandler) investorReturns(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := dto_request.InvestorReturnReportDTO{
+		ReportDTO: reportDTO(ctx),
+		Page:      ctx.QueryParam("page"),
+		PerPage:   ctx.QueryParam("per_page"),
+		Sort:      ctx.QueryParam("sort"),
+	}
+	reports, count, err := h.reportUsecase.InvestorReturns(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "investor-returns", dto_response.InvestorReturnReportCSV(reports))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Investor Returns Report",
+		Data:    dto_response.InvestorReturnReportResponse(reports),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *reportHandler) branches(ctx echo.Context) error {
+	asCSV, err := reportAsCSV(ctx)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	dto := reportDTO(ctx)
+	reports, err := h.reportUsecase.Branches(ctx.Request().Context(), &dto)
+	if err != nil {
+		return reportError(ctx, err)
+	}
+
+	if asCSV {
+		return reportCSV(ctx, "branches", dto_response.BranchReportCSV(reports))
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Branch Performance Report",
+		Data:    dto_response.BranchReportResponse(reports),
+	})
+}
+
+func reportDTO(ctx echo.Context) dto_request.ReportDTO {
+	return dto_request.ReportDTO{
+		From:   ctx.QueryParam("from"),
+		To:     ctx.QueryParam("to"),
+		Region: ctx.QueryParam("region"),
+	}
+}
+
+// reportAsCSV reads the format query param, reports are JSON unless
+// format=csv is asked for.
+func reportAsCSV(ctx echo.Context) (bool, error) {
+	switch ctx.QueryParam("format") {
+	case "", "json":
+		return false, nil
+	case "csv":
+		return true, nil
+	default:
+		return false, errors.New("invalid_report_format")
+	}
+}
+
+func reportCSV(ctx echo.Context, name string, rows [][]string) error {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.WriteAll(rows); err != nil {
+		return reportError(ctx, err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.csv", name, time.Now().Format("20060102150405"))
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	return ctx.Blob(http.StatusOK, "text/csv", buffer.Bytes())
+}
+
+func reportError(ctx echo.Context, err error) error {
+	return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+		Code:  utils.GetErrorCode(err.Error()),
+		Error: err.Error(),
+	})
+}
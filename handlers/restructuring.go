@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type restructuringHandler struct {
+	restructuringUsecase usecases.RestructuringUsecaseInterface
+}
+
+func NewRestructuringHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	restructuringUsecase usecases.RestructuringUsecaseInterface,
+) {
+	handler := &restructuringHandler{
+		restructuringUsecase: restructuringUsecase,
+	}
+
+	loanGroup := e.Group("/loans", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Field Officer and Admin User
+	loanGroup.POST("/:id/restructurings", handler.request)
+
+	restructuringGroup := e.Group("/restructurings", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Admin and Supervisor User
+	restructuringGroup.GET("", handler.list)
+
+	// For Supervisor User
+	restructuringGroup.POST("/:id/approve", handler.approve)
+	restructuringGroup.POST("/:id/reject", handler.reject)
+}
+
+func (h *restructuringHandler) request(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.RequestRestructuringDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.LoanID = ctx.Param("id")
+	dto.UserID = context.ID
+
+	restructuring, err := h.restructuringUsecase.Request(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Restructuring Requested",
+		Data:    dto_response.RestructuringDetailResponse(restructuring),
+	})
+}
+
+func (h *restructuringHandler) list(ctx echo.Context) error {
+	dto := dto_request.RestructuringListDTO{
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+		Status:  ctx.QueryParam("status"),
+	}
+
+	restructurings, count, err := h.restructuringUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Restructuring List",
+		Data:    dto_response.RestructuringListResponse(restructurings),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *restructuringHandler) approve(ctx echo.Context) error {
+	dto, err := decisionDTO(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	restructuring, err := h.restructuringUsecase.Approve(ctx.Request().Context(), dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Restructuring Approved",
+		Data:    dto_response.RestructuringDetailResponse(restructuring),
+	})
+}
+
+func (h *restructuringHandler) reject(ctx echo.Context) error {
+	dto, err := decisionDTO(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	restructuring, err := h.restructuringUsecase.Reject(ctx.Request().Context(), dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Restructuring Rejected",
+		Data:    dto_response.RestructuringDetailResponse(restructuring),
+	})
+}
+
+// decisionDTO reads the optional supervisor note, an empty body is fine
+func decisionDTO(ctx echo.Context) (*dto_request.DecideRestructuringDTO, error) {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.DecideRestructuringDTO{}
+	if ctx.Request().ContentLength != 0 {
+		err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dto.RestructuringID = ctx.Param("id")
+	dto.SupervisorID = context.ID
+
+	return &dto, nil
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type walletHandler struct {
+	walletUsecase usecases.WalletUsecaseInterface
+}
+
+func NewWalletHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	walletUsecase usecases.WalletUsecaseInterface,
+) {
+	handler := &walletHandler{
+		walletUsecase: walletUsecase,
+	}
+
+	walletGroup := e.Group("/wallet", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Investor User
+	walletGroup.GET("", handler.detail)
+	walletGroup.POST("/deposits", handler.deposit)
+	walletGroup.POST("/withdrawals", handler.withdraw)
+	walletGroup.GET("/transactions", handler.listTransactions)
+}
+
+func (h *walletHandler) detail(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	wallet, err := h.walletUsecase.Detail(ctx.Request().Context(), context.ID)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Wallet Detail",
+		Data:    dto_response.WalletDetailResponse(wallet),
+	})
+}
+
+func (h *walletHandler) deposit(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.DepositDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.UserID = context.ID
+
+	transaction, err := h.walletUsecase.Deposit(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Deposit Success",
+		Data:    dto_response.WalletTransactionDetailResponse(transaction),
+	})
+}
+
+func (h *walletHandler) withdraw(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	var dto dto_request.WithdrawDTO
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.UserID = context.ID
+
+	transaction, err := h.walletUsecase.Withdraw(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Withdrawal Success",
+		Data:    dto_response.WalletTransactionDetailResponse(transaction),
+	})
+}
+
+func (h *walletHandler) listTransactions(ctx echo.Context) error {
+	context := ctx.Get("payload").(utils.Payload)
+
+	dto := dto_request.WalletTransactionListDTO{
+		UserID:  context.ID,
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+		Type:    ctx.QueryParam("type"),
+	}
+
+	transactions, count, err := h.walletUsecase.ListTransactions(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Wallet Transaction List",
+		Data:    dto_response.WalletTransactionListResponse(transactions),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	middleware "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/usecases"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type webhookHandler struct {
+	webhookUsecase usecases.WebhookUsecaseInterface
+}
+
+func NewWebhookHandler(
+	e *echo.Echo,
+	middleware *middleware.Middleware,
+	webhookUsecase usecases.WebhookUsecaseInterface,
+) {
+	handler := &webhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+
+	webhookGroup := e.Group("/webhooks", middleware.JWTAuth(), middleware.RBACMiddleware())
+
+	// For Admin User
+	webhookGroup.POST("", handler.create)
+	webhookGroup.GET("", handler.list)
+	webhookGroup.PUT("/:id", handler.update)
+	webhookGroup.DELETE("/:id", handler.delete)
+	webhookGroup.GET("/:id/deliveries", handler.listDeliveries)
+	webhookGroup.POST("/deliveries/:id/redeliver", handler.redeliver)
+}
+
+func (h *webhookHandler) create(ctx echo.Context) error {
+	var dto dto_request.CreateWebhookDTO
+
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+
+	subscription, err := h.webhookUsecase.Create(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Webhook Created",
+		Data:    dto_response.WebhookDetailResponse(subscription, true),
+	})
+}
+
+func (h *webhookHandler) list(ctx echo.Context) error {
+	dto := dto_request.WebhookListDTO{
+		Page:    ctx.QueryParam("page"),
+		PerPage: ctx.QueryParam("per_page"),
+	}
+
+	subscriptions, count, err := h.webhookUsecase.List(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, utils.Error{
+			Code:  http.StatusInternalServerError,
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Webhook List",
+		Data:    dto_response.WebhookListResponse(subscriptions),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *webhookHandler) update(ctx echo.Context) error {
+	dto := dto_request.UpdateWebhookDTO{}
+
+	err := json.NewDecoder(ctx.Request().Body).Decode(&dto)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, utils.Error{
+			Code:  http.StatusBadRequest,
+			Error: "Invalid Payload",
+		})
+	}
+	dto.WebhookID = ctx.Param("id")
+
+	subscription, err := h.webhookUsecase.Update(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Webhook Updated",
+		Data:    dto_response.WebhookDetailResponse(subscription, false),
+	})
+}
+
+func (h *webhookHandler) delete(ctx echo.Context) error {
+	err := h.webhookUsecase.Delete(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Webhook Deleted",
+	})
+}
+
+func (h *webhookHandler) listDeliveries(ctx echo.Context) error {
+	dto := dto_request.WebhookDeliveryListDTO{
+		WebhookID: ctx.Param("id"),
+		Page:      ctx.QueryParam("page"),
+		PerPage:   ctx.QueryParam("per_page"),
+	}
+
+	deliveries, count, err := h.webhookUsecase.ListDeliveries(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, utils.Response{
+		Message: "Webhook Delivery List",
+		Data:    dto_response.WebhookDeliveryListResponse(deliveries),
+		Meta:    utils.GenerateMeta(dto.Page, dto.PerPage, count),
+	})
+}
+
+func (h *webhookHandler) redeliver(ctx echo.Context) error {
+	dto := dto_request.RedeliverWebhookDTO{
+		DeliveryID: ctx.Param("id"),
+	}
+
+	delivery, err := h.webhookUsecase.Redeliver(ctx.Request().Context(), &dto)
+	if err != nil {
+		return ctx.JSON(utils.GetErrorCode(err.Error()), utils.Error{
+			Code:  utils.GetErrorCode(err.Error()),
+			Error: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, utils.Response{
+		Message: "Webhook Redelivered",
+		Data:    dto_response.WebhookDeliveryResponse(delivery),
+	})
+}
@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/peang/amartha-loan-service/configs"
 	"github.com/peang/amartha-loan-service/handlers"
 	middlewares "github.com/peang/amartha-loan-service/middlewares"
+	"github.com/peang/amartha-loan-service/models"
 	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
 	"github.com/peang/amartha-loan-service/services/file_services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
 	"github.com/peang/amartha-loan-service/usecases"
 )
 
@@ -25,9 +33,10 @@ func main() {
 		panic(err)
 	}
 
-	middleware := middlewares.NewMiddleware(enfocer)
+	middleware := middlewares.NewMiddleware(enfocer, conf.UploadMaxRequestSize())
 
 	e := echo.New()
+	e.Use(middleware.RequestMeta())
 
 	// Register Repositories
 	userRepository := repositories.NewUserRepository(db)
@@ -35,15 +44,154 @@ func main() {
 	disbursementRepository := repositories.NewDisbursementRepository(db)
 	loanRepository := repositories.NewLoanRepository(db, approvalRepository, disbursementRepository)
 	investmentRepository := repositories.NewInvestmentRepository(db, loanRepository)
+	webhookRepository := repositories.NewWebhookRepository(db)
+	documentRepository := repositories.NewDocumentRepository(db)
+	approvalSignOffRepository := repositories.NewApprovalSignOffRepository(db)
+	rateCardRepository := repositories.NewRateCardRepository(db)
+	loanProductRepository := repositories.NewLoanProductRepository(db)
+	walletRepository := repositories.NewWalletRepository(db)
+	paymentRepository := repositories.NewPaymentRepository(db)
+	autoInvestPlanRepository := repositories.NewAutoInvestPlanRepository(db)
+	investmentListingRepository := repositories.NewInvestmentListingRepository(db)
+	installmentRepository := repositories.NewInstallmentRepository(db)
+	restructuringRepository := repositories.NewRestructuringRepository(db)
+	writeOffRepository := repositories.NewWriteOffRepository(db)
+	payoffRepository := repositories.NewPayoffRepository(db)
+	borrowerGroupRepository := repositories.NewBorrowerGroupRepository(db)
+	groupLoanRepository := repositories.NewGroupLoanRepository(db)
+	auditLogRepository := repositories.NewAuditLogRepository(db)
+	reportRepository := repositories.NewReportRepository(db)
 
 	// Register Services
-	fileService := file_services.NewLocalFileService()
+	fileService, err := file_services.NewFileService(conf)
+	if err != nil {
+		panic(err)
+	}
+
+	approvalPolicy, err := toApprovalPolicy(conf.ApprovalTiers)
+	if err != nil {
+		panic(err)
+	}
+	webhookService := services.NewWebhookService(
+		webhookRepository,
+		&http.Client{Timeout: conf.WebhookTimeout},
+		conf.WebhookMaxAttempts,
+		conf.WebhookBackoff,
+	)
+	auditService := services.NewAuditService(auditLogRepository)
+	pricingService := services.NewPricingService(rateCardRepository)
+	paymentGateway := payment_services.NewPaymentGateway(conf)
+	creditScorer := services.NewRuleBasedCreditScorer(loanRepository, installmentRepository, conf.CreditAutoRejectScore)
+
+	investmentLimits := models.InvestmentLimits{
+		MinTicket:           conf.InvestmentMinTicket,
+		MaxTicket:           conf.InvestmentMaxTicket,
+		LotSize:             conf.InvestmentLotSize,
+		MaxLoanSharePercent: conf.InvestmentMaxLoanShare,
+		MaxInvestorExposure: conf.InvestmentMaxInvestorExposure,
+		MaxBorrowerExposure: conf.InvestmentMaxBorrowerExposure,
+	}
 
 	// Register Usecases
-	loanUsecase := usecases.NewLoanUsecase(loanRepository, investmentRepository, fileService)
+	loanUsecase := usecases.NewLoanUsecase(
+		loanRepository,
+		loanProductRepository,
+		userRepository,
+		investmentRepository,
+		walletRepository,
+		paymentRepository,
+		autoInvestPlanRepository,
+		installmentRepository,
+		documentRepository,
+		approvalSignOffRepository,
+		fileService,
+		webhookService,
+		auditService,
+		paymentGateway,
+		pricingService,
+		creditScorer,
+		usecases.LoanDocumentRequirements{
+			Approval:     toDocumentTypes(conf.ApprovalRequiredDocuments),
+			Disbursement: toDocumentTypes(conf.DisbursementRequiredDocuments),
+		},
+		models.FieldVisitPolicy{
+			MaxDistance: conf.ApprovalVisitMaxDistance,
+			MaxAge:      conf.ApprovalVisitMaxAge,
+		},
+		approvalPolicy,
+		investmentLimits,
+	)
+	documentUsecase := usecases.NewDocumentUsecase(documentRepository, loanRepository, investmentRepository, approvalSignOffRepository, fileService, conf.FileStorage == file_services.StorageS3)
+	webhookUsecase := usecases.NewWebhookUsecase(webhookRepository, webhookService, auditService)
+	rateCardUsecase := usecases.NewRateCardUsecase(rateCardRepository, auditService)
+	loanProductUsecase := usecases.NewLoanProductUsecase(loanProductRepository, auditService)
+	walletUsecase := usecases.NewWalletUsecase(walletRepository, userRepository, auditService, paymentGateway)
+	collectionUsecase := usecases.NewCollectionUsecase(loanRepository, installmentRepository, investmentRepository, writeOffRepository, paymentRepository, payoffRepository, webhookService, auditService, paymentGateway, models.DelinquencyPolicy{
+		LateFeePercent:      conf.LateFeePercent,
+		LateFeeGraceDays:    conf.LateFeeGraceDays,
+		DelinquentAfterDays: conf.LoanDelinquentAfterDays,
+		DefaultAfterDays:    conf.LoanDefaultAfterDays,
+	})
+	restructuringUsecase := usecases.NewRestructuringUsecase(restructuringRepository, loanRepository, installmentRepository, webhookService, auditService)
+	borrowerGroupUsecase := usecases.NewBorrowerGroupUsecase(borrowerGroupRepository, userRepository, loanRepository, auditService)
+	groupLoanUsecase := usecases.NewGroupLoanUsecase(groupLoanRepository, borrowerGroupRepository, installmentRepository, loanUsecase, collectionUsecase, auditService)
+	fieldSyncUsecase := usecases.NewFieldSyncUsecase(loanRepository, installmentRepository, paymentRepository, groupLoanRepository, collectionUsecase, groupLoanUsecase, services.NewFieldSyncSigner(conf.FieldSyncSecret))
+	paymentUsecase := usecases.NewPaymentUsecase(paymentRepository, loanRepository, userRepository, walletRepository, auditService, paymentGateway, loanUsecase, collectionUsecase)
+	autoInvestPlanUsecase := usecases.NewAutoInvestPlanUsecase(autoInvestPlanRepository, auditService)
+	investmentMarketUsecase := usecases.NewInvestmentMarketUsecase(investmentListingRepository, investmentRepository, loanRepository, auditService, investmentLimits)
+	auditLogUsecase := usecases.NewAuditLogUsecase(auditLogRepository, loanRepository)
+	reportUsecase := usecases.NewReportUsecase(reportRepository)
 
 	handlers.NewAuthHandler(e, userRepository)
 	handlers.NewLoanHandler(e, middleware, loanUsecase)
+	handlers.NewInvestmentHandler(e, middleware, loanUsecase)
+	handlers.NewWebhookHandler(e, middleware, webhookUsecase)
+	handlers.NewDocumentHandler(e, middleware, documentUsecase)
+	handlers.NewRateCardHandler(e, middleware, rateCardUsecase)
+	handlers.NewLoanProductHandler(e, middleware, loanProductUsecase)
+	handlers.NewWalletHandler(e, middleware, walletUsecase)
+	handlers.NewPaymentHandler(e, middleware, paymentUsecase)
+	handlers.NewAutoInvestPlanHandler(e, middleware, autoInvestPlanUsecase)
+	handlers.NewInvestmentListingHandler(e, middleware, investmentMarketUsecase)
+	handlers.NewCollectionHandler(e, middleware, collectionUsecase)
+	handlers.NewRestructuringHandler(e, middleware, restructuringUsecase)
+	handlers.NewBorrowerGroupHandler(e, middleware, borrowerGroupUsecase, groupLoanUsecase)
+	handlers.NewGroupLoanHandler(e, middleware, groupLoanUsecase)
+	handlers.NewFieldSyncHandler(e, middleware, fieldSyncUsecase)
+	handlers.NewAuditLogHandler(e, middleware, auditLogUsecase)
+	handlers.NewReportHandler(e, middleware, reportUsecase)
+
+	go func() {
+		ticker := time.NewTicker(conf.LoanExpiryInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := loanUsecase.ExpireLoans(context.Background(), time.Now().Add(-conf.LoanFundingPeriod))
+			if err != nil {
+				e.Logger.Error(err)
+			}
+
+			if expired > 0 {
+				e.Logger.Infof("expired %d unfunded loans", expired)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(conf.DelinquencyCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			changed, err := collectionUsecase.AssessDelinquency(context.Background(), time.Now())
+			if err != nil {
+				e.Logger.Error(err)
+			}
+
+			if changed > 0 {
+				e.Logger.Infof("%d loans changed delinquency status", changed)
+			}
+		}
+	}()
 
 	go func() {
 		if err := e.Start(":8080"); err != nil && err != http.ErrServerClosed {
@@ -55,3 +203,34 @@ func main() {
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 }
+
+func toDocumentTypes(values []string) []models.DocumentType {
+	documentTypes := make([]models.DocumentType, 0, len(values))
+	for _, value := range values {
+		documentTypes = append(documentTypes, models.DocumentType(value))
+	}
+
+	return documentTypes
+}
+
+// toApprovalPolicy reads tiers written as amount:role, a typo must not
+// quietly let large loans through with a single approval.
+func toApprovalPolicy(values []string) (models.ApprovalPolicy, error) {
+	policy := models.ApprovalPolicy{}
+	for _, value := range values {
+		amount, roleName, found := strings.Cut(value, ":")
+		aboveAmount, err := strconv.ParseFloat(amount, 64)
+		if !found || err != nil || aboveAmount < 0 {
+			return policy, fmt.Errorf("invalid approval tier %q", value)
+		}
+
+		role, ok := models.ParseUserRole(roleName)
+		if !ok || (role != models.RoleSupervisor && role != models.RoleCreditCommittee) {
+			return policy, fmt.Errorf("invalid approval tier role %q", roleName)
+		}
+
+		policy.Tiers = append(policy.Tiers, models.ApprovalTier{AboveAmount: aboveAmount, Role: role})
+	}
+
+	return policy, nil
+}
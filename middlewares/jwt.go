@@ -23,6 +23,11 @@ func (m *Middleware) JWTAuth() echo.MiddlewareFunc {
 
 			c.Set("payload", tokenInfo.Payload)
 
+			meta := utils.RequestMetaFrom(c.Request().Context())
+			meta.ActorID = &tokenInfo.Payload.ID
+			meta.ActorRole = tokenInfo.Payload.Role
+			c.SetRequest(c.Request().WithContext(utils.WithRequestMeta(c.Request().Context(), meta)))
+
 			return next(c)
 		}
 	}
@@ -5,11 +5,13 @@ import (
 )
 
 type Middleware struct {
-	enforcer *casbin.Enforcer
+	enforcer         *casbin.Enforcer
+	uploadLimitBytes int64
 }
 
-func NewMiddleware(enfocer *casbin.Enforcer) *Middleware {
+func NewMiddleware(enfocer *casbin.Enforcer, uploadLimitBytes int64) *Middleware {
 	return &Middleware{
-		enforcer: enfocer,
+		enforcer:         enfocer,
+		uploadLimitBytes: uploadLimitBytes,
 	}
 }
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+const (
+	headerRequestID    = "X-Request-Id"
+	maxRequestIDLength = 64
+)
+
+// RequestMeta tags every request with an id, the caller's own when it sends
+// a sane one, and the client IP, JWTAuth adds the actor later on.
+func (m *Middleware) RequestMeta() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Request().Header.Get(headerRequestID)
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = uuid.NewString()
+			}
+			c.Response().Header().Set(headerRequestID, requestID)
+
+			ctx := utils.WithRequestMeta(c.Request().Context(), utils.RequestMeta{
+				RequestID: requestID,
+				ClientIP:  c.RealIP(),
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+// UploadLimit caps the body of upload routes before echo parses the
+// multipart form, a body declared too large is refused outright and one that
+// turns out too large stops being read at the limit.
+func (m *Middleware) UploadLimit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().ContentLength > m.uploadLimitBytes {
+				return c.JSON(http.StatusRequestEntityTooLarge, utils.Error{
+					Code:  http.StatusRequestEntityTooLarge,
+					Error: "file_too_large",
+				})
+			}
+
+			c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, m.uploadLimitBytes)
+
+			return next(c)
+		}
+	}
+}
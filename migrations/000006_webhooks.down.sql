@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_event_id;
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription_id;
+DROP INDEX IF EXISTS idx_webhook_deliveries_uuid;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_active;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_uuid;
+DROP TABLE IF EXISTS webhook_subscriptions;
\ No newline at end of file
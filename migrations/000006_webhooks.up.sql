@@ -0,0 +1,38 @@
+CREATE TABLE webhook_subscriptions (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  url TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  secret VARCHAR(255) NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_webhook_subscriptions_uuid ON webhook_subscriptions (uuid);
+CREATE INDEX idx_webhook_subscriptions_active ON webhook_subscriptions (active);
+
+CREATE TABLE webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  subscription_id BIGINT NOT NULL,
+  event_id UUID NOT NULL,
+  event_type VARCHAR(64) NOT NULL,
+  payload JSONB NOT NULL,
+  attempt INT NOT NULL,
+  status_code INT NOT NULL DEFAULT 0,
+  response_body TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT '',
+  success BOOLEAN NOT NULL DEFAULT FALSE,
+  duration_ms BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT fk_webhook_subscription
+    FOREIGN KEY(subscription_id)
+    REFERENCES webhook_subscriptions(id)
+    ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_uuid ON webhook_deliveries (uuid);
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+CREATE INDEX idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
@@ -0,0 +1,2 @@
+ALTER TABLE disbursements DROP COLUMN IF EXISTS aggreement_file_checksum;
+ALTER TABLE approvals DROP COLUMN IF EXISTS approval_file_checksum;
\ No newline at end of file
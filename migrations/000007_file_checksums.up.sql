@@ -0,0 +1,2 @@
+ALTER TABLE approvals ADD COLUMN approval_file_checksum VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE disbursements ADD COLUMN aggreement_file_checksum VARCHAR(64) NOT NULL DEFAULT '';
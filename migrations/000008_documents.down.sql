@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_documents_loan_id;
+DROP INDEX IF EXISTS idx_documents_uuid;
+DROP TABLE IF EXISTS documents;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE documents (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  loan_id BIGINT NOT NULL,
+  type VARCHAR(64) NOT NULL,
+  file_url TEXT NOT NULL,
+  checksum VARCHAR(64) NOT NULL,
+  content_type VARCHAR(255) NOT NULL,
+  size BIGINT NOT NULL,
+  uploaded_by BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT fk_document_loan
+    FOREIGN KEY(loan_id)
+    REFERENCES loans(id)
+);
+
+CREATE UNIQUE INDEX idx_documents_uuid ON documents (uuid);
+CREATE INDEX idx_documents_loan_id ON documents (loan_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_documents_disbursement_id;
+DROP INDEX IF EXISTS idx_documents_approval_id;
+ALTER TABLE documents DROP COLUMN IF EXISTS disbursement_id;
+ALTER TABLE documents DROP COLUMN IF EXISTS approval_id;
\ No newline at end of file
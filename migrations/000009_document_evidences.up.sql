@@ -0,0 +1,5 @@
+ALTER TABLE documents ADD COLUMN approval_id BIGINT REFERENCES approvals(id);
+ALTER TABLE documents ADD COLUMN disbursement_id BIGINT REFERENCES disbursements(id);
+
+CREATE INDEX idx_documents_approval_id ON documents (approval_id);
+CREATE INDEX idx_documents_disbursement_id ON documents (disbursement_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_loan_tenor;
+DROP INDEX IF EXISTS idx_loan_rate;
+DROP INDEX IF EXISTS idx_users_region;
+DROP INDEX IF EXISTS idx_users_sector;
+ALTER TABLE loans DROP COLUMN IF EXISTS tenor;
+ALTER TABLE users DROP COLUMN IF EXISTS region;
+ALTER TABLE users DROP COLUMN IF EXISTS sector;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN sector VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN region VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE loans ADD COLUMN tenor INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_users_sector ON users (sector);
+CREATE INDEX idx_users_region ON users (region);
+CREATE INDEX idx_loan_rate ON loans (rate);
+CREATE INDEX idx_loan_tenor ON loans (tenor);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_investments_loan_id_id;
+DROP INDEX IF EXISTS idx_loan_status_created_at_id;
\ No newline at end of file
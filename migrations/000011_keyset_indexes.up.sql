@@ -0,0 +1,2 @@
+CREATE INDEX idx_loan_status_created_at_id ON loans (status, created_at, id);
+CREATE INDEX idx_investments_loan_id_id ON investments (loan_id, id);
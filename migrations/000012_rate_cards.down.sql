@@ -0,0 +1,8 @@
+ALTER TABLE loans DROP COLUMN IF EXISTS rate_card_version;
+ALTER TABLE loans DROP COLUMN IF EXISTS rate_card_id;
+ALTER TABLE loans DROP COLUMN IF EXISTS risk_grade;
+ALTER TABLE loans DROP COLUMN IF EXISTS platform_fee;
+ALTER TABLE users DROP COLUMN IF EXISTS risk_grade;
+DROP INDEX IF EXISTS idx_rate_cards_lookup;
+DROP INDEX IF EXISTS idx_rate_cards_uuid;
+DROP TABLE IF EXISTS rate_cards;
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE rate_cards (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  previous_id BIGINT REFERENCES rate_cards(id),
+  version INT NOT NULL,
+  product VARCHAR(64) NOT NULL,
+  risk_grade VARCHAR(2) NOT NULL,
+  min_tenor INT NOT NULL,
+  max_tenor INT NOT NULL,
+  rate NUMERIC(10,2) NOT NULL,
+  platform_fee_rate NUMERIC(10,2) NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_by BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_rate_cards_uuid ON rate_cards (uuid);
+CREATE INDEX idx_rate_cards_lookup ON rate_cards (product, risk_grade, active);
+
+ALTER TABLE users ADD COLUMN risk_grade VARCHAR(2) NOT NULL DEFAULT 'C';
+
+ALTER TABLE loans ADD COLUMN platform_fee NUMERIC(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE loans ADD COLUMN risk_grade VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE loans ADD COLUMN rate_card_id BIGINT REFERENCES rate_cards(id);
+ALTER TABLE loans ADD COLUMN rate_card_version INT NOT NULL DEFAULT 0;
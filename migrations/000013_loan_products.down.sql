@@ -0,0 +1,5 @@
+ALTER TABLE loans DROP COLUMN grace_period_days;
+ALTER TABLE loans DROP COLUMN installment_frequency;
+ALTER TABLE loans DROP COLUMN product_id;
+
+DROP TABLE loan_products;
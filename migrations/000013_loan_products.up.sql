@@ -0,0 +1,24 @@
+CREATE TABLE loan_products (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  code VARCHAR(64) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  min_amount NUMERIC(20,2) NOT NULL,
+  max_amount NUMERIC(20,2) NOT NULL,
+  tenors INT[] NOT NULL,
+  installment_frequency VARCHAR(16) NOT NULL,
+  grace_period_days INT NOT NULL DEFAULT 0,
+  eligible_risk_grades VARCHAR(2)[] NOT NULL DEFAULT '{}',
+  eligible_sectors VARCHAR(64)[] NOT NULL DEFAULT '{}',
+  eligible_regions VARCHAR(64)[] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_loan_products_uuid ON loan_products (uuid);
+CREATE UNIQUE INDEX idx_loan_products_code ON loan_products (code);
+
+ALTER TABLE loans ADD COLUMN product_id BIGINT REFERENCES loan_products(id);
+ALTER TABLE loans ADD COLUMN installment_frequency VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE loans ADD COLUMN grace_period_days INT NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+DROP INDEX idx_loans_borrower_status;
+
+ALTER TABLE loans DROP COLUMN rejection_reason;
+ALTER TABLE loans DROP COLUMN credit_score;
+
+ALTER TABLE users DROP COLUMN kyc_verified;
@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN kyc_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE loans ADD COLUMN credit_score INT NOT NULL DEFAULT 0;
+ALTER TABLE loans ADD COLUMN rejection_reason VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_loans_borrower_status ON loans (borrower_id, status);
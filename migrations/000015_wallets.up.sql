@@ -0,0 +1,34 @@
+CREATE TABLE wallets (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  balance NUMERIC(20,2) NOT NULL DEFAULT 0,
+  reserved_balance NUMERIC(20,2) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP,
+  CONSTRAINT chk_wallets_balance CHECK (reserved_balance >= 0 AND balance >= reserved_balance)
+);
+
+CREATE UNIQUE INDEX idx_wallets_uuid ON wallets (uuid);
+CREATE UNIQUE INDEX idx_wallets_user_id ON wallets (user_id);
+
+CREATE TABLE wallet_transactions (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+  type VARCHAR(16) NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  amount NUMERIC(20,2) NOT NULL,
+  balance_after NUMERIC(20,2) NOT NULL,
+  reserved_balance_after NUMERIC(20,2) NOT NULL,
+  reference VARCHAR(255) NOT NULL DEFAULT '',
+  failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+  loan_id BIGINT REFERENCES loans(id),
+  investment_id BIGINT REFERENCES investments(id),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_wallet_transactions_uuid ON wallet_transactions (uuid);
+CREATE INDEX idx_wallet_transactions_wallet_id ON wallet_transactions (wallet_id, id);
+-- A reservation is released or settled at most once
+CREATE UNIQUE INDEX idx_wallet_transactions_investment_type ON wallet_transactions (investment_id, type) WHERE investment_id IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE loans DROP COLUMN virtual_account_number;
+
+ALTER TABLE users DROP COLUMN bank_account_name;
+ALTER TABLE users DROP COLUMN bank_account_number;
+ALTER TABLE users DROP COLUMN bank_code;
+
+DROP TABLE payments;
@@ -0,0 +1,29 @@
+CREATE TABLE payments (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  direction VARCHAR(16) NOT NULL,
+  method VARCHAR(32) NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  amount NUMERIC(20,2) NOT NULL,
+  reference VARCHAR(64) NOT NULL,
+  gateway_reference VARCHAR(255) NOT NULL DEFAULT '',
+  bank_code VARCHAR(16) NOT NULL DEFAULT '',
+  bank_account_number VARCHAR(64) NOT NULL DEFAULT '',
+  virtual_account_number VARCHAR(64) NOT NULL DEFAULT '',
+  failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+  completed_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_payments_uuid ON payments (uuid);
+CREATE UNIQUE INDEX idx_payments_reference ON payments (reference);
+CREATE UNIQUE INDEX idx_payments_gateway_reference ON payments (gateway_reference) WHERE gateway_reference <> '';
+CREATE INDEX idx_payments_loan_id ON payments (loan_id, id);
+
+ALTER TABLE users ADD COLUMN bank_code VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN bank_account_number VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN bank_account_name VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE loans ADD COLUMN virtual_account_number VARCHAR(64) NOT NULL DEFAULT '';
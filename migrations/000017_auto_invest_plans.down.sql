@@ -0,0 +1 @@
+DROP TABLE auto_invest_plans;
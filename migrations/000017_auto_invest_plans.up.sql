@@ -0,0 +1,21 @@
+CREATE TABLE auto_invest_plans (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  investor_id BIGINT NOT NULL REFERENCES users(id),
+  budget NUMERIC(20,2) NOT NULL,
+  invested_amount NUMERIC(20,2) NOT NULL DEFAULT 0,
+  amount_per_loan NUMERIC(20,2) NOT NULL,
+  min_rate NUMERIC(10,2),
+  max_rate NUMERIC(10,2),
+  tenors INT[] NOT NULL DEFAULT '{}',
+  risk_grades VARCHAR(2)[] NOT NULL DEFAULT '{}',
+  regions VARCHAR(64)[] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  last_matched_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP,
+  CONSTRAINT chk_auto_invest_plans_budget CHECK (invested_amount >= 0 AND invested_amount <= budget)
+);
+
+CREATE UNIQUE INDEX idx_auto_invest_plans_uuid ON auto_invest_plans (uuid);
+CREATE INDEX idx_auto_invest_plans_matching ON auto_invest_plans (active, last_matched_at);
@@ -0,0 +1,9 @@
+DROP INDEX idx_wallet_transactions_investment_type;
+DELETE FROM wallet_transactions WHERE type IN ('purchase', 'sale');
+CREATE UNIQUE INDEX idx_wallet_transactions_investment_type ON wallet_transactions (investment_id, type) WHERE investment_id IS NOT NULL;
+
+DROP TABLE investment_transfers;
+DROP TABLE investment_listings;
+
+DROP INDEX idx_investments_uuid;
+ALTER TABLE investments DROP COLUMN uuid;
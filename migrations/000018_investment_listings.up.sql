@@ -0,0 +1,43 @@
+ALTER TABLE investments ADD COLUMN uuid UUID;
+UPDATE investments SET uuid = gen_random_uuid();
+ALTER TABLE investments ALTER COLUMN uuid SET NOT NULL;
+CREATE UNIQUE INDEX idx_investments_uuid ON investments (uuid);
+
+CREATE TABLE investment_listings (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  investment_id BIGINT NOT NULL REFERENCES investments(id),
+  seller_id BIGINT NOT NULL REFERENCES users(id),
+  buyer_id BIGINT REFERENCES users(id),
+  price NUMERIC(20,2) NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  sold_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_investment_listings_uuid ON investment_listings (uuid);
+CREATE INDEX idx_investment_listings_status ON investment_listings (status, created_at);
+-- An investment is on sale at most once at a time
+CREATE UNIQUE INDEX idx_investment_listings_open ON investment_listings (investment_id) WHERE status = 'open';
+
+CREATE TABLE investment_transfers (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  investment_id BIGINT NOT NULL REFERENCES investments(id),
+  listing_id BIGINT NOT NULL REFERENCES investment_listings(id),
+  seller_id BIGINT NOT NULL REFERENCES users(id),
+  buyer_id BIGINT NOT NULL REFERENCES users(id),
+  amount NUMERIC(20,2) NOT NULL,
+  price NUMERIC(20,2) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_investment_transfers_uuid ON investment_transfers (uuid);
+CREATE UNIQUE INDEX idx_investment_transfers_listing_id ON investment_transfers (listing_id);
+CREATE INDEX idx_investment_transfers_seller_id ON investment_transfers (seller_id);
+CREATE INDEX idx_investment_transfers_buyer_id ON investment_transfers (buyer_id);
+
+-- A position can be traded many times, only reservations stay booked once
+DROP INDEX idx_wallet_transactions_investment_type;
+CREATE UNIQUE INDEX idx_wallet_transactions_investment_type ON wallet_transactions (investment_id, type) WHERE investment_id IS NOT NULL AND type IN ('reserve', 'release', 'settle');
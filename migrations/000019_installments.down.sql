@@ -0,0 +1,7 @@
+DROP INDEX idx_loan_days_past_due;
+
+ALTER TABLE loans DROP COLUMN overdue_amount;
+ALTER TABLE loans DROP COLUMN delinquency_bucket;
+ALTER TABLE loans DROP COLUMN days_past_due;
+
+DROP TABLE installments;
@@ -0,0 +1,24 @@
+CREATE TABLE installments (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  sequence INT NOT NULL,
+  due_date TIMESTAMP NOT NULL,
+  principal_amount NUMERIC(20,2) NOT NULL,
+  interest_amount NUMERIC(20,2) NOT NULL,
+  late_fee NUMERIC(20,2) NOT NULL DEFAULT 0,
+  paid_amount NUMERIC(20,2) NOT NULL DEFAULT 0,
+  paid_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_installments_uuid ON installments (uuid);
+CREATE UNIQUE INDEX idx_installments_loan_sequence ON installments (loan_id, sequence);
+CREATE INDEX idx_installments_due_date ON installments (due_date) WHERE paid_at IS NULL;
+
+ALTER TABLE loans ADD COLUMN days_past_due INT NOT NULL DEFAULT 0;
+ALTER TABLE loans ADD COLUMN delinquency_bucket VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE loans ADD COLUMN overdue_amount NUMERIC(20,2) NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_loan_days_past_due ON loans (status, days_past_due);
@@ -0,0 +1,7 @@
+DROP TABLE investment_losses;
+DROP TABLE write_offs;
+
+ALTER TABLE installments DROP COLUMN superseded_at;
+ALTER TABLE installments DROP COLUMN restructuring_id;
+
+DROP TABLE restructurings;
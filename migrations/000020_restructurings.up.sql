@@ -0,0 +1,50 @@
+CREATE TABLE restructurings (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  requested_by BIGINT NOT NULL REFERENCES users(id),
+  decided_by BIGINT REFERENCES users(id),
+  status VARCHAR(16) NOT NULL,
+  extra_installments INT NOT NULL DEFAULT 0,
+  holiday_periods INT NOT NULL DEFAULT 0,
+  reason TEXT NOT NULL DEFAULT '',
+  decision_note TEXT NOT NULL DEFAULT '',
+  decided_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_restructurings_uuid ON restructurings (uuid);
+CREATE INDEX idx_restructurings_status ON restructurings (status, created_at);
+-- A loan has at most one request waiting for a supervisor
+CREATE UNIQUE INDEX idx_restructurings_pending ON restructurings (loan_id) WHERE status = 'requested';
+
+ALTER TABLE installments ADD COLUMN restructuring_id BIGINT REFERENCES restructurings(id);
+ALTER TABLE installments ADD COLUMN superseded_at TIMESTAMP;
+
+CREATE TABLE write_offs (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  approved_by BIGINT NOT NULL REFERENCES users(id),
+  reason TEXT NOT NULL DEFAULT '',
+  outstanding_principal NUMERIC(20,2) NOT NULL,
+  outstanding_interest NUMERIC(20,2) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_write_offs_uuid ON write_offs (uuid);
+CREATE UNIQUE INDEX idx_write_offs_loan_id ON write_offs (loan_id);
+
+CREATE TABLE investment_losses (
+  id BIGSERIAL PRIMARY KEY,
+  write_off_id BIGINT NOT NULL REFERENCES write_offs(id),
+  investment_id BIGINT NOT NULL REFERENCES investments(id),
+  investor_id BIGINT NOT NULL REFERENCES users(id),
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  amount NUMERIC(20,2) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_investment_losses_investment_id ON investment_losses (investment_id);
+CREATE INDEX idx_investment_losses_investor_id ON investment_losses (investor_id);
@@ -0,0 +1,9 @@
+DROP INDEX idx_wallet_transactions_investment_type;
+DELETE FROM wallet_transactions WHERE type = 'payout';
+CREATE UNIQUE INDEX idx_wallet_transactions_investment_type ON wallet_transactions (investment_id, type) WHERE investment_id IS NOT NULL AND type IN ('reserve', 'release', 'settle');
+
+ALTER TABLE loans DROP COLUMN closed_at;
+ALTER TABLE loans DROP COLUMN early_settlement_fee;
+ALTER TABLE loans DROP COLUMN early_settlement_fee_percent;
+
+ALTER TABLE loan_products DROP COLUMN early_settlement_fee_percent;
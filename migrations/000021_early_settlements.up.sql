@@ -0,0 +1,9 @@
+ALTER TABLE loan_products ADD COLUMN early_settlement_fee_percent NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE loans ADD COLUMN early_settlement_fee_percent NUMERIC(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE loans ADD COLUMN early_settlement_fee NUMERIC(20,2) NOT NULL DEFAULT 0;
+ALTER TABLE loans ADD COLUMN closed_at TIMESTAMP;
+
+-- An investment is paid out once, when its loan is repaid
+DROP INDEX idx_wallet_transactions_investment_type;
+CREATE UNIQUE INDEX idx_wallet_transactions_investment_type ON wallet_transactions (investment_id, type) WHERE investment_id IS NOT NULL AND type IN ('reserve', 'release', 'settle', 'payout');
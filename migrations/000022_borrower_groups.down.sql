@@ -0,0 +1,9 @@
+DROP TABLE group_collection_allocations;
+DROP TABLE group_collections;
+
+DROP INDEX idx_loan_group_loan_id;
+ALTER TABLE loans DROP COLUMN group_loan_id;
+
+DROP TABLE group_loans;
+DROP TABLE borrower_group_members;
+DROP TABLE borrower_groups;
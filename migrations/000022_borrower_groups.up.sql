@@ -0,0 +1,68 @@
+CREATE TABLE borrower_groups (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  region VARCHAR(255) NOT NULL DEFAULT '',
+  leader_id BIGINT NOT NULL REFERENCES users(id),
+  field_officer_id BIGINT NOT NULL REFERENCES users(id),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_borrower_groups_uuid ON borrower_groups (uuid);
+CREATE INDEX idx_borrower_groups_field_officer_id ON borrower_groups (field_officer_id);
+
+CREATE TABLE borrower_group_members (
+  id BIGSERIAL PRIMARY KEY,
+  group_id BIGINT NOT NULL REFERENCES borrower_groups(id),
+  borrower_id BIGINT NOT NULL REFERENCES users(id),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A borrower belongs to one group at a time
+CREATE UNIQUE INDEX idx_borrower_group_members_borrower_id ON borrower_group_members (borrower_id);
+CREATE INDEX idx_borrower_group_members_group_id ON borrower_group_members (group_id);
+
+CREATE TABLE group_loans (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  group_id BIGINT NOT NULL REFERENCES borrower_groups(id),
+  proposed_by BIGINT NOT NULL REFERENCES users(id),
+  approved_by BIGINT REFERENCES users(id),
+  status VARCHAR(16) NOT NULL,
+  approved_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_group_loans_uuid ON group_loans (uuid);
+-- A group has at most one loan waiting for approval
+CREATE UNIQUE INDEX idx_group_loans_proposed ON group_loans (group_id) WHERE status = 'proposed';
+
+ALTER TABLE loans ADD COLUMN group_loan_id BIGINT REFERENCES group_loans(id);
+CREATE INDEX idx_loan_group_loan_id ON loans (group_loan_id);
+
+CREATE TABLE group_collections (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  group_loan_id BIGINT NOT NULL REFERENCES group_loans(id),
+  collected_by BIGINT NOT NULL REFERENCES users(id),
+  reference VARCHAR(255) NOT NULL,
+  amount NUMERIC(20,2) NOT NULL,
+  meeting_date TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_group_collections_uuid ON group_collections (uuid);
+CREATE UNIQUE INDEX idx_group_collections_reference ON group_collections (reference);
+CREATE INDEX idx_group_collections_group_loan_id ON group_collections (group_loan_id);
+
+CREATE TABLE group_collection_allocations (
+  id BIGSERIAL PRIMARY KEY,
+  group_collection_id BIGINT NOT NULL REFERENCES group_collections(id),
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  borrower_id BIGINT NOT NULL REFERENCES users(id),
+  amount NUMERIC(20,2) NOT NULL
+);
+
+CREATE INDEX idx_group_collection_allocations_collection_id ON group_collection_allocations (group_collection_id);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_approvals_flagged;
+
+ALTER TABLE approvals DROP COLUMN IF EXISTS flags;
+ALTER TABLE approvals DROP COLUMN IF EXISTS photo_distance;
+ALTER TABLE approvals DROP COLUMN IF EXISTS photo_taken_at;
+ALTER TABLE approvals DROP COLUMN IF EXISTS photo_longitude;
+ALTER TABLE approvals DROP COLUMN IF EXISTS photo_latitude;
+ALTER TABLE approvals DROP COLUMN IF EXISTS visited_at;
+ALTER TABLE approvals DROP COLUMN IF EXISTS visit_longitude;
+ALTER TABLE approvals DROP COLUMN IF EXISTS visit_latitude;
+
+ALTER TABLE users DROP COLUMN IF EXISTS address_longitude;
+ALTER TABLE users DROP COLUMN IF EXISTS address_latitude;
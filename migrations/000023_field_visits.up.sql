@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN address_latitude DOUBLE PRECISION;
+ALTER TABLE users ADD COLUMN address_longitude DOUBLE PRECISION;
+
+ALTER TABLE approvals ADD COLUMN visit_latitude DOUBLE PRECISION;
+ALTER TABLE approvals ADD COLUMN visit_longitude DOUBLE PRECISION;
+ALTER TABLE approvals ADD COLUMN visited_at TIMESTAMP;
+ALTER TABLE approvals ADD COLUMN photo_latitude DOUBLE PRECISION;
+ALTER TABLE approvals ADD COLUMN photo_longitude DOUBLE PRECISION;
+ALTER TABLE approvals ADD COLUMN photo_taken_at TIMESTAMP;
+-- In meters from the borrower's registered address
+ALTER TABLE approvals ADD COLUMN photo_distance DOUBLE PRECISION;
+ALTER TABLE approvals ADD COLUMN flags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Flagged approvals are the ones reviewed after the fact
+CREATE INDEX idx_approvals_flagged ON approvals (created_at) WHERE cardinality(flags) > 0;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS approval_sign_offs;
+
+ALTER TABLE approvals DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE approvals DROP COLUMN IF EXISTS required_roles;
+
+ALTER TABLE loans DROP COLUMN IF EXISTS proposed_by;
@@ -0,0 +1,26 @@
+ALTER TABLE loans ADD COLUMN proposed_by BIGINT REFERENCES users(id);
+UPDATE loans SET proposed_by = borrower_id;
+UPDATE loans SET proposed_by = group_loans.proposed_by FROM group_loans WHERE loans.group_loan_id = group_loans.id;
+ALTER TABLE loans ALTER COLUMN proposed_by SET NOT NULL;
+
+ALTER TABLE approvals ADD COLUMN required_roles INT[] NOT NULL DEFAULT '{}';
+-- Loans approved so far needed no sign-off, their funding period ran from the field approval
+ALTER TABLE approvals ADD COLUMN approved_at TIMESTAMP;
+UPDATE approvals SET approved_at = created_at;
+
+CREATE TABLE approval_sign_offs (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  approval_id BIGINT NOT NULL REFERENCES approvals(id),
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  approver_id BIGINT NOT NULL REFERENCES users(id),
+  role INT NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_approval_sign_offs_uuid ON approval_sign_offs (uuid);
+-- Nobody signs twice and every required role signs once
+CREATE UNIQUE INDEX idx_approval_sign_offs_approver ON approval_sign_offs (approval_id, approver_id);
+CREATE UNIQUE INDEX idx_approval_sign_offs_role ON approval_sign_offs (approval_id, role);
+CREATE INDEX idx_approval_sign_offs_loan_id ON approval_sign_offs (loan_id);
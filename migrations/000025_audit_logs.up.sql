@@ -0,0 +1,36 @@
+CREATE TABLE audit_logs (
+  id BIGSERIAL PRIMARY KEY,
+  uuid UUID NOT NULL,
+  actor_id BIGINT REFERENCES users(id),
+  actor_role INT NOT NULL DEFAULT 0,
+  action VARCHAR(64) NOT NULL,
+  entity_type VARCHAR(32) NOT NULL,
+  entity_id VARCHAR(64) NOT NULL,
+  loan_id BIGINT REFERENCES loans(id),
+  investment_id BIGINT REFERENCES investments(id),
+  -- JSON rather than JSONB keeps the snapshots byte for byte as hashed
+  before JSON,
+  after JSON,
+  request_id VARCHAR(64) NOT NULL DEFAULT '',
+  client_ip VARCHAR(64) NOT NULL DEFAULT '',
+  prev_hash VARCHAR(64) NOT NULL DEFAULT '',
+  hash VARCHAR(64) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_audit_logs_uuid ON audit_logs (uuid);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX idx_audit_logs_loan_id ON audit_logs (loan_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- The log is append only, even for the application's own database user
+CREATE FUNCTION audit_logs_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_logs_append_only
+  BEFORE UPDATE OR DELETE ON audit_logs
+  FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,21 @@
+CREATE TABLE loan_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  from_status INT,
+  to_status INT NOT NULL,
+  actor_id BIGINT REFERENCES users(id),
+  actor_role INT NOT NULL DEFAULT 0,
+  reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_loan_status_history_loan_id ON loan_status_history (loan_id, created_at);
+
+-- Earlier transitions were never recorded, existing loans start their
+-- timeline with the proposal and a single step to where they are now
+INSERT INTO loan_status_history (loan_id, from_status, to_status, actor_id, actor_role, created_at)
+SELECT loans.id, NULL, 0, loans.proposed_by, users.role, loans.created_at
+FROM loans JOIN users ON users.id = loans.proposed_by;
+
+INSERT INTO loan_status_history (loan_id, from_status, to_status, reason, created_at)
+SELECT id, 0, status, rejection_reason, COALESCE(closed_at, updated_at, created_at) FROM loans WHERE status <> 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS auto_invest_match_failures;
@@ -0,0 +1,11 @@
+CREATE TABLE auto_invest_match_failures (
+  id BIGSERIAL PRIMARY KEY,
+  loan_id BIGINT NOT NULL REFERENCES loans(id),
+  plan_id BIGINT REFERENCES auto_invest_plans(id),
+  amount NUMERIC(20,2) NOT NULL DEFAULT 0,
+  error TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auto_invest_match_failures_loan_id ON auto_invest_match_failures (loan_id);
+CREATE INDEX idx_auto_invest_match_failures_plan_id ON auto_invest_match_failures (plan_id);
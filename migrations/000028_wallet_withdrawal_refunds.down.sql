@@ -0,0 +1 @@
+DROP INDEX idx_wallet_transactions_refund_reference;
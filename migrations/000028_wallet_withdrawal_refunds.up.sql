@@ -0,0 +1,2 @@
+-- A withdrawal the bank refused is refunded once, whoever reports it first
+CREATE UNIQUE INDEX idx_wallet_transactions_refund_reference ON wallet_transactions (reference) WHERE type = 'refund';
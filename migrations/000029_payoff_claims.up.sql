@@ -0,0 +1,2 @@
+-- Only settlements are charged while pending, one per loan at a time
+CREATE UNIQUE INDEX idx_payments_pending_collection ON payments (loan_id) WHERE direction = 'collection' AND status = 'pending';
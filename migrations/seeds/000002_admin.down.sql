@@ -0,0 +1 @@
+DELETE FROM users WHERE id = 5;
\ No newline at end of file
@@ -0,0 +1,2 @@
+INSERT INTO users (id, name, email, role, created_at, updated_at) VALUES
+(5, 'Admin', 'admin@amartha.id', 5, NOW(), NOW());
\ No newline at end of file
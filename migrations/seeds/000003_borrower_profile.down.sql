@@ -0,0 +1 @@
+UPDATE users SET sector = '', region = '' WHERE id = 1;
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE users SET sector = 'agriculture', region = 'bogor' WHERE id = 1;
\ No newline at end of file
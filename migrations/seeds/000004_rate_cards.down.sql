@@ -0,0 +1 @@
+DELETE FROM rate_cards WHERE product = 'default' AND created_by = 5;
\ No newline at end of file
@@ -0,0 +1,6 @@
+INSERT INTO rate_cards (uuid, version, product, risk_grade, min_tenor, max_tenor, rate, platform_fee_rate, active, created_by, created_at) VALUES
+(gen_random_uuid(), 1, 'default', 'A', 0, 60, 4.00, 10.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'default', 'B', 0, 60, 5.00, 10.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'default', 'C', 0, 60, 6.50, 12.50, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'default', 'D', 0, 60, 8.00, 15.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'default', 'E', 0, 60, 10.00, 15.00, TRUE, 5, NOW());
\ No newline at end of file
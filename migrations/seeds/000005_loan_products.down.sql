@@ -0,0 +1,2 @@
+DELETE FROM rate_cards WHERE product IN ('weekly_group', 'monthly_micro_business') AND created_by = 5;
+DELETE FROM loan_products WHERE code IN ('weekly_group', 'monthly_micro_business');
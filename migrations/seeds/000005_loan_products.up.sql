@@ -0,0 +1,13 @@
+INSERT INTO loan_products (uuid, code, name, min_amount, max_amount, tenors, installment_frequency, grace_period_days, eligible_risk_grades, created_at) VALUES
+(gen_random_uuid(), 'weekly_group', 'Weekly Group Loan', 1000000, 10000000, '{25,50}', 'weekly', 7, '{}', NOW()),
+(gen_random_uuid(), 'monthly_micro_business', 'Monthly Micro-Business Loan', 5000000, 50000000, '{6,12,18,24}', 'monthly', 30, '{A,B,C}', NOW());
+
+INSERT INTO rate_cards (uuid, version, product, risk_grade, min_tenor, max_tenor, rate, platform_fee_rate, active, created_by, created_at) VALUES
+(gen_random_uuid(), 1, 'weekly_group', 'A', 25, 50, 4.00, 10.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'weekly_group', 'B', 25, 50, 5.00, 10.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'weekly_group', 'C', 25, 50, 6.50, 12.50, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'weekly_group', 'D', 25, 50, 8.00, 15.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'weekly_group', 'E', 25, 50, 10.00, 15.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'monthly_micro_business', 'A', 6, 24, 5.00, 10.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'monthly_micro_business', 'B', 6, 24, 6.00, 10.00, TRUE, 5, NOW()),
+(gen_random_uuid(), 1, 'monthly_micro_business', 'C', 6, 24, 7.50, 12.50, TRUE, 5, NOW());
@@ -0,0 +1 @@
+UPDATE users SET kyc_verified = FALSE WHERE id = 1;
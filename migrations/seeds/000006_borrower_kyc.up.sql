@@ -0,0 +1 @@
+UPDATE users SET kyc_verified = TRUE WHERE id = 1;
@@ -0,0 +1 @@
+UPDATE users SET bank_code = '', bank_account_number = '', bank_account_name = '' WHERE id = 1;
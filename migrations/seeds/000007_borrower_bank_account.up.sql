@@ -0,0 +1 @@
+UPDATE users SET bank_code = 'BRI', bank_account_number = '1234567890', bank_account_name = 'Borowwer' WHERE id = 1;
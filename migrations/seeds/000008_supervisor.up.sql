@@ -0,0 +1,2 @@
+INSERT INTO users (id, name, email, role, created_at, updated_at) VALUES
+(6, 'Supervisor', 'supervisor@amartha.id', 6, NOW(), NOW());
\ No newline at end of file
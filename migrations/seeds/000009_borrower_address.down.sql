@@ -0,0 +1 @@
+UPDATE users SET address_latitude = NULL, address_longitude = NULL WHERE id = 1;
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE users SET address_latitude = -6.595038, address_longitude = 106.816635 WHERE id = 1;
\ No newline at end of file
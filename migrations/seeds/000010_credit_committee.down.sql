@@ -0,0 +1 @@
+DELETE FROM users WHERE id = 7;
\ No newline at end of file
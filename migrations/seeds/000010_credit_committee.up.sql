@@ -0,0 +1,2 @@
+INSERT INTO users (id, name, email, role, created_at, updated_at) VALUES
+(7, 'Credit Committee', 'committee@amartha.id', 7, NOW(), NOW());
\ No newline at end of file
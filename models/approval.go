@@ -1,17 +1,179 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"github.com/uptrace/bun"
 )
 
+const earthRadiusMeters = 6371000
+
+// Reasons an approval is flagged for review, the approval itself stands
+const (
+	ApprovalFlagVisitLocationMissing   = "visit_location_missing"
+	ApprovalFlagVisitFarFromAddress    = "visit_far_from_address"
+	ApprovalFlagVisitStale             = "visit_stale"
+	ApprovalFlagPhotoLocationMissing   = "photo_location_missing"
+	ApprovalFlagPhotoFarFromAddress    = "photo_far_from_address"
+	ApprovalFlagPhotoStale             = "photo_stale"
+	ApprovalFlagAddressLocationMissing = "address_location_missing"
+)
+
 type Approval struct {
 	bun.BaseModel `bun:"table:approvals"`
 
-	ID               uint       `bun:"id,pk,nullzero"`
-	FieldValidatorID uint       `bun:"field_validator_id"`
-	ApprovalFileURL  string     `bun:"approval_file_url"`
-	CreatedAt        time.Time  `bun:"created_at"`
-	UpdatedAt        *time.Time `bun:"updated_at,nullzero"`
+	ID                   uint       `bun:"id,pk,nullzero"`
+	FieldValidatorID     uint       `bun:"field_validator_id"`
+	ApprovalFileURL      string     `bun:"approval_file_url"`
+	ApprovalFileChecksum string     `bun:"approval_file_checksum"`
+	VisitLatitude        *float64   `bun:"visit_latitude"`
+	VisitLongitude       *float64   `bun:"visit_longitude"`
+	VisitedAt            *time.Time `bun:"visited_at,nullzero"`
+	PhotoLatitude        *float64   `bun:"photo_latitude"`
+	PhotoLongitude       *float64   `bun:"photo_longitude"`
+	PhotoTakenAt         *time.Time `bun:"photo_taken_at,nullzero"`
+	PhotoDistance        *float64   `bun:"photo_distance"`
+	Flags                []string   `bun:"flags,array"`
+	RequiredRoles        []int      `bun:"required_roles,array"`
+	ApprovedAt           *time.Time `bun:"approved_at,nullzero"`
+	CreatedAt            time.Time  `bun:"created_at"`
+	UpdatedAt            *time.Time `bun:"updated_at,nullzero"`
+
+	Documents []Document        `bun:"rel:has-many,join:id=approval_id"`
+	SignOffs  []ApprovalSignOff `bun:"rel:has-many,join:id=approval_id"`
+}
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func NewGeoPoint(latitude *float64, longitude *float64) *GeoPoint {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+
+	return &GeoPoint{Latitude: *latitude, Longitude: *longitude}
+}
+
+func (p GeoPoint) Valid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// DistanceTo is the great-circle distance in meters
+func (p GeoPoint) DistanceTo(other GeoPoint) float64 {
+	lat1 := p.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	deltaLat := lat2 - lat1
+	deltaLon := (other.Longitude - p.Longitude) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// FieldPhoto is where and when an approval photo says it was taken
+type FieldPhoto struct {
+	Location *GeoPoint
+	TakenAt  *time.Time
+}
+
+// FieldVisit is the evidence of a validator visiting the borrower: the
+// position the field app reported and the photos taken there.
+type FieldVisit struct {
+	Location  *GeoPoint
+	VisitedAt *time.Time
+	Photos    []FieldPhoto
+}
+
+// FieldVisitPolicy bounds how far from the registered address and how long
+// before the approval the evidence may be, a zero value disables the rule.
+type FieldVisitPolicy struct {
+	MaxDistance float64
+	MaxAge      time.Duration
+}
+
+// RecordVisit stores the visit evidence on the approval and flags what does
+// not hold up against the borrower's registered address. The photo kept is
+// the one furthest from the address, that is the one worth a second look.
+func (a *Approval) RecordVisit(visit FieldVisit, address *GeoPoint, policy FieldVisitPolicy) {
+	a.Flags = []string{}
+	if address == nil {
+		a.flag(ApprovalFlagAddressLocationMissing)
+	}
+
+	if visit.Location == nil {
+		a.flag(ApprovalFlagVisitLocationMissing)
+	} else {
+		a.VisitLatitude = &visit.Location.Latitude
+		a.VisitLongitude = &visit.Location.Longitude
+
+		if address != nil && policy.MaxDistance > 0 && visit.Location.DistanceTo(*address) > policy.MaxDistance {
+			a.flag(ApprovalFlagVisitFarFromAddress)
+		}
+	}
+
+	a.VisitedAt = visit.VisitedAt
+	if visit.VisitedAt == nil || a.stale(*visit.VisitedAt, policy) {
+		a.flag(ApprovalFlagVisitStale)
+	}
+
+	var photo *FieldPhoto
+	for index := range visit.Photos {
+		candidate := &visit.Photos[index]
+		if candidate.TakenAt != nil && a.stale(*candidate.TakenAt, policy) {
+			a.flag(ApprovalFlagPhotoStale)
+		}
+
+		if candidate.Location == nil {
+			continue
+		}
+
+		if photo == nil || (address != nil && candidate.Location.DistanceTo(*address) > photo.Location.DistanceTo(*address)) {
+			photo = candidate
+		}
+	}
+
+	if photo == nil {
+		a.flag(ApprovalFlagPhotoLocationMissing)
+		return
+	}
+
+	a.PhotoLatitude = &photo.Location.Latitude
+	a.PhotoLongitude = &photo.Location.Longitude
+	a.PhotoTakenAt = photo.TakenAt
+
+	if address != nil {
+		distance := math.Round(photo.Location.DistanceTo(*address))
+		a.PhotoDistance = &distance
+
+		if policy.MaxDistance > 0 && distance > policy.MaxDistance {
+			a.flag(ApprovalFlagPhotoFarFromAddress)
+		}
+	}
+}
+
+func (a *Approval) Flagged() bool {
+	return len(a.Flags) > 0
+}
+
+// stale tells whether evidence was captured too long before the approval,
+// or claims a time as far after it, camera clocks are not always right
+func (a *Approval) stale(at time.Time, policy FieldVisitPolicy) bool {
+	if policy.MaxAge <= 0 {
+		return false
+	}
+
+	age := a.CreatedAt.Sub(at)
+	return age > policy.MaxAge || age < -policy.MaxAge
+}
+
+func (a *Approval) flag(reason string) {
+	for _, flag := range a.Flags {
+		if flag == reason {
+			return
+		}
+	}
+
+	a.Flags = append(a.Flags, reason)
 }
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ApprovalTier asks loans above the amount for a sign-off by the role, on
+// top of the field validator approval
+type ApprovalTier struct {
+	AboveAmount float64
+	Role        UserRole
+}
+
+// ApprovalPolicy lists the tiers a loan climbs with its amount, no tier
+// means the field validator approves alone.
+type ApprovalPolicy struct {
+	Tiers []ApprovalTier
+}
+
+// RequiredRoles are the sign-offs the loan needs, each role once
+func (p ApprovalPolicy) RequiredRoles(loan *Loan) []UserRole {
+	required := map[UserRole]bool{}
+	roles := []UserRole{}
+	for _, tier := range p.Tiers {
+		if loan.ProposedAmount > tier.AboveAmount && !required[tier.Role] {
+			required[tier.Role] = true
+			roles = append(roles, tier.Role)
+		}
+	}
+
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	return roles
+}
+
+// ApprovalSignOff is the second pair of eyes on a large loan, a senior
+// validator or the credit committee confirming the field approval.
+type ApprovalSignOff struct {
+	bun.BaseModel `bun:"table:approval_sign_offs"`
+
+	ID         uint      `bun:"id,pk,nullzero"`
+	UUID       uuid.UUID `bun:"uuid"`
+	ApprovalID uint      `bun:"approval_id"`
+	LoanID     uint      `bun:"loan_id"`
+	ApproverID uint      `bun:"approver_id"`
+	Role       UserRole  `bun:"role"`
+	Note       string    `bun:"note"`
+	CreatedAt  time.Time `bun:"created_at"`
+}
+
+// NewApprovalSignOff checks the four-eyes rules: the sign-off must be one
+// the approval still waits for, and nobody signs a loan they proposed,
+// approved in the field or signed already.
+func NewApprovalSignOff(loan *Loan, signOffs []ApprovalSignOff, approverID uint, role UserRole, note string) (*ApprovalSignOff, error) {
+	if loan.Status != LoanStatusPendingApproval || loan.Approval == nil {
+		return nil, errors.New("only_pending_approval_loan_allowed")
+	}
+
+	if approverID == loan.BorrowerID || approverID == loan.ProposedBy {
+		return nil, errors.New("approver_is_proposer")
+	}
+
+	if approverID == loan.Approval.FieldValidatorID {
+		return nil, errors.New("loan_already_approved_by_approver")
+	}
+
+	for _, signOff := range signOffs {
+		if signOff.ApproverID == approverID {
+			return nil, errors.New("loan_already_approved_by_approver")
+		}
+	}
+
+	missing := false
+	for _, missingRole := range loan.Approval.MissingRoles(signOffs) {
+		missing = missing || missingRole == role
+	}
+
+	if !missing {
+		return nil, errors.New("sign_off_not_required")
+	}
+
+	return &ApprovalSignOff{
+		UUID:       uuid.New(),
+		ApprovalID: loan.Approval.ID,
+		LoanID:     loan.ID,
+		ApproverID: approverID,
+		Role:       role,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// MissingRoles are the sign-offs the approval still waits for
+func (a *Approval) MissingRoles(signOffs []ApprovalSignOff) []UserRole {
+	signed := map[UserRole]bool{}
+	for _, signOff := range signOffs {
+		signed[signOff.Role] = true
+	}
+
+	missing := []UserRole{}
+	for _, role := range a.RequiredRoles {
+		if !signed[UserRole(role)] {
+			missing = append(missing, UserRole(role))
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Audited actions, named after the entity and what was done to it
+const (
+	AuditActionLoanPropose              = "loan.propose"
+	AuditActionLoanApprove              = "loan.approve"
+	AuditActionLoanSignOff              = "loan.sign_off"
+	AuditActionLoanInvest               = "loan.invest"
+	AuditActionLoanDisburse             = "loan.disburse"
+	AuditActionLoanCompleteDisbursement = "loan.complete_disbursement"
+	AuditActionLoanCancel               = "loan.cancel"
+	AuditActionLoanExpire               = "loan.expire"
+	AuditActionLoanRepay                = "loan.repay"
+	AuditActionLoanAssessDelinquency    = "loan.assess_delinquency"
+	AuditActionLoanWriteOff             = "loan.write_off"
+	AuditActionLoanSettle               = "loan.settle"
+	AuditActionLoanCreateVirtualAccount = "loan.create_virtual_account"
+
+	AuditActionRestructuringRequest = "restructuring.request"
+	AuditActionRestructuringApprove = "restructuring.approve"
+	AuditActionRestructuringReject  = "restructuring.reject"
+
+	AuditActionWalletDeposit  = "wallet.deposit"
+	AuditActionWalletWithdraw = "wallet.withdraw"
+	AuditActionWalletRefund   = "wallet.refund"
+
+	AuditActionAutoInvestPlanCreate = "auto_invest_plan.create"
+	AuditActionAutoInvestPlanUpdate = "auto_invest_plan.update"
+	AuditActionAutoInvestPlanDelete = "auto_invest_plan.delete"
+
+	AuditActionInvestmentListingCreate = "investment_listing.create"
+	AuditActionInvestmentListingCancel = "investment_listing.cancel"
+	AuditActionInvestmentListingBuy    = "investment_listing.buy"
+
+	AuditActionRateCardCreate     = "rate_card.create"
+	AuditActionRateCardUpdate     = "rate_card.update"
+	AuditActionRateCardDeactivate = "rate_card.deactivate"
+
+	AuditActionLoanProductCreate = "loan_product.create"
+	AuditActionLoanProductUpdate = "loan_product.update"
+
+	AuditActionWebhookCreate    = "webhook.create"
+	AuditActionWebhookUpdate    = "webhook.update"
+	AuditActionWebhookDelete    = "webhook.delete"
+	AuditActionWebhookRedeliver = "webhook.redeliver"
+
+	AuditActionBorrowerGroupCreate       = "borrower_group.create"
+	AuditActionBorrowerGroupAddMember    = "borrower_group.add_member"
+	AuditActionBorrowerGroupRemoveMember = "borrower_group.remove_member"
+	AuditActionBorrowerGroupChangeLeader = "borrower_group.change_leader"
+
+	AuditActionGroupLoanPropose = "group_loan.propose"
+	AuditActionGroupLoanApprove = "group_loan.approve"
+	AuditActionGroupLoanCollect = "group_loan.collect"
+)
+
+const (
+	AuditEntityLoan              = "loan"
+	AuditEntityInvestment        = "investment"
+	AuditEntityPayment           = "payment"
+	AuditEntityRestructuring     = "restructuring"
+	AuditEntityWalletTransaction = "wallet_transaction"
+	AuditEntityAutoInvestPlan    = "auto_invest_plan"
+	AuditEntityInvestmentListing = "investment_listing"
+	AuditEntityRateCard          = "rate_card"
+	AuditEntityLoanProduct       = "loan_product"
+	AuditEntityWebhook           = "webhook"
+	AuditEntityWebhookDelivery   = "webhook_delivery"
+	AuditEntityBorrowerGroup     = "borrower_group"
+	AuditEntityGroupLoan         = "group_loan"
+	AuditEntityGroupCollection   = "group_collection"
+)
+
+// AuditEntry is a state change as the usecase making it sees it, the
+// snapshots are the public representation of the entity.
+type AuditEntry struct {
+	Action       string
+	EntityType   string
+	EntityID     string
+	LoanID       *uint
+	InvestmentID *uint
+	Before       interface{}
+	After        interface{}
+}
+
+// AuditLog is one link of the append-only audit chain. Every entry hashes
+// the one before it, so editing or removing any past entry breaks every
+// hash after it.
+type AuditLog struct {
+	bun.BaseModel `bun:"table:audit_logs"`
+
+	ID           uint      `bun:"id,pk,nullzero"`
+	UUID         uuid.UUID `bun:"uuid"`
+	ActorID      *uint     `bun:"actor_id"`
+	ActorRole    UserRole  `bun:"actor_role"`
+	Action       string    `bun:"action"`
+	EntityType   string    `bun:"entity_type"`
+	EntityID     string    `bun:"entity_id"`
+	LoanID       *uint     `bun:"loan_id"`
+	InvestmentID *uint     `bun:"investment_id"`
+	Before       string    `bun:"before,type:json,nullzero"`
+	After        string    `bun:"after,type:json,nullzero"`
+	RequestID    string    `bun:"request_id"`
+	ClientIP     string    `bun:"client_ip"`
+	PrevHash     string    `bun:"prev_hash"`
+	Hash         string    `bun:"hash"`
+	CreatedAt    time.Time `bun:"created_at"`
+}
+
+// NewAuditLog freezes the entry, the snapshots are serialized right away
+// and the time is cut to what the database keeps so the hash still holds
+// once read back.
+func NewAuditLog(entry *AuditEntry, actorID *uint, actorRole UserRole, requestID string, clientIP string) (*AuditLog, error) {
+	before, err := auditSnapshot(entry.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := auditSnapshot(entry.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditLog{
+		UUID:         uuid.New(),
+		ActorID:      actorID,
+		ActorRole:    actorRole,
+		Action:       entry.Action,
+		EntityType:   entry.EntityType,
+		EntityID:     entry.EntityID,
+		LoanID:       entry.LoanID,
+		InvestmentID: entry.InvestmentID,
+		Before:       before,
+		After:        after,
+		RequestID:    requestID,
+		ClientIP:     clientIP,
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+// Chain links the entry to the last one of the log
+func (l *AuditLog) Chain(prevHash string) {
+	l.PrevHash = prevHash
+	l.Hash = l.ComputeHash()
+}
+
+// Intact tells whether the entry still is what was chained after prevHash
+func (l *AuditLog) Intact(prevHash string) bool {
+	return l.PrevHash == prevHash && l.Hash == l.ComputeHash()
+}
+
+// ComputeHash hashes every recorded field, each one length prefixed so no
+// two different entries read the same.
+func (l *AuditLog) ComputeHash() string {
+	fields := []string{
+		l.PrevHash,
+		l.UUID.String(),
+		auditOptionalID(l.ActorID),
+		strconv.Itoa(int(l.ActorRole)),
+		l.Action,
+		l.EntityType,
+		l.EntityID,
+		auditOptionalID(l.LoanID),
+		auditOptionalID(l.InvestmentID),
+		l.Before,
+		l.After,
+		l.RequestID,
+		l.ClientIP,
+		l.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	hash := sha256.New()
+	for _, field := range fields {
+		fmt.Fprintf(hash, "%d:%s", len(field), field)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// AuditSnapshot captures an entity before it is changed in place
+func AuditSnapshot(value interface{}) json.RawMessage {
+	snapshot, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	return snapshot
+}
+
+func auditSnapshot(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	if snapshot, ok := value.(json.RawMessage); ok {
+		return string(snapshot), nil
+	}
+
+	snapshot, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(snapshot), nil
+}
+
+func auditOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// AuditVerification is the outcome of walking the chain from its first entry
+type AuditVerification struct {
+	Checked  int
+	Intact   bool
+	BrokenAt *uuid.UUID
+}
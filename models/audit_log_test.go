@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+)
+
+func newTestAuditChain(t *testing.T) []*AuditLog {
+	t.Helper()
+
+	entries := []*AuditEntry{
+		{Action: AuditActionLoanApprove, EntityType: AuditEntityLoan, EntityID: "loan-1", LoanID: ptr.Of(uint(1)), Before: map[string]string{"status": "proposed"}, After: map[string]string{"status": "approved"}},
+		{Action: AuditActionWalletDeposit, EntityType: AuditEntityWalletTransaction, EntityID: "tx-1", After: json.RawMessage(`{"amount":1000}`)},
+		{Action: AuditActionLoanCancel, EntityType: AuditEntityLoan, EntityID: "loan-1", LoanID: ptr.Of(uint(1))},
+	}
+
+	logs := []*AuditLog{}
+	prevHash := ""
+	for _, entry := range entries {
+		log, err := NewAuditLog(entry, ptr.Of(uint(5)), RoleAdmin, "request-1", "10.0.0.1")
+		if err != nil {
+			t.Fatalf("new audit log: %v", err)
+		}
+
+		log.Chain(prevHash)
+		prevHash = log.Hash
+		logs = append(logs, log)
+	}
+
+	return logs
+}
+
+func TestAuditLogChain(t *testing.T) {
+	logs := newTestAuditChain(t)
+
+	prevHash := ""
+	for index, log := range logs {
+		if !log.Intact(prevHash) {
+			t.Errorf("entry %d not intact after chaining", index)
+		}
+
+		if log.CreatedAt.Nanosecond()%1000 != 0 {
+			t.Errorf("entry %d created at %v, want microsecond precision", index, log.CreatedAt)
+		}
+		prevHash = log.Hash
+	}
+
+	if logs[0].Before != `{"status":"proposed"}` || logs[1].After != `{"amount":1000}` || logs[2].Before != "" {
+		t.Errorf("snapshots = %q %q %q, want serialized values and empty for none", logs[0].Before, logs[1].After, logs[2].Before)
+	}
+}
+
+func TestAuditLogTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(logs []*AuditLog)
+		broken int
+	}{
+		{name: "edited snapshot", tamper: func(logs []*AuditLog) { logs[1].After = `{"amount":9000}` }, broken: 1},
+		{name: "edited actor", tamper: func(logs []*AuditLog) { logs[0].ActorID = ptr.Of(uint(6)) }, broken: 0},
+		{name: "backdated entry", tamper: func(logs []*AuditLog) { logs[2].CreatedAt = logs[2].CreatedAt.Add(-time.Hour) }, broken: 2},
+		{name: "rehashed edit", tamper: func(logs []*AuditLog) {
+			logs[0].Action = AuditActionLoanCancel
+			logs[0].Hash = logs[0].ComputeHash()
+		}, broken: 1},
+		{name: "removed entry", tamper: func(logs []*AuditLog) { copy(logs[1:], logs[2:]) }, broken: 1},
+		{name: "moved fields", tamper: func(logs []*AuditLog) {
+			// Length prefixes keep shifted field boundaries from hashing alike
+			logs[1].EntityType, logs[1].EntityID = logs[1].EntityType+logs[1].EntityID[:2], logs[1].EntityID[2:]
+		}, broken: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := newTestAuditChain(t)
+			tt.tamper(logs)
+
+			prevHash := ""
+			for index, log := range logs {
+				if !log.Intact(prevHash) {
+					if index != tt.broken {
+						t.Errorf("chain broken at %d, want %d", index, tt.broken)
+					}
+					return
+				}
+				prevHash = log.Hash
+			}
+
+			t.Errorf("tampered chain verified intact")
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// AutoInvestPlan lets the platform place tickets for an investor on every
+// approved loan matching the filters, until the budget is used up.
+type AutoInvestPlan struct {
+	bun.BaseModel `bun:"table:auto_invest_plans"`
+
+	ID             uint       `bun:"id,pk,nullzero"`
+	UUID           uuid.UUID  `bun:"uuid"`
+	InvestorID     uint       `bun:"investor_id"`
+	Budget         float64    `bun:"budget"`
+	InvestedAmount float64    `bun:"invested_amount"`
+	AmountPerLoan  float64    `bun:"amount_per_loan"`
+	MinRate        *float64   `bun:"min_rate"`
+	MaxRate        *float64   `bun:"max_rate"`
+	Tenors         []int      `bun:"tenors,array"`
+	RiskGrades     []string   `bun:"risk_grades,array"`
+	Regions        []string   `bun:"regions,array"`
+	Active         bool       `bun:"active"`
+	LastMatchedAt  *time.Time `bun:"last_matched_at,nullzero"`
+	CreatedAt      time.Time  `bun:"created_at"`
+	UpdatedAt      *time.Time `bun:"updated_at,nullzero"`
+}
+
+// AutoInvestMatchFailure keeps why matching could not place a ticket on an
+// approved loan, for the whole loan when PlanID is nil.
+type AutoInvestMatchFailure struct {
+	bun.BaseModel `bun:"table:auto_invest_match_failures"`
+
+	ID        uint      `bun:"id,pk,nullzero"`
+	LoanID    uint      `bun:"loan_id"`
+	PlanID    *uint     `bun:"plan_id"`
+	Amount    float64   `bun:"amount"`
+	Error     string    `bun:"error"`
+	CreatedAt time.Time `bun:"created_at"`
+}
+
+func NewAutoInvestPlan(investorID uint, budget float64, amountPerLoan float64) *AutoInvestPlan {
+	return &AutoInvestPlan{
+		UUID:          uuid.New(),
+		InvestorID:    investorID,
+		Budget:        budget,
+		AmountPerLoan: amountPerLoan,
+		Active:        true,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func (p *AutoInvestPlan) RemainingBudget() float64 {
+	return p.Budget - p.InvestedAmount
+}
+
+// Matches tells whether the plan wants a ticket on the loan, an empty filter
+// list accepts every value.
+func (p *AutoInvestPlan) Matches(loan *Loan, borrower *User) bool {
+	if p.MinRate != nil && loan.Rate < *p.MinRate {
+		return false
+	}
+
+	if p.MaxRate != nil && loan.Rate > *p.MaxRate {
+		return false
+	}
+
+	if len(p.Tenors) > 0 {
+		found := false
+		for _, tenor := range p.Tenors {
+			if tenor == loan.Tenor {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return matchesRule(p.RiskGrades, loan.RiskGrade) && matchesRule(p.Regions, borrower.Region)
+}
+
+// TicketFor sizes the ticket for the loan, zero meaning the plan cannot take part
+func (p *AutoInvestPlan) TicketFor(loan *Loan) float64 {
+	return max(0, min(p.AmountPerLoan, p.RemainingBudget(), loan.RemainingAmount()))
+}
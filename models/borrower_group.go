@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// BorrowerGroup is a majelis, borrowers meeting with their field officer and
+// jointly liable for the group loans of each other. The leader is one of the
+// members and speaks for the group.
+type BorrowerGroup struct {
+	bun.BaseModel `bun:"table:borrower_groups"`
+
+	ID             uint       `bun:"id,pk,nullzero"`
+	UUID           uuid.UUID  `bun:"uuid"`
+	Name           string     `bun:"name"`
+	Region         string     `bun:"region"`
+	LeaderID       uint       `bun:"leader_id"`
+	FieldOfficerID uint       `bun:"field_officer_id"`
+	CreatedAt      time.Time  `bun:"created_at"`
+	UpdatedAt      *time.Time `bun:"updated_at,nullzero"`
+
+	Members []BorrowerGroupMember `bun:"rel:has-many,join:id=group_id"`
+}
+
+type BorrowerGroupMember struct {
+	bun.BaseModel `bun:"table:borrower_group_members"`
+
+	ID         uint      `bun:"id,pk,nullzero"`
+	GroupID    uint      `bun:"group_id"`
+	BorrowerID uint      `bun:"borrower_id"`
+	CreatedAt  time.Time `bun:"created_at"`
+
+	Borrower *User `bun:"rel:has-one,join:borrower_id=id"`
+}
+
+func NewBorrowerGroup(name string, region string, fieldOfficerID uint, leaderID uint, memberIDs []uint) (*BorrowerGroup, error) {
+	if name == "" {
+		return nil, errors.New("invalid_borrower_group")
+	}
+
+	now := time.Now()
+	group := &BorrowerGroup{
+		UUID:           uuid.New(),
+		Name:           name,
+		Region:         region,
+		LeaderID:       leaderID,
+		FieldOfficerID: fieldOfficerID,
+		CreatedAt:      now,
+	}
+
+	for _, memberID := range memberIDs {
+		if group.HasMember(memberID) {
+			return nil, errors.New("invalid_borrower_group")
+		}
+
+		group.Members = append(group.Members, BorrowerGroupMember{
+			BorrowerID: memberID,
+			CreatedAt:  now,
+		})
+	}
+
+	if !group.HasMember(leaderID) {
+		return nil, errors.New("group_leader_not_member")
+	}
+
+	return group, nil
+}
+
+func (g *BorrowerGroup) HasMember(borrowerID uint) bool {
+	for _, member := range g.Members {
+		if member.BorrowerID == borrowerID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *BorrowerGroup) Member(borrowerID uint) *BorrowerGroupMember {
+	for index := range g.Members {
+		if g.Members[index].BorrowerID == borrowerID {
+			return &g.Members[index]
+		}
+	}
+
+	return nil
+}
+
+// ChangeLeader hands the group over to another of its members
+func (g *BorrowerGroup) ChangeLeader(borrowerID uint) error {
+	if !g.HasMember(borrowerID) {
+		return errors.New("group_leader_not_member")
+	}
+
+	now := time.Now()
+	g.LeaderID = borrowerID
+	g.UpdatedAt = &now
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+const (
+	DelinquencyBucketCurrent = "current"
+	DelinquencyBucket1To30   = "1-30"
+	DelinquencyBucket31To60  = "31-60"
+	DelinquencyBucket61To90  = "61-90"
+	DelinquencyBucketOver90  = "90+"
+)
+
+var DelinquencyBuckets = map[string]bool{
+	DelinquencyBucketCurrent: true,
+	DelinquencyBucket1To30:   true,
+	DelinquencyBucket31To60:  true,
+	DelinquencyBucket61To90:  true,
+	DelinquencyBucketOver90:  true,
+}
+
+func DelinquencyBucketFor(daysPastDue int) string {
+	switch {
+	case daysPastDue <= 0:
+		return DelinquencyBucketCurrent
+	case daysPastDue <= 30:
+		return DelinquencyBucket1To30
+	case daysPastDue <= 60:
+		return DelinquencyBucket31To60
+	case daysPastDue <= 90:
+		return DelinquencyBucket61To90
+	default:
+		return DelinquencyBucketOver90
+	}
+}
+
+// DelinquencyPolicy decides when an overdue loan is charged a late fee and
+// when it turns delinquent or defaulted, a zero value disables the rule.
+type DelinquencyPolicy struct {
+	LateFeePercent      float64
+	LateFeeGraceDays    int
+	DelinquentAfterDays int
+	DefaultAfterDays    int
+}
+
+// Assess recomputes the days past due of the loan from its schedule and
+// charges the late fee, once, on installments overdue beyond the grace days.
+// It returns whether any installment was charged.
+func (p DelinquencyPolicy) Assess(loan *Loan, installments []Installment, asOf time.Time) bool {
+	charged := false
+	daysPastDue := 0
+	overdue := 0.0
+
+	for index := range installments {
+		installment := &installments[index]
+
+		days := installment.DaysPastDue(asOf)
+		if days == 0 {
+			continue
+		}
+
+		if p.LateFeePercent > 0 && days > p.LateFeeGraceDays && installment.LateFee == 0 {
+			installment.LateFee = roundAmount(installment.Outstanding() * p.LateFeePercent / 100)
+			installment.UpdatedAt = &asOf
+			charged = true
+		}
+
+		daysPastDue = max(daysPastDue, days)
+		overdue += installment.Outstanding()
+	}
+
+	loan.MarkDelinquency(daysPastDue, roundAmount(overdue), p)
+
+	return charged
+}
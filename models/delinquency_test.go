@@ -0,0 +1,129 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelinquencyPolicyAssess(t *testing.T) {
+	policy := DelinquencyPolicy{
+		LateFeePercent:      5,
+		LateFeeGraceDays:    3,
+		DelinquentAfterDays: 1,
+		DefaultAfterDays:    90,
+	}
+
+	tests := []struct {
+		name            string
+		paid            float64
+		lateFee         float64
+		asOf            time.Time
+		wantCharged     bool
+		wantLateFee     float64
+		wantDaysPastDue int
+		wantBucket      string
+		wantOverdue     float64
+		wantStatus      LoanStatus
+	}{
+		{
+			name:       "on the due date",
+			asOf:       date(2024, time.March, 1),
+			wantBucket: DelinquencyBucketCurrent,
+			wantStatus: LoanStatusDisbursed,
+		},
+		{
+			name:            "within the grace days",
+			asOf:            date(2024, time.March, 3),
+			wantDaysPastDue: 2,
+			wantBucket:      DelinquencyBucket1To30,
+			wantOverdue:     1100,
+			wantStatus:      LoanStatusDelinquent,
+		},
+		{
+			name:            "charged after the grace days",
+			asOf:            date(2024, time.March, 10),
+			wantCharged:     true,
+			wantLateFee:     55,
+			wantDaysPastDue: 9,
+			wantBucket:      DelinquencyBucket1To30,
+			wantOverdue:     1155,
+			wantStatus:      LoanStatusDelinquent,
+		},
+		{
+			name:            "charged on what a partly paid installment still owes",
+			paid:            600,
+			asOf:            date(2024, time.March, 10),
+			wantCharged:     true,
+			wantLateFee:     25,
+			wantDaysPastDue: 9,
+			wantBucket:      DelinquencyBucket1To30,
+			wantOverdue:     525,
+			wantStatus:      LoanStatusDelinquent,
+		},
+		{
+			name:            "charged only once",
+			lateFee:         55,
+			asOf:            date(2024, time.April, 10),
+			wantLateFee:     55,
+			wantDaysPastDue: 40,
+			wantBucket:      DelinquencyBucket31To60,
+			wantOverdue:     1155,
+			wantStatus:      LoanStatusDelinquent,
+		},
+		{
+			name:            "defaulted past the default days",
+			lateFee:         55,
+			asOf:            date(2024, time.June, 1),
+			wantLateFee:     55,
+			wantDaysPastDue: 92,
+			wantBucket:      DelinquencyBucketOver90,
+			wantOverdue:     1155,
+			wantStatus:      LoanStatusDefaulted,
+		},
+		{
+			name:       "paid installment is never past due",
+			paid:       1100,
+			asOf:       date(2024, time.April, 10),
+			wantBucket: DelinquencyBucketCurrent,
+			wantStatus: LoanStatusDisbursed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{Status: LoanStatusDisbursed}
+			installments := []Installment{{
+				DueDate:         date(2024, time.March, 1),
+				PrincipalAmount: 1000,
+				InterestAmount:  100,
+				LateFee:         tt.lateFee,
+				PaidAmount:      tt.paid,
+			}}
+
+			charged := policy.Assess(loan, installments, tt.asOf)
+			if charged != tt.wantCharged {
+				t.Errorf("charged = %v, want %v", charged, tt.wantCharged)
+			}
+
+			if installments[0].LateFee != tt.wantLateFee {
+				t.Errorf("late fee = %v, want %v", installments[0].LateFee, tt.wantLateFee)
+			}
+
+			if loan.DaysPastDue != tt.wantDaysPastDue {
+				t.Errorf("days past due = %v, want %v", loan.DaysPastDue, tt.wantDaysPastDue)
+			}
+
+			if loan.DelinquencyBucket != tt.wantBucket {
+				t.Errorf("bucket = %v, want %v", loan.DelinquencyBucket, tt.wantBucket)
+			}
+
+			if loan.OverdueAmount != tt.wantOverdue {
+				t.Errorf("overdue amount = %v, want %v", loan.OverdueAmount, tt.wantOverdue)
+			}
+
+			if loan.Status != tt.wantStatus {
+				t.Errorf("status = %v, want %v", loan.Status, tt.wantStatus)
+			}
+		})
+	}
+}
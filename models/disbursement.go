@@ -7,11 +7,14 @@ import (
 )
 
 type Disbursment struct {
-	bun.BaseModel `bun:"table:dibursements"`
+	bun.BaseModel `bun:"table:disbursements"`
 
-	ID                uint       `bun:"id,pk,nullzero"`
-	FieldOfficerID    uint       `bun:"field_officer_id"`
-	AggreementFileURL string     `bun:"aggreement_file_url"`
-	CreatedAt         time.Time  `bun:"created_at"`
-	UpdatedAt         *time.Time `bun:"updated_at,nullzero"`
+	ID                     uint       `bun:"id,pk,nullzero"`
+	FieldOfficerID         uint       `bun:"field_officer_id"`
+	AggreementFileURL      string     `bun:"aggreement_file_url"`
+	AggreementFileChecksum string     `bun:"aggreement_file_checksum"`
+	CreatedAt              time.Time  `bun:"created_at"`
+	UpdatedAt              *time.Time `bun:"updated_at,nullzero"`
+
+	Documents []Document `bun:"rel:has-many,join:id=disbursement_id"`
 }
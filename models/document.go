@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type DocumentType string
+
+const (
+	DocumentTypeLoanAgreement         DocumentType = "loan_agreement"
+	DocumentTypeApprovalProof         DocumentType = "approval_proof"
+	DocumentTypeDisbursementAgreement DocumentType = "disbursement_agreement"
+	DocumentTypeHousePhoto            DocumentType = "house_photo"
+	DocumentTypeBusinessPhoto         DocumentType = "business_photo"
+	DocumentTypeSignedAgreement       DocumentType = "signed_agreement"
+	DocumentTypeIDCard                DocumentType = "id_card"
+)
+
+// Evidence a field validator may attach when approving a loan
+var ApprovalDocumentTypes = map[DocumentType]bool{
+	DocumentTypeApprovalProof: true,
+	DocumentTypeHousePhoto:    true,
+	DocumentTypeBusinessPhoto: true,
+	DocumentTypeIDCard:        true,
+}
+
+// Evidence a field officer may attach when disbursing a loan
+var DisbursementDocumentTypes = map[DocumentType]bool{
+	DocumentTypeDisbursementAgreement: true,
+	DocumentTypeSignedAgreement:       true,
+	DocumentTypeIDCard:                true,
+}
+
+type Document struct {
+	bun.BaseModel `bun:"table:documents"`
+
+	ID             uint         `bun:"id,pk,nullzero"`
+	UUID           uuid.UUID    `bun:"uuid"`
+	LoanID         uint         `bun:"loan_id"`
+	ApprovalID     *uint        `bun:"approval_id"`
+	DisbursementID *uint        `bun:"disbursement_id"`
+	Type           DocumentType `bun:"type"`
+	FileURL        string       `bun:"file_url"`
+	Checksum       string       `bun:"checksum"`
+	ContentType    string       `bun:"content_type"`
+	Size           int64        `bun:"size"`
+	UploadedBy     uint         `bun:"uploaded_by"`
+	CreatedAt      time.Time    `bun:"created_at"`
+
+	Loan *Loan `bun:"rel:has-one,join:loan_id=id"`
+}
+
+func NewDocument(
+	loanID uint,
+	documentType DocumentType,
+	fileUrl string,
+	checksum string,
+	contentType string,
+	size int64,
+	uploadedBy uint,
+) *Document {
+	return &Document{
+		UUID:        uuid.New(),
+		LoanID:      loanID,
+		Type:        documentType,
+		FileURL:     fileUrl,
+		Checksum:    checksum,
+		ContentType: contentType,
+		Size:        size,
+		UploadedBy:  uploadedBy,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+type SyncRecordStatus string
+
+const (
+	SyncRecordApplied   SyncRecordStatus = "applied"
+	SyncRecordDuplicate SyncRecordStatus = "duplicate"
+	SyncRecordConflict  SyncRecordStatus = "conflict"
+)
+
+// SyncRecordResult is the outcome of one collection record of an offline
+// bundle, Reason explains a conflict.
+type SyncRecordResult struct {
+	RecordID string
+	Status   SyncRecordStatus
+	Reason   string
+}
+
+// LoanPosition is what the field app needs to keep collecting a loan offline
+type LoanPosition struct {
+	Loan          *Loan
+	Outstanding   float64
+	NextDueDate   *time.Time
+	NextDueAmount float64
+}
+
+func NewLoanPosition(loan *Loan, installments []Installment) LoanPosition {
+	position := LoanPosition{
+		Loan: loan,
+	}
+
+	for index := range installments {
+		installment := &installments[index]
+		if installment.Paid() {
+			continue
+		}
+
+		position.Outstanding += installment.Outstanding()
+		if position.NextDueDate == nil {
+			position.NextDueDate = &installment.DueDate
+			position.NextDueAmount = installment.Outstanding()
+		}
+	}
+	position.Outstanding = roundAmount(position.Outstanding)
+
+	return position
+}
+
+// SyncReport answers an offline bundle with the outcome of every record and
+// the up to date positions of the loans of the officer.
+type SyncReport struct {
+	Results []SyncRecordResult
+	Loans   []LoanPosition
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type GroupLoanStatus string
+
+const (
+	GroupLoanProposed GroupLoanStatus = "proposed"
+	GroupLoanApproved GroupLoanStatus = "approved"
+)
+
+// GroupLoan is a joint liability loan of a borrower group. Every member gets
+// a sub-loan of their own that is funded, disbursed and repaid like any loan,
+// but the group is approved as a whole and collected at its meetings.
+type GroupLoan struct {
+	bun.BaseModel `bun:"table:group_loans"`
+
+	ID         uint            `bun:"id,pk,nullzero"`
+	UUID       uuid.UUID       `bun:"uuid"`
+	GroupID    uint            `bun:"group_id"`
+	ProposedBy uint            `bun:"proposed_by"`
+	ApprovedBy *uint           `bun:"approved_by"`
+	Status     GroupLoanStatus `bun:"status"`
+	ApprovedAt *time.Time      `bun:"approved_at,nullzero"`
+	CreatedAt  time.Time       `bun:"created_at"`
+	UpdatedAt  *time.Time      `bun:"updated_at,nullzero"`
+
+	Group *BorrowerGroup `bun:"rel:has-one,join:group_id=id"`
+	Loans []Loan         `bun:"rel:has-many,join:id=group_loan_id"`
+}
+
+func NewGroupLoan(group *BorrowerGroup, proposedBy uint) *GroupLoan {
+	return &GroupLoan{
+		UUID:       uuid.New(),
+		GroupID:    group.ID,
+		ProposedBy: proposedBy,
+		Status:     GroupLoanProposed,
+		CreatedAt:  time.Now(),
+		Group:      group,
+	}
+}
+
+// Loan is the sub-loan of a member, nil when the member has none
+func (g *GroupLoan) Loan(borrowerID uint) *Loan {
+	for index := range g.Loans {
+		if g.Loans[index].BorrowerID == borrowerID {
+			return &g.Loans[index]
+		}
+	}
+
+	return nil
+}
+
+func (g *GroupLoan) Approve(fieldValidatorID uint) {
+	now := time.Now()
+	g.Status = GroupLoanApproved
+	g.ApprovedBy = &fieldValidatorID
+	g.ApprovedAt = &now
+	g.UpdatedAt = &now
+}
+
+// GroupCollection is the cash a field officer collects at a group meeting,
+// split between the sub-loans of the members. Reference identifies the
+// meeting collection so recording it twice applies it once.
+type GroupCollection struct {
+	bun.BaseModel `bun:"table:group_collections"`
+
+	ID          uint      `bun:"id,pk,nullzero"`
+	UUID        uuid.UUID `bun:"uuid"`
+	GroupLoanID uint      `bun:"group_loan_id"`
+	CollectedBy uint      `bun:"collected_by"`
+	Reference   string    `bun:"reference"`
+	Amount      float64   `bun:"amount"`
+	MeetingDate time.Time `bun:"meeting_date"`
+	CreatedAt   time.Time `bun:"created_at"`
+
+	Allocations []GroupCollectionAllocation `bun:"rel:has-many,join:id=group_collection_id"`
+}
+
+// GroupCollectionAllocation is the part of a meeting collection repaying the
+// sub-loan of one member
+type GroupCollectionAllocation struct {
+	bun.BaseModel `bun:"table:group_collection_allocations"`
+
+	ID                uint    `bun:"id,pk,nullzero"`
+	GroupCollectionID uint    `bun:"group_collection_id"`
+	LoanID            uint    `bun:"loan_id"`
+	BorrowerID        uint    `bun:"borrower_id"`
+	Amount            float64 `bun:"amount"`
+}
+
+func NewGroupCollection(groupLoan *GroupLoan, collectedBy uint, reference string, amount float64, meetingDate time.Time) (*GroupCollection, error) {
+	if amount <= 0 {
+		return nil, errors.New("invalid_amount")
+	}
+
+	id := uuid.New()
+	if reference == "" {
+		reference = id.String()
+	}
+
+	return &GroupCollection{
+		UUID:        id,
+		GroupLoanID: groupLoan.ID,
+		CollectedBy: collectedBy,
+		Reference:   reference,
+		Amount:      roundAmount(amount),
+		MeetingDate: truncateDay(meetingDate),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Allocate splits the collection between the sub-loans in proportion to
+// what each member owes as of the meeting, the last one absorbing the
+// rounding. Collecting more than the group owes needs explicit allocations.
+func (c *GroupCollection) Allocate(loans []Loan, dues []float64) error {
+	total := 0.0
+	for _, due := range dues {
+		total += due
+	}
+	total = roundAmount(total)
+
+	if c.Amount > total {
+		return errors.New("group_collection_exceeds_due")
+	}
+
+	allocated := 0.0
+	last := -1
+	for index, due := range dues {
+		if due > 0 {
+			last = index
+		}
+	}
+
+	c.Allocations = nil
+	for index, loan := range loans {
+		if dues[index] <= 0 {
+			continue
+		}
+
+		amount := roundAmount(c.Amount * dues[index] / total)
+		if index == last {
+			amount = roundAmount(c.Amount - allocated)
+		}
+		allocated += amount
+
+		c.Allocations = append(c.Allocations, GroupCollectionAllocation{
+			LoanID:     loan.ID,
+			BorrowerID: loan.BorrowerID,
+			Amount:     amount,
+		})
+	}
+
+	return nil
+}
+
+// Payment is the repayment booked on the sub-loan of an allocation. It is
+// paid as of the meeting, and its gateway reference keeps a replayed
+// collection from paying twice.
+func (c *GroupCollection) Payment(allocation *GroupCollectionAllocation, method string) *Payment {
+	payment := NewPayment(allocation.LoanID, PaymentDirectionCollection, method, allocation.Amount)
+	payment.GatewayReference = c.UUID.String() + ":" + strconv.FormatUint(uint64(allocation.LoanID), 10)
+	payment.Complete()
+	if c.MeetingDate.Before(*payment.CompletedAt) {
+		payment.CompletedAt = &c.MeetingDate
+	}
+
+	return payment
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Installment is one due date of the loan repayment schedule. Late fees are
+// added on top of the principal and interest due and are repaid first with
+// the rest of the installment.
+type Installment struct {
+	bun.BaseModel `bun:"table:installments"`
+
+	ID              uint       `bun:"id,pk,nullzero"`
+	UUID            uuid.UUID  `bun:"uuid"`
+	LoanID          uint       `bun:"loan_id"`
+	Sequence        int        `bun:"sequence"`
+	DueDate         time.Time  `bun:"due_date"`
+	PrincipalAmount float64    `bun:"principal_amount"`
+	InterestAmount  float64    `bun:"interest_amount"`
+	LateFee         float64    `bun:"late_fee"`
+	PaidAmount      float64    `bun:"paid_amount"`
+	PaidAt          *time.Time `bun:"paid_at,nullzero"`
+	RestructuringID *uint      `bun:"restructuring_id"`
+	SupersededAt    *time.Time `bun:"superseded_at,nullzero"`
+	CreatedAt       time.Time  `bun:"created_at"`
+	UpdatedAt       *time.Time `bun:"updated_at,nullzero"`
+}
+
+// NewRepaymentSchedule splits principal and flat interest evenly over the
+// tenor, the last installment absorbs the rounding. The first installment
+// falls one period after the grace period following disbursement.
+func NewRepaymentSchedule(loan *Loan, disbursedAt time.Time) []Installment {
+	principal := loan.PrincipalAmount
+	interest := roundAmount(principal * loan.Rate / 100)
+
+	start := truncateDay(disbursedAt).AddDate(0, 0, loan.GracePeriodDays)
+	principalPerInstallment := roundAmount(principal / float64(loan.Tenor))
+	interestPerInstallment := roundAmount(interest / float64(loan.Tenor))
+
+	installments := make([]Installment, 0, loan.Tenor)
+	for sequence := 1; sequence <= loan.Tenor; sequence++ {
+		installment := Installment{
+			UUID:            uuid.New(),
+			LoanID:          loan.ID,
+			Sequence:        sequence,
+			DueDate:         loan.Frequency.DueDate(start, sequence),
+			PrincipalAmount: principalPerInstallment,
+			InterestAmount:  interestPerInstallment,
+			CreatedAt:       time.Now(),
+		}
+
+		if sequence == loan.Tenor {
+			installment.PrincipalAmount = roundAmount(principal - principalPerInstallment*float64(loan.Tenor-1))
+			installment.InterestAmount = roundAmount(interest - interestPerInstallment*float64(loan.Tenor-1))
+		}
+
+		installments = append(installments, installment)
+	}
+
+	return installments
+}
+
+func (i *Installment) AmountDue() float64 {
+	return i.PrincipalAmount + i.InterestAmount + i.LateFee
+}
+
+func (i *Installment) Outstanding() float64 {
+	return max(0, roundAmount(i.AmountDue()-i.PaidAmount))
+}
+
+// PaidInterest is the part of the repayments that went to interest, they
+// cover the late fee of an installment first, then its interest and its
+// principal last.
+func (i *Installment) PaidInterest() float64 {
+	return min(i.InterestAmount, max(0, i.PaidAmount-i.LateFee))
+}
+
+func (i *Installment) PaidPrincipal() float64 {
+	return min(i.PrincipalAmount, max(0, i.PaidAmount-i.LateFee-i.InterestAmount))
+}
+
+func (i *Installment) OutstandingLateFee() float64 {
+	return max(0, roundAmount(i.LateFee-i.PaidAmount))
+}
+
+func (i *Installment) OutstandingInterest() float64 {
+	return max(0, roundAmount(i.InterestAmount-i.PaidInterest()))
+}
+
+func (i *Installment) OutstandingPrincipal() float64 {
+	return max(0, roundAmount(i.PrincipalAmount-i.PaidPrincipal()))
+}
+
+func (i *Installment) Paid() bool {
+	return i.Outstanding() == 0
+}
+
+// DaysPastDue counts the full days since the due date, a paid installment
+// is never past due.
+func (i *Installment) DaysPastDue(asOf time.Time) int {
+	if i.Paid() {
+		return 0
+	}
+
+	return max(0, int(truncateDay(asOf).Sub(truncateDay(i.DueDate)).Hours()/24))
+}
+
+// Pay takes as much of the amount as the installment still owes and returns
+// what is left for the next one.
+func (i *Installment) Pay(amount float64, at time.Time) float64 {
+	paid := min(amount, i.Outstanding())
+	if paid <= 0 {
+		return amount
+	}
+
+	i.PaidAmount = roundAmount(i.PaidAmount + paid)
+	i.UpdatedAt = &at
+	if i.Paid() {
+		i.PaidAt = &at
+	}
+
+	return roundAmount(amount - paid)
+}
+
+// AllocateRepayment pays the installments oldest first and returns the part
+// of the amount exceeding everything owed.
+func AllocateRepayment(installments []Installment, amount float64, at time.Time) float64 {
+	for index := range installments {
+		if amount <= 0 {
+			break
+		}
+
+		amount = installments[index].Pay(amount, at)
+	}
+
+	return amount
+}
+
+// Supersede closes an unpaid installment replaced by a restructured schedule,
+// it stays on the loan as history.
+func (i *Installment) Supersede(at time.Time) {
+	i.SupersededAt = &at
+	i.UpdatedAt = &at
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func truncateDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNewRepaymentSchedule(t *testing.T) {
+	tests := []struct {
+		name          string
+		loan          Loan
+		disbursedAt   time.Time
+		wantPrincipal []float64
+		wantInterest  []float64
+		wantDueDates  []time.Time
+	}{
+		{
+			name:          "last installment absorbs the rounding",
+			loan:          Loan{PrincipalAmount: 1000000, Rate: 10, Tenor: 3, Frequency: InstallmentFrequencyMonthly},
+			disbursedAt:   time.Date(2024, time.January, 15, 13, 30, 0, 0, time.UTC),
+			wantPrincipal: []float64{333333.33, 333333.33, 333333.34},
+			wantInterest:  []float64{33333.33, 33333.33, 33333.34},
+			wantDueDates:  []time.Time{date(2024, time.February, 15), date(2024, time.March, 15), date(2024, time.April, 15)},
+		},
+		{
+			name:          "even split after the grace period",
+			loan:          Loan{PrincipalAmount: 1200000, Rate: 12, Tenor: 4, Frequency: InstallmentFrequencyWeekly, GracePeriodDays: 7},
+			disbursedAt:   date(2024, time.January, 1),
+			wantPrincipal: []float64{300000, 300000, 300000, 300000},
+			wantInterest:  []float64{36000, 36000, 36000, 36000},
+			wantDueDates:  []time.Time{date(2024, time.January, 15), date(2024, time.January, 22), date(2024, time.January, 29), date(2024, time.February, 5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installments := NewRepaymentSchedule(&tt.loan, tt.disbursedAt)
+			if len(installments) != tt.loan.Tenor {
+				t.Fatalf("got %d installments, want %d", len(installments), tt.loan.Tenor)
+			}
+
+			principal, interest := 0.0, 0.0
+			for index, installment := range installments {
+				if installment.Sequence != index+1 {
+					t.Errorf("installment %d sequence = %d", index, installment.Sequence)
+				}
+
+				if installment.PrincipalAmount != tt.wantPrincipal[index] {
+					t.Errorf("installment %d principal = %v, want %v", index, installment.PrincipalAmount, tt.wantPrincipal[index])
+				}
+
+				if installment.InterestAmount != tt.wantInterest[index] {
+					t.Errorf("installment %d interest = %v, want %v", index, installment.InterestAmount, tt.wantInterest[index])
+				}
+
+				if !installment.DueDate.Equal(tt.wantDueDates[index]) {
+					t.Errorf("installment %d due date = %v, want %v", index, installment.DueDate, tt.wantDueDates[index])
+				}
+
+				principal += installment.PrincipalAmount
+				interest += installment.InterestAmount
+			}
+
+			if roundAmount(principal) != tt.loan.PrincipalAmount {
+				t.Errorf("principal adds up to %v, want %v", roundAmount(principal), tt.loan.PrincipalAmount)
+			}
+
+			if want := roundAmount(tt.loan.PrincipalAmount * tt.loan.Rate / 100); roundAmount(interest) != want {
+				t.Errorf("interest adds up to %v, want %v", roundAmount(interest), want)
+			}
+		})
+	}
+}
+
+func TestInstallmentPartlyPaid(t *testing.T) {
+	tests := []struct {
+		name                 string
+		paid                 float64
+		wantLateFee          float64
+		wantInterest         float64
+		wantPrincipal        float64
+		wantOutstanding      float64
+		wantPaid             bool
+		wantDaysPastDueAfter int
+	}{
+		{name: "nothing paid", paid: 0, wantLateFee: 5, wantInterest: 10, wantPrincipal: 100, wantOutstanding: 115, wantDaysPastDueAfter: 4},
+		{name: "late fee partly paid", paid: 3, wantLateFee: 2, wantInterest: 10, wantPrincipal: 100, wantOutstanding: 112, wantDaysPastDueAfter: 4},
+		{name: "interest partly paid", paid: 12, wantLateFee: 0, wantInterest: 3, wantPrincipal: 100, wantOutstanding: 103, wantDaysPastDueAfter: 4},
+		{name: "principal partly paid", paid: 65, wantLateFee: 0, wantInterest: 0, wantPrincipal: 50, wantOutstanding: 50, wantDaysPastDueAfter: 4},
+		{name: "fully paid", paid: 115, wantLateFee: 0, wantInterest: 0, wantPrincipal: 0, wantOutstanding: 0, wantPaid: true, wantDaysPastDueAfter: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installment := Installment{
+				DueDate:         date(2024, time.March, 1),
+				PrincipalAmount: 100,
+				InterestAmount:  10,
+				LateFee:         5,
+				PaidAmount:      tt.paid,
+			}
+
+			if got := installment.OutstandingLateFee(); got != tt.wantLateFee {
+				t.Errorf("outstanding late fee = %v, want %v", got, tt.wantLateFee)
+			}
+
+			if got := installment.OutstandingInterest(); got != tt.wantInterest {
+				t.Errorf("outstanding interest = %v, want %v", got, tt.wantInterest)
+			}
+
+			if got := installment.OutstandingPrincipal(); got != tt.wantPrincipal {
+				t.Errorf("outstanding principal = %v, want %v", got, tt.wantPrincipal)
+			}
+
+			if got := installment.Outstanding(); got != tt.wantOutstanding {
+				t.Errorf("outstanding = %v, want %v", got, tt.wantOutstanding)
+			}
+
+			if got := installment.Paid(); got != tt.wantPaid {
+				t.Errorf("paid = %v, want %v", got, tt.wantPaid)
+			}
+
+			if got := installment.DaysPastDue(time.Date(2024, time.March, 5, 18, 0, 0, 0, time.UTC)); got != tt.wantDaysPastDueAfter {
+				t.Errorf("days past due = %v, want %v", got, tt.wantDaysPastDueAfter)
+			}
+		})
+	}
+}
+
+func TestAllocateRepayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      float64
+		wantLeft    float64
+		wantPaid    []float64
+		wantSettled []bool
+	}{
+		{
+			name:        "finishes the partly paid installment first",
+			amount:      50,
+			wantLeft:    0,
+			wantPaid:    []float64{80, 0},
+			wantSettled: []bool{false, false},
+		},
+		{
+			name:        "spills over to the next installment",
+			amount:      150,
+			wantLeft:    0,
+			wantPaid:    []float64{110, 70},
+			wantSettled: []bool{true, false},
+		},
+		{
+			name:        "returns what exceeds everything owed",
+			amount:      300,
+			wantLeft:    110,
+			wantPaid:    []float64{110, 110},
+			wantSettled: []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installments := []Installment{
+				{Sequence: 1, PrincipalAmount: 100, InterestAmount: 10, PaidAmount: 30},
+				{Sequence: 2, PrincipalAmount: 100, InterestAmount: 10},
+			}
+
+			left := AllocateRepayment(installments, tt.amount, date(2024, time.March, 1))
+			if left != tt.wantLeft {
+				t.Errorf("left = %v, want %v", left, tt.wantLeft)
+			}
+
+			for index, installment := range installments {
+				if installment.PaidAmount != tt.wantPaid[index] {
+					t.Errorf("installment %d paid amount = %v, want %v", index, installment.PaidAmount, tt.wantPaid[index])
+				}
+
+				if settled := installment.PaidAt != nil; settled != tt.wantSettled[index] {
+					t.Errorf("installment %d settled = %v, want %v", index, settled, tt.wantSettled[index])
+				}
+			}
+		})
+	}
+}
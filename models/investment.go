@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
 
@@ -10,6 +11,7 @@ type Investment struct {
 	bun.BaseModel `bun:"table:investments"`
 
 	ID                  uint       `bun:"id,pk,nullzero"`
+	UUID                uuid.UUID  `bun:"uuid"`
 	LoanID              uint       `bun:"loan_id"`
 	InvestorID          uint       `bun:"investor_id"`
 	Amount              float64    `bun:"amount"`
@@ -22,17 +24,29 @@ type Investment struct {
 	Investor *User `bun:"rel:has-one,join:investor_id=id"`
 }
 
-func NewInvestment(investorId uint, amount float64, loan *Loan) (*Investment, error) {
-	err := loan.Invest(amount)
+func NewInvestment(investorId uint, amount float64, loan *Loan, limits InvestmentLimits, position InvestorPosition) (*Investment, error) {
+	err := limits.CheckTicket(amount, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	err = limits.CheckConcentration(amount, loan, position)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loan.Invest(amount)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Investment{
+		UUID:       uuid.New(),
 		LoanID:     loan.ID,
 		InvestorID: investorId,
 		Amount:     amount,
 		ROI:        (amount / loan.ProposedAmount) * loan.ROI,
+		CreatedAt:  time.Now(),
 		Loan:       loan,
 	}, nil
 }
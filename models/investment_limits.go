@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+// InvestmentLimits are the platform rules every ticket must respect, a zero
+// value disables the matching rule.
+type InvestmentLimits struct {
+	MinTicket           float64
+	MaxTicket           float64
+	LotSize             float64
+	MaxLoanSharePercent float64
+	MaxInvestorExposure float64
+	MaxBorrowerExposure float64
+}
+
+// ExposureLoanStatuses are the loans still holding investor money
+var ExposureLoanStatuses = []LoanStatus{
+	LoanStatusApproved,
+	LoanStatusInvested,
+	LoanStatusDisbursing,
+	LoanStatusDisbursed,
+	LoanStatusDelinquent,
+	LoanStatusDefaulted,
+}
+
+// InvestorPosition is what the investor already holds when placing a ticket
+type InvestorPosition struct {
+	LoanAmount     float64
+	BorrowerAmount float64
+	Exposure       float64
+}
+
+// CheckTicket validates the ticket size. The ticket closing the loan may be
+// smaller than the minimum or off lot, otherwise a loan could never be filled.
+func (l InvestmentLimits) CheckTicket(amount float64, loan *Loan) error {
+	if amount <= 0 {
+		return errors.New("invalid_amount")
+	}
+
+	closing := amount == loan.RemainingAmount()
+
+	if l.MinTicket > 0 && amount < l.MinTicket && !closing {
+		return errors.New("investment_below_minimum_ticket")
+	}
+
+	if l.MaxTicket > 0 && amount > l.MaxTicket {
+		return errors.New("investment_above_maximum_ticket")
+	}
+
+	if l.LotSize > 0 && !closing && int64(math.Round(amount*100))%int64(math.Round(l.LotSize*100)) != 0 {
+		return errors.New("investment_not_multiple_of_lot_size")
+	}
+
+	return nil
+}
+
+// CheckConcentration keeps a single investor from owning too much of a loan,
+// of a borrower or of the platform.
+func (l InvestmentLimits) CheckConcentration(amount float64, loan *Loan, position InvestorPosition) error {
+	if l.MaxLoanSharePercent > 0 && position.LoanAmount+amount > loan.ProposedAmount*l.MaxLoanSharePercent/100 {
+		return errors.New("investment_exceeds_loan_share")
+	}
+
+	if l.MaxBorrowerExposure > 0 && position.BorrowerAmount+amount > l.MaxBorrowerExposure {
+		return errors.New("investment_exceeds_borrower_exposure")
+	}
+
+	if l.MaxInvestorExposure > 0 && position.Exposure+amount > l.MaxInvestorExposure {
+		return errors.New("investment_exceeds_investor_exposure")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import "testing"
+
+func TestInvestmentLimitsCheckTicket(t *testing.T) {
+	limits := InvestmentLimits{MinTicket: 100000, MaxTicket: 5000000, LotSize: 50000}
+
+	tests := []struct {
+		name    string
+		amount  float64
+		funded  float64
+		wantErr string
+	}{
+		{name: "within the limits", amount: 250000},
+		{name: "zero", amount: 0, wantErr: "invalid_amount"},
+		{name: "below the minimum", amount: 50000, wantErr: "investment_below_minimum_ticket"},
+		{name: "above the maximum", amount: 5050000, wantErr: "investment_above_maximum_ticket"},
+		{name: "off lot", amount: 120000, wantErr: "investment_not_multiple_of_lot_size"},
+		{name: "closing ticket below the minimum", amount: 30000, funded: 9970000},
+		{name: "closing ticket off lot", amount: 120000.5, funded: 9879999.5},
+		{name: "closing ticket above the maximum", amount: 6000000, funded: 4000000, wantErr: "investment_above_maximum_ticket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{ProposedAmount: 10000000, PrincipalAmount: tt.funded}
+
+			err := limits.CheckTicket(tt.amount, loan)
+			if got := errorString(err); got != tt.wantErr {
+				t.Errorf("error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvestmentLimitsCheckConcentration(t *testing.T) {
+	limits := InvestmentLimits{MaxLoanSharePercent: 20, MaxBorrowerExposure: 3000000, MaxInvestorExposure: 10000000}
+
+	tests := []struct {
+		name     string
+		amount   float64
+		position InvestorPosition
+		wantErr  string
+	}{
+		{name: "within the limits", amount: 1000000},
+		{name: "exactly the loan share", amount: 1000000, position: InvestorPosition{LoanAmount: 1000000}},
+		{name: "over the loan share", amount: 500000, position: InvestorPosition{LoanAmount: 1600000}, wantErr: "investment_exceeds_loan_share"},
+		{name: "over the borrower exposure", amount: 1000000, position: InvestorPosition{BorrowerAmount: 2500000}, wantErr: "investment_exceeds_borrower_exposure"},
+		{name: "over the investor exposure", amount: 1000000, position: InvestorPosition{Exposure: 9500000}, wantErr: "investment_exceeds_investor_exposure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{ProposedAmount: 10000000}
+
+			err := limits.CheckConcentration(tt.amount, loan, tt.position)
+			if got := errorString(err); got != tt.wantErr {
+				t.Errorf("error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (InvestmentLimits{}).CheckConcentration(1e12, &Loan{ProposedAmount: 1}, InvestorPosition{Exposure: 1e12}); err != nil {
+		t.Errorf("zero limits refused the ticket: %v", err)
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type InvestmentListingStatus string
+
+const (
+	InvestmentListingOpen      InvestmentListingStatus = "open"
+	InvestmentListingSold      InvestmentListingStatus = "sold"
+	InvestmentListingCancelled InvestmentListingStatus = "cancelled"
+)
+
+// InvestmentListing offers a whole investment position to other investors at
+// a price set by the seller.
+type InvestmentListing struct {
+	bun.BaseModel `bun:"table:investment_listings"`
+
+	ID           uint                    `bun:"id,pk,nullzero"`
+	UUID         uuid.UUID               `bun:"uuid"`
+	InvestmentID uint                    `bun:"investment_id"`
+	SellerID     uint                    `bun:"seller_id"`
+	BuyerID      *uint                   `bun:"buyer_id"`
+	Price        float64                 `bun:"price"`
+	Status       InvestmentListingStatus `bun:"status"`
+	SoldAt       *time.Time              `bun:"sold_at,nullzero"`
+	CreatedAt    time.Time               `bun:"created_at"`
+	UpdatedAt    *time.Time              `bun:"updated_at,nullzero"`
+
+	Investment *Investment `bun:"rel:has-one,join:investment_id=id"`
+}
+
+func NewInvestmentListing(investment *Investment, price float64) *InvestmentListing {
+	return &InvestmentListing{
+		UUID:         uuid.New(),
+		InvestmentID: investment.ID,
+		SellerID:     investment.InvestorID,
+		Price:        price,
+		Status:       InvestmentListingOpen,
+		CreatedAt:    time.Now(),
+		Investment:   investment,
+	}
+}
+
+func (l *InvestmentListing) Cancel() {
+	now := time.Now()
+	l.Status = InvestmentListingCancelled
+	l.UpdatedAt = &now
+}
+
+// Sell closes the listing and hands the position over to the buyer, the
+// returned transfer is the history record of the trade.
+func (l *InvestmentListing) Sell(buyerID uint) *InvestmentTransfer {
+	now := time.Now()
+	l.Status = InvestmentListingSold
+	l.BuyerID = &buyerID
+	l.SoldAt = &now
+	l.UpdatedAt = &now
+
+	l.Investment.InvestorID = buyerID
+	l.Investment.UpdatedAt = &now
+
+	return &InvestmentTransfer{
+		UUID:         uuid.New(),
+		InvestmentID: l.InvestmentID,
+		ListingID:    l.ID,
+		SellerID:     l.SellerID,
+		BuyerID:      buyerID,
+		Amount:       l.Investment.Amount,
+		Price:        l.Price,
+		CreatedAt:    now,
+	}
+}
+
+// InvestmentTransfer records an investment changing hands
+type InvestmentTransfer struct {
+	bun.BaseModel `bun:"table:investment_transfers"`
+
+	ID           uint      `bun:"id,pk,nullzero"`
+	UUID         uuid.UUID `bun:"uuid"`
+	InvestmentID uint      `bun:"investment_id"`
+	ListingID    uint      `bun:"listing_id"`
+	SellerID     uint      `bun:"seller_id"`
+	BuyerID      uint      `bun:"buyer_id"`
+	Amount       float64   `bun:"amount"`
+	Price        float64   `bun:"price"`
+	CreatedAt    time.Time `bun:"created_at"`
+
+	Investment *Investment `bun:"rel:has-one,join:investment_id=id"`
+}
@@ -15,6 +15,15 @@ const (
 	LoanStatusApproved
 	LoanStatusInvested
 	LoanStatusDisbursed
+	LoanStatusRejected
+	LoanStatusCancelled
+	LoanStatusExpired
+	LoanStatusDisbursing
+	LoanStatusDelinquent
+	LoanStatusDefaulted
+	LoanStatusWrittenOff
+	LoanStatusRepaid
+	LoanStatusPendingApproval
 )
 
 func (s LoanStatus) String() string {
@@ -27,6 +36,24 @@ func (s LoanStatus) String() string {
 		return "invested"
 	case LoanStatusDisbursed:
 		return "disbursed"
+	case LoanStatusRejected:
+		return "rejected"
+	case LoanStatusCancelled:
+		return "cancelled"
+	case LoanStatusExpired:
+		return "expired"
+	case LoanStatusDisbursing:
+		return "disbursing"
+	case LoanStatusDelinquent:
+		return "delinquent"
+	case LoanStatusDefaulted:
+		return "defaulted"
+	case LoanStatusWrittenOff:
+		return "written_off"
+	case LoanStatusRepaid:
+		return "repaid"
+	case LoanStatusPendingApproval:
+		return "pending_approval"
 	default:
 		return "unknown"
 	}
@@ -35,59 +62,267 @@ func (s LoanStatus) String() string {
 type Loan struct {
 	bun.BaseModel `bun:"table:loans"`
 
-	ID               uint       `bun:"id,pk,nullzero"`
-	UUID             uuid.UUID  `bun:"uuid"`
-	BorrowerID       uint       `bun:"borrower_id"`
-	ApprovalID       *uint      `bun:"approval_id"`
-	DisbursmentID    *uint      `bun:"disbursement_id"`
-	ProposedAmount   float64    `bun:"proposed_amount"`
-	PrincipalAmount  float64    `bun:"principal_amount"`
-	Rate             float64    `bun:"rate"`
-	ROI              float64    `bun:"roi"`
-	Status           LoanStatus `bun:"status"`
-	AgreementFileURL string     `bun:"aggreement_file_url"`
-	CreatedAt        time.Time  `bun:"created_at"`
-	UpdatedAt        *time.Time `bun:"updated_at,nullzero"`
+	ID                        uint                 `bun:"id,pk,nullzero"`
+	UUID                      uuid.UUID            `bun:"uuid"`
+	BorrowerID                uint                 `bun:"borrower_id"`
+	ProposedBy                uint                 `bun:"proposed_by"`
+	ApprovalID                *uint                `bun:"approval_id"`
+	DisbursmentID             *uint                `bun:"disbursement_id"`
+	ProposedAmount            float64              `bun:"proposed_amount"`
+	PrincipalAmount           float64              `bun:"principal_amount"`
+	Rate                      float64              `bun:"rate"`
+	ROI                       float64              `bun:"roi"`
+	PlatformFee               float64              `bun:"platform_fee"`
+	Tenor                     int                  `bun:"tenor"`
+	ProductID                 *uint                `bun:"product_id"`
+	GroupLoanID               *uint                `bun:"group_loan_id"`
+	Frequency                 InstallmentFrequency `bun:"installment_frequency"`
+	GracePeriodDays           int                  `bun:"grace_period_days"`
+	EarlySettlementFeePercent float64              `bun:"early_settlement_fee_percent"`
+	RiskGrade                 string               `bun:"risk_grade"`
+	CreditScore               int                  `bun:"credit_score"`
+	RejectionReason           string               `bun:"rejection_reason"`
+	RateCardID                *uint                `bun:"rate_card_id"`
+	RateCardVersion           int                  `bun:"rate_card_version"`
+	Status                    LoanStatus           `bun:"status"`
+	AgreementFileURL          string               `bun:"aggreement_file_url"`
+	VirtualAccount            string               `bun:"virtual_account_number"`
+	DaysPastDue               int                  `bun:"days_past_due"`
+	DelinquencyBucket         string               `bun:"delinquency_bucket"`
+	OverdueAmount             float64              `bun:"overdue_amount"`
+	EarlySettlementFee        float64              `bun:"early_settlement_fee"`
+	ClosedAt                  *time.Time           `bun:"closed_at,nullzero"`
+	CreatedAt                 time.Time            `bun:"created_at"`
+	UpdatedAt                 *time.Time           `bun:"updated_at,nullzero"`
 
+	Borrower    *User        `bun:"rel:has-one,join:borrower_id=id"`
+	Product     *LoanProduct `bun:"rel:has-one,join:product_id=id"`
+	RateCard    *RateCard    `bun:"rel:has-one,join:rate_card_id=id"`
 	Approval    *Approval    `bun:"rel:has-one,join:approval_id=id"`
 	Disbursment *Disbursment `bun:"rel:has-one,join:disbursement_id=id"`
+	Documents   []Document   `bun:"rel:has-many,join:id=loan_id"`
+
+	Installments  []Installment      `bun:"rel:has-many,join:id=loan_id"`
+	StatusHistory []LoanStatusChange `bun:"rel:has-many,join:id=loan_id"`
+
+	statusChanges []LoanStatusChange
 }
 
 func NewPropose(
 	borowerID uint,
 	amount float64,
+	product *LoanProduct,
+	tenor int,
+	creditScore int,
+	riskGrade string,
 ) *Loan {
-	return &Loan{
-		UUID:           uuid.New(),
-		BorrowerID:     borowerID,
-		ProposedAmount: amount,
-		Rate:           5,
-		ROI:            amount * 5 / 100,
-		Status:         LoanStatusProposed,
-		CreatedAt:      time.Now(),
+	now := time.Now()
+	loan := &Loan{
+		UUID:                      uuid.New(),
+		BorrowerID:                borowerID,
+		ProposedBy:                borowerID,
+		ProposedAmount:            amount,
+		Tenor:                     tenor,
+		ProductID:                 &product.ID,
+		Frequency:                 product.InstallmentFrequency,
+		GracePeriodDays:           product.GracePeriodDays,
+		EarlySettlementFeePercent: product.EarlySettlementFeePercent,
+		CreditScore:               creditScore,
+		RiskGrade:                 riskGrade,
+		Status:                    LoanStatusProposed,
+		CreatedAt:                 now,
+		Product:                   product,
 	}
+	loan.statusChanges = []LoanStatusChange{{ToStatus: LoanStatusProposed, CreatedAt: now}}
+
+	return loan
 }
 
-func (l *Loan) Approve(fieldValidatorId uint, approvalFileUrl string) {
-	l.Status = LoanStatusApproved
+// ApplyRateCard prices the loan and snapshots the card it was priced with
+func (l *Loan) ApplyRateCard(rateCard *RateCard) {
+	_, platformFee, roi := rateCard.Price(l.ProposedAmount)
+
+	l.Rate = rateCard.Rate
+	l.ROI = roi
+	l.PlatformFee = platformFee
+	l.RateCardID = &rateCard.ID
+	l.RateCardVersion = rateCard.Version
+	l.RateCard = rateCard
+}
+
+// Reject closes a proposal before it reaches a field validator
+func (l *Loan) Reject(reason string) {
+	now := time.Now()
+	l.moveTo(LoanStatusRejected, reason, now)
+	l.RejectionReason = reason
+	l.UpdatedAt = &now
+}
+
+// Cancellable tells whether the loan can still be called off, money has not
+// left the platform before disbursement.
+func (l *Loan) Cancellable() bool {
+	return l.Status == LoanStatusProposed || l.Status == LoanStatusPendingApproval || l.Status == LoanStatusApproved || l.Status == LoanStatusInvested
+}
+
+func (l *Loan) Cancel(reason string) {
+	now := time.Now()
+	l.moveTo(LoanStatusCancelled, reason, now)
+	l.RejectionReason = reason
+	l.UpdatedAt = &now
+}
+
+// Expire closes an approved loan that did not get fully funded in time
+func (l *Loan) Expire() {
+	now := time.Now()
+	l.moveTo(LoanStatusExpired, LoanStatusReasonFundingPeriodEnded, now)
+	l.UpdatedAt = &now
+}
+
+// Approve records the field validator approval. A loan needing sign-offs
+// on top of it waits in pending approval until the last one comes in.
+func (l *Loan) Approve(fieldValidatorId uint, approvalFileUrl string, approvalFileChecksum string, requiredRoles []UserRole) {
+	now := time.Now()
 
 	l.Approval = &Approval{
-		FieldValidatorID: fieldValidatorId,
-		ApprovalFileURL:  approvalFileUrl,
-		CreatedAt:        time.Now(),
+		FieldValidatorID:     fieldValidatorId,
+		ApprovalFileURL:      approvalFileUrl,
+		ApprovalFileChecksum: approvalFileChecksum,
+		RequiredRoles:        make([]int, 0, len(requiredRoles)),
+		ApprovedAt:           &now,
+		CreatedAt:            now,
+	}
+
+	for _, role := range requiredRoles {
+		l.Approval.RequiredRoles = append(l.Approval.RequiredRoles, int(role))
+	}
+
+	if len(requiredRoles) > 0 {
+		l.Approval.ApprovedAt = nil
+		l.moveTo(LoanStatusPendingApproval, "", now)
+		return
+	}
+
+	l.moveTo(LoanStatusApproved, "", now)
+}
+
+// CompleteApproval releases a loan to investors once every sign-off its
+// approval asked for is in.
+func (l *Loan) CompleteApproval(signOffs []ApprovalSignOff) bool {
+	if l.Status != LoanStatusPendingApproval || len(l.Approval.MissingRoles(signOffs)) > 0 {
+		return false
 	}
+
+	now := time.Now()
+	l.moveTo(LoanStatusApproved, LoanStatusReasonSignOffsCompleted, now)
+	l.Approval.ApprovedAt = &now
+	l.Approval.UpdatedAt = &now
+	l.UpdatedAt = &now
+
+	return true
 }
 
-func (l *Loan) Disburse(fieldOfficerId uint, aggreementFileUrl string) {
-	l.Status = LoanStatusDisbursed
+// Disburse records the field officer hand-over and waits for the transfer to
+// the borrower, the loan is only disbursed once the gateway confirms it.
+func (l *Loan) Disburse(fieldOfficerId uint, aggreementFileUrl string, aggreementFileChecksum string) {
+	now := time.Now()
+	l.moveTo(LoanStatusDisbursing, "", now)
 
 	l.Disbursment = &Disbursment{
-		FieldOfficerID:    fieldOfficerId,
-		AggreementFileURL: aggreementFileUrl,
-		CreatedAt:         time.Now(),
+		FieldOfficerID:         fieldOfficerId,
+		AggreementFileURL:      aggreementFileUrl,
+		AggreementFileChecksum: aggreementFileChecksum,
+		CreatedAt:              now,
 	}
 }
 
+func (l *Loan) CompleteDisbursement() {
+	now := time.Now()
+	l.moveTo(LoanStatusDisbursed, "", now)
+	l.UpdatedAt = &now
+}
+
+// FailDisbursement puts the loan back so the field officer can try again
+func (l *Loan) FailDisbursement(reason string) {
+	now := time.Now()
+	l.moveTo(LoanStatusInvested, reason, now)
+	l.UpdatedAt = &now
+}
+
+// Repaying tells whether the borrower holds the money and owes installments
+func (l *Loan) Repaying() bool {
+	return l.Status == LoanStatusDisbursed || l.Status == LoanStatusDelinquent || l.Status == LoanStatusDefaulted
+}
+
+// MarkDelinquency moves a repaying loan between disbursed, delinquent and
+// defaulted. A defaulted loan stays defaulted even when the borrower catches
+// up, getting out of default is a decision for collections.
+func (l *Loan) MarkDelinquency(daysPastDue int, overdueAmount float64, policy DelinquencyPolicy) {
+	l.DaysPastDue = daysPastDue
+	l.DelinquencyBucket = DelinquencyBucketFor(daysPastDue)
+	l.OverdueAmount = overdueAmount
+
+	if l.Status == LoanStatusDefaulted {
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case policy.DefaultAfterDays > 0 && daysPastDue >= policy.DefaultAfterDays:
+		l.moveTo(LoanStatusDefaulted, LoanStatusReasonPastDue, now)
+	case policy.DelinquentAfterDays > 0 && daysPastDue >= policy.DelinquentAfterDays:
+		l.moveTo(LoanStatusDelinquent, LoanStatusReasonPastDue, now)
+	default:
+		l.moveTo(LoanStatusDisbursed, LoanStatusReasonCaughtUp, now)
+	}
+}
+
+// Restructure takes the loan out of arrears, a new schedule replaces what
+// was left of the old one.
+func (l *Loan) Restructure(extraInstallments int) {
+	now := time.Now()
+	l.Tenor += extraInstallments
+	l.moveTo(LoanStatusDisbursed, LoanStatusReasonRestructured, now)
+	l.DaysPastDue = 0
+	l.DelinquencyBucket = DelinquencyBucketCurrent
+	l.OverdueAmount = 0
+	l.UpdatedAt = &now
+}
+
+// Repay closes a loan the borrower paid back in full over its schedule
+func (l *Loan) Repay() {
+	l.close(0, "")
+}
+
+// Settle closes a loan paid back before the end of its schedule,
+// settlementFee is the fee charged for it.
+func (l *Loan) Settle(settlementFee float64) {
+	l.close(settlementFee, LoanStatusReasonEarlySettlement)
+}
+
+func (l *Loan) close(settlementFee float64, reason string) {
+	now := time.Now()
+	l.moveTo(LoanStatusRepaid, reason, now)
+	l.EarlySettlementFee = settlementFee
+	l.DaysPastDue = 0
+	l.DelinquencyBucket = DelinquencyBucketCurrent
+	l.OverdueAmount = 0
+	l.ClosedAt = &now
+	l.UpdatedAt = &now
+}
+
+// WriteOff closes a loan the platform no longer expects to recover
+func (l *Loan) WriteOff(reason string) {
+	now := time.Now()
+	l.moveTo(LoanStatusWrittenOff, reason, now)
+	l.RejectionReason = reason
+	l.ClosedAt = &now
+	l.UpdatedAt = &now
+}
+
+func (l *Loan) RemainingAmount() float64 {
+	return l.ProposedAmount - l.PrincipalAmount
+}
+
 func (l *Loan) Invest(amount float64) error {
 	if l.PrincipalAmount+amount > l.ProposedAmount {
 		return errors.New("loan_invested_amount_exceeds_proposed_amount")
@@ -95,7 +330,7 @@ func (l *Loan) Invest(amount float64) error {
 
 	l.PrincipalAmount += float64(amount)
 	if l.PrincipalAmount == l.ProposedAmount {
-		l.Status = LoanStatusInvested
+		l.moveTo(LoanStatusInvested, "", time.Now())
 	}
 
 	return nil
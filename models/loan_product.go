@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type InstallmentFrequency string
+
+const (
+	InstallmentFrequencyWeekly   InstallmentFrequency = "weekly"
+	InstallmentFrequencyBiweekly InstallmentFrequency = "biweekly"
+	InstallmentFrequencyMonthly  InstallmentFrequency = "monthly"
+)
+
+var InstallmentFrequencies = map[InstallmentFrequency]bool{
+	InstallmentFrequencyWeekly:   true,
+	InstallmentFrequencyBiweekly: true,
+	InstallmentFrequencyMonthly:  true,
+}
+
+// DueDate is the date of the nth installment counted from start
+func (f InstallmentFrequency) DueDate(start time.Time, n int) time.Time {
+	switch f {
+	case InstallmentFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case InstallmentFrequencyBiweekly:
+		return start.AddDate(0, 0, 14*n)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
+
+// LoanProduct is what a borrower applies for. Tenors are counted in
+// installments of the product frequency, and the product code is also the
+// product key of its rate cards.
+type LoanProduct struct {
+	bun.BaseModel `bun:"table:loan_products"`
+
+	ID                   uint                 `bun:"id,pk,nullzero"`
+	UUID                 uuid.UUID            `bun:"uuid"`
+	Code                 string               `bun:"code"`
+	Name                 string               `bun:"name"`
+	MinAmount            float64              `bun:"min_amount"`
+	MaxAmount            float64              `bun:"max_amount"`
+	Tenors               []int                `bun:"tenors,array"`
+	InstallmentFrequency InstallmentFrequency `bun:"installment_frequency"`
+	GracePeriodDays      int                  `bun:"grace_period_days"`
+	// EarlySettlementFeePercent is charged on the principal still owed when a
+	// borrower settles a loan before the end of its schedule
+	EarlySettlementFeePercent float64    `bun:"early_settlement_fee_percent"`
+	EligibleRiskGrades        []string   `bun:"eligible_risk_grades,array"`
+	EligibleSectors           []string   `bun:"eligible_sectors,array"`
+	EligibleRegions           []string   `bun:"eligible_regions,array"`
+	Active                    bool       `bun:"active"`
+	CreatedAt                 time.Time  `bun:"created_at"`
+	UpdatedAt                 *time.Time `bun:"updated_at,nullzero"`
+}
+
+func NewLoanProduct(
+	code string,
+	name string,
+	minAmount float64,
+	maxAmount float64,
+	tenors []int,
+	installmentFrequency InstallmentFrequency,
+	gracePeriodDays int,
+) *LoanProduct {
+	return &LoanProduct{
+		UUID:                 uuid.New(),
+		Code:                 code,
+		Name:                 name,
+		MinAmount:            minAmount,
+		MaxAmount:            maxAmount,
+		Tenors:               tenors,
+		InstallmentFrequency: installmentFrequency,
+		GracePeriodDays:      gracePeriodDays,
+		Active:               true,
+		CreatedAt:            time.Now(),
+	}
+}
+
+func (p *LoanProduct) AllowsTenor(tenor int) bool {
+	for _, allowed := range p.Tenors {
+		if allowed == tenor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Eligible checks the borrower profile and assessed risk grade against the
+// product rules, an empty rule list means the product is open to everyone on
+// that criterion.
+func (p *LoanProduct) Eligible(borrower *User, riskGrade string) bool {
+	return matchesRule(p.EligibleRiskGrades, riskGrade) &&
+		matchesRule(p.EligibleSectors, borrower.Sector) &&
+		matchesRule(p.EligibleRegions, borrower.Region)
+}
+
+func matchesRule(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, candidate := range allowed {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
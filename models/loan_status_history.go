@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Reasons given for the transitions the platform makes on its own
+const (
+	LoanStatusReasonCreditScore        = "credit_score_below_threshold"
+	LoanStatusReasonFundingPeriodEnded = "funding_period_ended"
+	LoanStatusReasonSignOffsCompleted  = "sign_offs_completed"
+	LoanStatusReasonPastDue            = "past_due"
+	LoanStatusReasonCaughtUp           = "caught_up"
+	LoanStatusReasonRestructured       = "restructured"
+	LoanStatusReasonEarlySettlement    = "early_settlement"
+)
+
+// LoanStatusChange is one step of the loan timeline. FromStatus is nil for
+// the proposal opening it, a nil actor is the platform itself.
+type LoanStatusChange struct {
+	bun.BaseModel `bun:"table:loan_status_history,alias:loan_status_change"`
+
+	ID         uint        `bun:"id,pk,nullzero"`
+	LoanID     uint        `bun:"loan_id"`
+	FromStatus *LoanStatus `bun:"from_status"`
+	ToStatus   LoanStatus  `bun:"to_status"`
+	ActorID    *uint       `bun:"actor_id"`
+	ActorRole  UserRole    `bun:"actor_role"`
+	Reason     string      `bun:"reason"`
+	CreatedAt  time.Time   `bun:"created_at"`
+}
+
+// moveTo changes the status and keeps the transition until the loan is
+// saved, moving to the status the loan already has is no transition.
+func (l *Loan) moveTo(status LoanStatus, reason string, at time.Time) {
+	if l.Status == status {
+		return
+	}
+
+	from := l.Status
+	l.Status = status
+	l.statusChanges = append(l.statusChanges, LoanStatusChange{
+		FromStatus: &from,
+		ToStatus:   status,
+		Reason:     reason,
+		CreatedAt:  at,
+	})
+}
+
+// TakeStatusChanges hands the transitions not saved yet to the repository
+// saving the loan, each one is saved once.
+func (l *Loan) TakeStatusChanges() []LoanStatusChange {
+	changes := l.statusChanges
+	l.statusChanges = nil
+
+	for index := range changes {
+		changes[index].LoanID = l.ID
+	}
+
+	return changes
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type PaymentDirection string
+
+const (
+	PaymentDirectionDisbursement PaymentDirection = "disbursement"
+	PaymentDirectionCollection   PaymentDirection = "collection"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending PaymentStatus = "pending"
+	PaymentStatusSuccess PaymentStatus = "success"
+	PaymentStatusFailed  PaymentStatus = "failed"
+)
+
+// Payment is money moving between the platform and a borrower through the
+// payment gateway, Reference is ours and GatewayReference the provider's.
+type Payment struct {
+	bun.BaseModel `bun:"table:payments"`
+
+	ID                   uint             `bun:"id,pk,nullzero"`
+	UUID                 uuid.UUID        `bun:"uuid"`
+	LoanID               uint             `bun:"loan_id"`
+	Direction            PaymentDirection `bun:"direction"`
+	Method               string           `bun:"method"`
+	Status               PaymentStatus    `bun:"status"`
+	Amount               float64          `bun:"amount"`
+	Reference            string           `bun:"reference"`
+	GatewayReference     string           `bun:"gateway_reference"`
+	BankCode             string           `bun:"bank_code"`
+	BankAccountNumber    string           `bun:"bank_account_number"`
+	VirtualAccountNumber string           `bun:"virtual_account_number"`
+	FailureReason        string           `bun:"failure_reason"`
+	CompletedAt          *time.Time       `bun:"completed_at,nullzero"`
+	CreatedAt            time.Time        `bun:"created_at"`
+	UpdatedAt            *time.Time       `bun:"updated_at,nullzero"`
+
+	Loan *Loan `bun:"rel:has-one,join:loan_id=id"`
+}
+
+func NewPayment(loanID uint, direction PaymentDirection, method string, amount float64) *Payment {
+	id := uuid.New()
+
+	return &Payment{
+		UUID:      id,
+		LoanID:    loanID,
+		Direction: direction,
+		Method:    method,
+		Status:    PaymentStatusPending,
+		Amount:    amount,
+		Reference: id.String(),
+		CreatedAt: time.Now(),
+	}
+}
+
+func (p *Payment) Final() bool {
+	return p.Status != PaymentStatusPending
+}
+
+func (p *Payment) Complete() {
+	now := time.Now()
+	p.Status = PaymentStatusSuccess
+	p.CompletedAt = &now
+	p.UpdatedAt = &now
+}
+
+func (p *Payment) Fail(reason string) {
+	now := time.Now()
+	p.Status = PaymentStatusFailed
+	p.FailureReason = reason
+	p.CompletedAt = &now
+	p.UpdatedAt = &now
+}
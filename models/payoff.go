@@ -0,0 +1,145 @@
+package models
+
+import (
+	"time"
+)
+
+// PayoffQuote is what a borrower pays to close a loan on Date. Interest of
+// the installment running on that date accrues by the day, interest of the
+// installments after it is waived and the early settlement fee of the
+// product is charged on the principal still owed instead.
+type PayoffQuote struct {
+	Date                 time.Time
+	OutstandingPrincipal float64
+	AccruedInterest      float64
+	LateFees             float64
+	EarlySettlementFee   float64
+	Total                float64
+
+	// interest is what each installment keeps as interest once settled
+	interest []float64
+}
+
+// NewPayoffQuote prices the settlement of the active installments of a loan,
+// Settle must then be given the same installments.
+func NewPayoffQuote(loan *Loan, installments []Installment, date time.Time) *PayoffQuote {
+	quote := &PayoffQuote{
+		Date:     truncateDay(date),
+		interest: make([]float64, len(installments)),
+	}
+
+	early := false
+	running := true
+	for index := range installments {
+		installment := &installments[index]
+		quote.interest[index] = installment.InterestAmount
+		if installment.Paid() {
+			continue
+		}
+
+		if truncateDay(installment.DueDate).After(quote.Date) {
+			early = true
+			quote.interest[index] = installment.PaidInterest()
+
+			if running {
+				running = false
+				accrued := accruedInterest(loan, installments, index, quote.Date)
+				quote.interest[index] = max(quote.interest[index], accrued)
+			}
+		}
+
+		quote.OutstandingPrincipal += installment.OutstandingPrincipal()
+		quote.AccruedInterest += quote.interest[index] - installment.PaidInterest()
+		quote.LateFees += installment.OutstandingLateFee()
+	}
+
+	quote.OutstandingPrincipal = roundAmount(quote.OutstandingPrincipal)
+	quote.AccruedInterest = roundAmount(quote.AccruedInterest)
+	quote.LateFees = roundAmount(quote.LateFees)
+	if early {
+		quote.EarlySettlementFee = roundAmount(quote.OutstandingPrincipal * loan.EarlySettlementFeePercent / 100)
+	}
+	quote.Total = roundAmount(quote.OutstandingPrincipal + quote.AccruedInterest + quote.LateFees + quote.EarlySettlementFee)
+
+	return quote
+}
+
+// Settle waives the interest not accrued yet and pays every installment in
+// full, the early settlement fee is kept on the loan.
+func (q *PayoffQuote) Settle(installments []Installment, at time.Time) {
+	for index := range installments {
+		if installments[index].Paid() {
+			continue
+		}
+
+		installments[index].InterestAmount = roundAmount(q.interest[index])
+		installments[index].Pay(installments[index].Outstanding(), at)
+	}
+}
+
+// accruedInterest is the interest of an installment earned by the day since
+// the previous due date, or one period before its own for the first one.
+func accruedInterest(loan *Loan, installments []Installment, index int, date time.Time) float64 {
+	installment := installments[index]
+	dueDate := truncateDay(installment.DueDate)
+
+	periodStart := loan.Frequency.DueDate(dueDate, -1)
+	if index > 0 {
+		periodStart = truncateDay(installments[index-1].DueDate)
+	}
+
+	periodDays := dueDate.Sub(periodStart).Hours() / 24
+	if periodDays <= 0 {
+		return installment.InterestAmount
+	}
+
+	elapsedDays := min(periodDays, max(0, date.Sub(periodStart).Hours()/24))
+
+	return roundAmount(installment.InterestAmount * elapsedDays / periodDays)
+}
+
+// InvestorPayouts splits what the borrower repaid over the life of a closed
+// loan between its investors in proportion to their investment. Investors
+// get the principal back with their share of the interest and of the early
+// settlement fee, net of the platform fee. Late fees stay with the platform.
+// Installments must include the superseded ones so restructured loans are
+// accounted in full.
+func InvestorPayouts(loan *Loan, installments []Installment, investments []Investment) []*WalletTransaction {
+	principal, interest := 0.0, 0.0
+	for _, installment := range installments {
+		principal += installment.PaidPrincipal()
+		interest += installment.PaidInterest()
+	}
+
+	investorShare := 0.0
+	if loan.ROI+loan.PlatformFee > 0 {
+		investorShare = loan.ROI / (loan.ROI + loan.PlatformFee)
+	}
+
+	total := roundAmount(principal + (interest+loan.EarlySettlementFee)*investorShare)
+
+	invested := 0.0
+	for _, investment := range investments {
+		invested += investment.Amount
+	}
+
+	// The last investment absorbs the rounding so the payouts add up exactly
+	payouts := make([]*WalletTransaction, 0, len(investments))
+	allocated := 0.0
+	for index := range investments {
+		investment := &investments[index]
+
+		amount := roundAmount(total * investment.Amount / invested)
+		if index == len(investments)-1 {
+			amount = roundAmount(total - allocated)
+		}
+		allocated += amount
+
+		transaction := NewInvestmentTransaction(WalletTransactionPayout, investment)
+		transaction.Amount = amount
+		transaction.Reference = loan.UUID.String()
+		payouts = append(payouts, transaction)
+	}
+
+	return payouts
+}
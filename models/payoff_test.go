@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+)
+
+func TestNewPayoffQuote(t *testing.T) {
+	tests := []struct {
+		name          string
+		date          time.Time
+		paid          []float64
+		lateFees      []float64
+		wantPrincipal float64
+		wantInterest  float64
+		wantLateFees  float64
+		wantFee       float64
+		wantTotal     float64
+	}{
+		{
+			name:          "before the first due date",
+			date:          date(2024, time.January, 16),
+			paid:          []float64{0, 0, 0},
+			lateFees:      []float64{0, 0, 0},
+			wantPrincipal: 300000,
+			wantInterest:  1451.61,
+			wantFee:       6000,
+			wantTotal:     307451.61,
+		},
+		{
+			name:          "on a due date",
+			date:          date(2024, time.March, 1),
+			paid:          []float64{103000, 0, 0},
+			lateFees:      []float64{0, 0, 0},
+			wantPrincipal: 200000,
+			wantInterest:  3000,
+			wantFee:       4000,
+			wantTotal:     207000,
+		},
+		{
+			name:          "mid period with an overdue installment",
+			date:          date(2024, time.March, 16),
+			paid:          []float64{103000, 0, 0},
+			lateFees:      []float64{0, 500, 0},
+			wantPrincipal: 200000,
+			wantInterest:  4451.61,
+			wantLateFees:  500,
+			wantFee:       4000,
+			wantTotal:     208951.61,
+		},
+		{
+			name:          "on the last due date",
+			date:          date(2024, time.April, 1),
+			paid:          []float64{103000, 50000, 0},
+			lateFees:      []float64{0, 500, 0},
+			wantPrincipal: 153500,
+			wantInterest:  3000,
+			wantLateFees:  0,
+			wantFee:       0,
+			wantTotal:     156500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{Frequency: InstallmentFrequencyMonthly, EarlySettlementFeePercent: 2}
+			installments := []Installment{
+				{Sequence: 1, DueDate: date(2024, time.February, 1), PrincipalAmount: 100000, InterestAmount: 3000},
+				{Sequence: 2, DueDate: date(2024, time.March, 1), PrincipalAmount: 100000, InterestAmount: 3000},
+				{Sequence: 3, DueDate: date(2024, time.April, 1), PrincipalAmount: 100000, InterestAmount: 3000},
+			}
+			paidBefore := 0.0
+			for index := range installments {
+				installments[index].PaidAmount = tt.paid[index]
+				installments[index].LateFee = tt.lateFees[index]
+				paidBefore += installments[index].PaidAmount
+			}
+
+			quote := NewPayoffQuote(loan, installments, tt.date)
+			if quote.OutstandingPrincipal != tt.wantPrincipal {
+				t.Errorf("outstanding principal = %v, want %v", quote.OutstandingPrincipal, tt.wantPrincipal)
+			}
+
+			if quote.AccruedInterest != tt.wantInterest {
+				t.Errorf("accrued interest = %v, want %v", quote.AccruedInterest, tt.wantInterest)
+			}
+
+			if quote.LateFees != tt.wantLateFees {
+				t.Errorf("late fees = %v, want %v", quote.LateFees, tt.wantLateFees)
+			}
+
+			if quote.EarlySettlementFee != tt.wantFee {
+				t.Errorf("early settlement fee = %v, want %v", quote.EarlySettlementFee, tt.wantFee)
+			}
+
+			if quote.Total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", quote.Total, tt.wantTotal)
+			}
+
+			// Settling takes exactly the quoted amount, the fee stays on the loan
+			quote.Settle(installments, tt.date)
+			settled := 0.0
+			for index, installment := range installments {
+				if !installment.Paid() {
+					t.Errorf("installment %d not paid after settle", index)
+				}
+				settled += installment.PaidAmount
+			}
+
+			if got, want := roundAmount(settled-paidBefore), roundAmount(quote.Total-quote.EarlySettlementFee); got != want {
+				t.Errorf("settle took %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestInvestorPayouts(t *testing.T) {
+	tests := []struct {
+		name         string
+		loan         Loan
+		installments []Installment
+		investments  []float64
+		want         []float64
+	}{
+		{
+			name: "last investment absorbs the rounding",
+			loan: Loan{ROI: 8, PlatformFee: 2},
+			installments: []Installment{
+				{PrincipalAmount: 1000, PaidAmount: 1000},
+			},
+			investments: []float64{100, 100, 100},
+			want:        []float64{333.33, 333.33, 333.34},
+		},
+		{
+			name: "late fees stay with the platform",
+			loan: Loan{ROI: 8, PlatformFee: 2, EarlySettlementFee: 10},
+			installments: []Installment{
+				{PrincipalAmount: 1000, InterestAmount: 100, LateFee: 50, PaidAmount: 1150},
+			},
+			investments: []float64{600, 400},
+			want:        []float64{652.8, 435.2},
+		},
+		{
+			name: "superseded installments count towards the payout",
+			loan: Loan{ROI: 10},
+			installments: []Installment{
+				{PrincipalAmount: 500, InterestAmount: 50, PaidAmount: 300, SupersededAt: ptr.Of(date(2024, time.March, 1))},
+				{PrincipalAmount: 250, InterestAmount: 25, PaidAmount: 275},
+			},
+			investments: []float64{750},
+			want:        []float64{575},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			investments := make([]Investment, len(tt.investments))
+			for index, amount := range tt.investments {
+				investments[index] = Investment{ID: uint(index + 1), Amount: amount}
+			}
+
+			payouts := InvestorPayouts(&tt.loan, tt.installments, investments)
+			if len(payouts) != len(tt.want) {
+				t.Fatalf("got %d payouts, want %d", len(payouts), len(tt.want))
+			}
+
+			for index, payout := range payouts {
+				if payout.Amount != tt.want[index] {
+					t.Errorf("payout %d = %v, want %v", index, payout.Amount, tt.want[index])
+				}
+
+				if payout.Type != WalletTransactionPayout {
+					t.Errorf("payout %d type = %v, want %v", index, payout.Type, WalletTransactionPayout)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var RiskGrades = map[string]bool{
+	"A": true,
+	"B": true,
+	"C": true,
+	"D": true,
+	"E": true,
+}
+
+// RateCard rows are never edited in place, a change deactivates the row and
+// inserts its next version so loans keep pointing at the pricing they got.
+type RateCard struct {
+	bun.BaseModel `bun:"table:rate_cards"`
+
+	ID              uint       `bun:"id,pk,nullzero"`
+	UUID            uuid.UUID  `bun:"uuid"`
+	PreviousID      *uint      `bun:"previous_id"`
+	Version         int        `bun:"version"`
+	Product         string     `bun:"product"`
+	RiskGrade       string     `bun:"risk_grade"`
+	MinTenor        int        `bun:"min_tenor"`
+	MaxTenor        int        `bun:"max_tenor"`
+	Rate            float64    `bun:"rate"`
+	PlatformFeeRate float64    `bun:"platform_fee_rate"`
+	Active          bool       `bun:"active"`
+	CreatedBy       uint       `bun:"created_by"`
+	CreatedAt       time.Time  `bun:"created_at"`
+	UpdatedAt       *time.Time `bun:"updated_at,nullzero"`
+}
+
+func NewRateCard(
+	product string,
+	riskGrade string,
+	minTenor int,
+	maxTenor int,
+	rate float64,
+	platformFeeRate float64,
+	createdBy uint,
+) *RateCard {
+	return &RateCard{
+		UUID:            uuid.New(),
+		Version:         1,
+		Product:         product,
+		RiskGrade:       riskGrade,
+		MinTenor:        minTenor,
+		MaxTenor:        maxTenor,
+		Rate:            rate,
+		PlatformFeeRate: platformFeeRate,
+		Active:          true,
+		CreatedBy:       createdBy,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// NextVersion retires the card and returns its successor carrying the same settings
+func (r *RateCard) NextVersion(createdBy uint) *RateCard {
+	now := time.Now()
+	r.Active = false
+	r.UpdatedAt = &now
+
+	next := *r
+	next.ID = 0
+	next.UUID = uuid.New()
+	next.PreviousID = &r.ID
+	next.Version = r.Version + 1
+	next.Active = true
+	next.CreatedBy = createdBy
+	next.CreatedAt = now
+	next.UpdatedAt = nil
+
+	return &next
+}
+
+// Price splits the interest of a loan between investors and the platform,
+// the platform fee being a share of the interest.
+func (r *RateCard) Price(amount float64) (interest float64, platformFee float64, roi float64) {
+	interest = amount * r.Rate / 100
+	platformFee = interest * r.PlatformFeeRate / 100
+	roi = interest - platformFee
+
+	return interest, platformFee, roi
+}
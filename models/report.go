@@ -0,0 +1,141 @@
+package models
+
+import (
+	"time"
+)
+
+// ReportPeriods are the buckets disbursed volume can be grouped by, they map
+// to postgres date_trunc fields.
+var ReportPeriods = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+}
+
+// FundedLoanStatuses are the statuses of loans that were fully invested
+var FundedLoanStatuses = []LoanStatus{
+	LoanStatusInvested,
+	LoanStatusDisbursing,
+	LoanStatusDisbursed,
+	LoanStatusDelinquent,
+	LoanStatusDefaulted,
+	LoanStatusWrittenOff,
+	LoanStatusRepaid,
+}
+
+// DisbursedLoanStatuses are the statuses of loans that reached the borrower
+var DisbursedLoanStatuses = []LoanStatus{
+	LoanStatusDisbursed,
+	LoanStatusDelinquent,
+	LoanStatusDefaulted,
+	LoanStatusWrittenOff,
+	LoanStatusRepaid,
+}
+
+// RepayingLoanStatuses are the statuses of loans the borrower still owes on
+var RepayingLoanStatuses = []LoanStatus{
+	LoanStatusDisbursed,
+	LoanStatusDelinquent,
+	LoanStatusDefaulted,
+}
+
+type StatusReport struct {
+	Status         LoanStatus `bun:"status"`
+	Loans          int        `bun:"loans"`
+	ProposedAmount float64    `bun:"proposed_amount"`
+	InvestedAmount float64    `bun:"invested_amount"`
+}
+
+type DisbursementReport struct {
+	Period time.Time `bun:"period"`
+	Loans  int       `bun:"loans"`
+	Amount float64   `bun:"amount"`
+}
+
+// TimeToFundReport measures how long approved loans waited on the
+// marketplace, from approval to the investment that filled them.
+type TimeToFundReport struct {
+	Loans          int     `bun:"loans"`
+	AverageSeconds float64 `bun:"average_seconds"`
+	MinSeconds     float64 `bun:"min_seconds"`
+	MaxSeconds     float64 `bun:"max_seconds"`
+}
+
+type FundingReport struct {
+	Loans          int     `bun:"loans"`
+	FundedLoans    int     `bun:"funded_loans"`
+	ProposedAmount float64 `bun:"proposed_amount"`
+	InvestedAmount float64 `bun:"invested_amount"`
+}
+
+// Ratio is the share of the approved amount investors actually put in, in
+// percent.
+func (r *FundingReport) Ratio() float64 {
+	return percentOf(r.InvestedAmount, r.ProposedAmount)
+}
+
+// PortfolioAtRiskReport compares the principal still owed on loans more than
+// 30 and 90 days past due with the whole outstanding principal.
+type PortfolioAtRiskReport struct {
+	Loans       int     `bun:"loans"`
+	Outstanding float64 `bun:"outstanding"`
+	PAR30Loans  int     `bun:"par30_loans"`
+	PAR30Amount float64 `bun:"par30_amount"`
+	PAR90Loans  int     `bun:"par90_loans"`
+	PAR90Amount float64 `bun:"par90_amount"`
+}
+
+func (r *PortfolioAtRiskReport) PAR30() float64 {
+	return percentOf(r.PAR30Amount, r.Outstanding)
+}
+
+func (r *PortfolioAtRiskReport) PAR90() float64 {
+	return percentOf(r.PAR90Amount, r.Outstanding)
+}
+
+// InvestorReturnReport sums up one investor's investments. Payouts only
+// happen when a loan is repaid, so the return is what was paid out over the
+// principal of the repaid investments, less the losses on written off ones.
+type InvestorReturnReport struct {
+	InvestorID      uint    `bun:"investor_id"`
+	InvestorName    string  `bun:"investor_name"`
+	Investments     int     `bun:"investments"`
+	InvestedAmount  float64 `bun:"invested_amount"`
+	RepaidPrincipal float64 `bun:"repaid_principal"`
+	Payouts         float64 `bun:"payouts"`
+	Losses          float64 `bun:"losses"`
+}
+
+func (r *InvestorReturnReport) NetReturn() float64 {
+	return roundAmount(r.Payouts - r.RepaidPrincipal - r.Losses)
+}
+
+// ReturnRate is the net return over the amount invested, in percent
+func (r *InvestorReturnReport) ReturnRate() float64 {
+	return percentOf(r.NetReturn(), r.InvestedAmount)
+}
+
+// BranchReport groups loans by the region of the borrower, each region being
+// served by one branch.
+type BranchReport struct {
+	Region           string  `bun:"region"`
+	Loans            int     `bun:"loans"`
+	DisbursedLoans   int     `bun:"disbursed_loans"`
+	DisbursedAmount  float64 `bun:"disbursed_amount"`
+	Outstanding      float64 `bun:"outstanding"`
+	PAR30Amount      float64 `bun:"par30_amount"`
+	WrittenOffAmount float64 `bun:"written_off_amount"`
+	RepaidLoans      int     `bun:"repaid_loans"`
+}
+
+func (r *BranchReport) PAR30() float64 {
+	return percentOf(r.PAR30Amount, r.Outstanding)
+}
+
+func percentOf(part float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return roundAmount(part / total * 100)
+}
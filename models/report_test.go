@@ -0,0 +1,84 @@
+package models
+
+import "testing"
+
+func TestFundingReportRatio(t *testing.T) {
+	tests := []struct {
+		name   string
+		report FundingReport
+		want   float64
+	}{
+		{name: "partly funded", report: FundingReport{ProposedAmount: 3000000, InvestedAmount: 2000000}, want: 66.67},
+		{name: "fully funded", report: FundingReport{ProposedAmount: 3000000, InvestedAmount: 3000000}, want: 100},
+		{name: "nothing approved", report: FundingReport{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Ratio(); got != tt.want {
+				t.Errorf("ratio = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortfolioAtRiskReport(t *testing.T) {
+	report := PortfolioAtRiskReport{Outstanding: 9000000, PAR30Amount: 1500000, PAR90Amount: 450000}
+	if got := report.PAR30(); got != 16.67 {
+		t.Errorf("par30 = %v, want 16.67", got)
+	}
+
+	if got := report.PAR90(); got != 5 {
+		t.Errorf("par90 = %v, want 5", got)
+	}
+
+	empty := PortfolioAtRiskReport{}
+	if empty.PAR30() != 0 || empty.PAR90() != 0 {
+		t.Errorf("empty portfolio at risk = %v, %v, want 0", empty.PAR30(), empty.PAR90())
+	}
+}
+
+func TestInvestorReturnReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     InvestorReturnReport
+		wantReturn float64
+		wantRate   float64
+	}{
+		{
+			name:       "repaid investments",
+			report:     InvestorReturnReport{InvestedAmount: 3000000, RepaidPrincipal: 1000000, Payouts: 1080000},
+			wantReturn: 80000,
+			wantRate:   2.67,
+		},
+		{
+			name:       "losses outweigh the payouts",
+			report:     InvestorReturnReport{InvestedAmount: 2000000, RepaidPrincipal: 1000000, Payouts: 1080000, Losses: 250000.5},
+			wantReturn: -170000.5,
+			wantRate:   -8.5,
+		},
+		{
+			name:   "nothing invested",
+			report: InvestorReturnReport{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.NetReturn(); got != tt.wantReturn {
+				t.Errorf("net return = %v, want %v", got, tt.wantReturn)
+			}
+
+			if got := tt.report.ReturnRate(); got != tt.wantRate {
+				t.Errorf("return rate = %v, want %v", got, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestBranchReportPAR30(t *testing.T) {
+	report := BranchReport{Outstanding: 800000, PAR30Amount: 100000}
+	if got := report.PAR30(); got != 12.5 {
+		t.Errorf("par30 = %v, want 12.5", got)
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type RestructuringStatus string
+
+const (
+	RestructuringRequested RestructuringStatus = "requested"
+	RestructuringApproved  RestructuringStatus = "approved"
+	RestructuringRejected  RestructuringStatus = "rejected"
+)
+
+// Restructuring reschedules what the borrower still owes. HolidayPeriods
+// installments are skipped before repayment resumes and ExtraInstallments
+// extend the tenor, spreading the outstanding amount thinner.
+type Restructuring struct {
+	bun.BaseModel `bun:"table:restructurings"`
+
+	ID                uint                `bun:"id,pk,nullzero"`
+	UUID              uuid.UUID           `bun:"uuid"`
+	LoanID            uint                `bun:"loan_id"`
+	RequestedBy       uint                `bun:"requested_by"`
+	DecidedBy         *uint               `bun:"decided_by"`
+	Status            RestructuringStatus `bun:"status"`
+	ExtraInstallments int                 `bun:"extra_installments"`
+	HolidayPeriods    int                 `bun:"holiday_periods"`
+	Reason            string              `bun:"reason"`
+	DecisionNote      string              `bun:"decision_note"`
+	DecidedAt         *time.Time          `bun:"decided_at,nullzero"`
+	CreatedAt         time.Time           `bun:"created_at"`
+	UpdatedAt         *time.Time          `bun:"updated_at,nullzero"`
+
+	Loan *Loan `bun:"rel:has-one,join:loan_id=id"`
+}
+
+func NewRestructuring(loan *Loan, requestedBy uint, extraInstallments int, holidayPeriods int, reason string) (*Restructuring, error) {
+	if extraInstallments < 0 || holidayPeriods < 0 || extraInstallments+holidayPeriods == 0 {
+		return nil, errors.New("invalid_restructuring")
+	}
+
+	return &Restructuring{
+		UUID:              uuid.New(),
+		LoanID:            loan.ID,
+		RequestedBy:       requestedBy,
+		Status:            RestructuringRequested,
+		ExtraInstallments: extraInstallments,
+		HolidayPeriods:    holidayPeriods,
+		Reason:            reason,
+		CreatedAt:         time.Now(),
+		Loan:              loan,
+	}, nil
+}
+
+func (r *Restructuring) decide(status RestructuringStatus, supervisorID uint, note string) {
+	now := time.Now()
+	r.Status = status
+	r.DecidedBy = &supervisorID
+	r.DecisionNote = note
+	r.DecidedAt = &now
+	r.UpdatedAt = &now
+}
+
+func (r *Restructuring) Reject(supervisorID uint, note string) {
+	r.decide(RestructuringRejected, supervisorID, note)
+}
+
+// Approve supersedes every unpaid installment and spreads what they still
+// owe over the new schedule, starting after the holiday periods. Paid
+// installments are left untouched. It returns the installments to save,
+// superseded ones included.
+func (r *Restructuring) Approve(supervisorID uint, note string, installments []Installment, asOf time.Time) []Installment {
+	r.decide(RestructuringApproved, supervisorID, note)
+
+	principal := 0.0
+	interest := 0.0
+	unpaid := 0
+	lastSequence := 0
+	changed := make([]Installment, 0, len(installments))
+	for _, installment := range installments {
+		lastSequence = max(lastSequence, installment.Sequence)
+		if installment.Paid() {
+			continue
+		}
+
+		// Unpaid interest and late fees carry over as interest
+		principal += installment.OutstandingPrincipal()
+		interest += installment.Outstanding() - installment.OutstandingPrincipal()
+		unpaid++
+
+		installment.Supersede(asOf)
+		changed = append(changed, installment)
+	}
+
+	count := unpaid + r.ExtraInstallments
+	if count == 0 {
+		return changed
+	}
+
+	start := truncateDay(asOf)
+	principalPerInstallment := roundAmount(principal / float64(count))
+	interestPerInstallment := roundAmount(interest / float64(count))
+	for index := 1; index <= count; index++ {
+		installment := Installment{
+			UUID:            uuid.New(),
+			LoanID:          r.LoanID,
+			Sequence:        lastSequence + index,
+			DueDate:         r.Loan.Frequency.DueDate(start, r.HolidayPeriods+index),
+			PrincipalAmount: principalPerInstallment,
+			InterestAmount:  interestPerInstallment,
+			RestructuringID: &r.ID,
+			CreatedAt:       asOf,
+		}
+
+		if index == count {
+			installment.PrincipalAmount = roundAmount(principal - principalPerInstallment*float64(count-1))
+			installment.InterestAmount = roundAmount(interest - interestPerInstallment*float64(count-1))
+		}
+
+		changed = append(changed, installment)
+	}
+
+	r.Loan.Restructure(r.ExtraInstallments)
+
+	return changed
+}
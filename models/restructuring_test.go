@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestructuringApprove(t *testing.T) {
+	tests := []struct {
+		name              string
+		extraInstallments int
+		holidayPeriods    int
+		secondPaid        float64
+		wantPrincipal     []float64
+		wantInterest      []float64
+		wantDueDates      []time.Time
+		wantTenor         int
+	}{
+		{
+			name:              "late fees carry over as interest",
+			extraInstallments: 1,
+			wantPrincipal:     []float64{66.67, 66.67, 66.66},
+			wantInterest:      []float64{8.33, 8.33, 8.34},
+			wantDueDates:      []time.Time{date(2024, time.April, 5), date(2024, time.May, 5), date(2024, time.June, 5)},
+			wantTenor:         4,
+		},
+		{
+			name:           "partly paid installment after a holiday",
+			holidayPeriods: 1,
+			secondPaid:     8,
+			wantPrincipal:  []float64{100, 100},
+			wantInterest:   []float64{8.5, 8.5},
+			wantDueDates:   []time.Time{date(2024, time.May, 5), date(2024, time.June, 5)},
+			wantTenor:      3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{ID: 1, Tenor: 3, Frequency: InstallmentFrequencyMonthly, Status: LoanStatusDelinquent}
+			installments := []Installment{
+				{Sequence: 1, DueDate: date(2024, time.January, 1), PrincipalAmount: 100, InterestAmount: 10, PaidAmount: 110},
+				{Sequence: 2, DueDate: date(2024, time.February, 1), PrincipalAmount: 100, InterestAmount: 10, LateFee: 5, PaidAmount: tt.secondPaid},
+				{Sequence: 3, DueDate: date(2024, time.March, 1), PrincipalAmount: 100, InterestAmount: 10},
+			}
+
+			restructuring, err := NewRestructuring(loan, 3, tt.extraInstallments, tt.holidayPeriods, "harvest failed")
+			if err != nil {
+				t.Fatalf("new restructuring: %v", err)
+			}
+
+			asOf := date(2024, time.March, 5)
+			changed := restructuring.Approve(6, "approved", installments, asOf)
+
+			// The unpaid installments come back superseded, the new schedule follows
+			if len(changed) != 2+len(tt.wantPrincipal) {
+				t.Fatalf("got %d installments, want %d", len(changed), 2+len(tt.wantPrincipal))
+			}
+
+			for _, superseded := range changed[:2] {
+				if superseded.SupersededAt == nil || !superseded.SupersededAt.Equal(asOf) {
+					t.Errorf("installment %d superseded at = %v, want %v", superseded.Sequence, superseded.SupersededAt, asOf)
+				}
+			}
+
+			for index, installment := range changed[2:] {
+				if installment.Sequence != 4+index {
+					t.Errorf("installment %d sequence = %d, want %d", index, installment.Sequence, 4+index)
+				}
+
+				if installment.PrincipalAmount != tt.wantPrincipal[index] {
+					t.Errorf("installment %d principal = %v, want %v", index, installment.PrincipalAmount, tt.wantPrincipal[index])
+				}
+
+				if installment.InterestAmount != tt.wantInterest[index] {
+					t.Errorf("installment %d interest = %v, want %v", index, installment.InterestAmount, tt.wantInterest[index])
+				}
+
+				if installment.LateFee != 0 {
+					t.Errorf("installment %d late fee = %v, want 0", index, installment.LateFee)
+				}
+
+				if !installment.DueDate.Equal(tt.wantDueDates[index]) {
+					t.Errorf("installment %d due date = %v, want %v", index, installment.DueDate, tt.wantDueDates[index])
+				}
+			}
+
+			if loan.Tenor != tt.wantTenor {
+				t.Errorf("tenor = %d, want %d", loan.Tenor, tt.wantTenor)
+			}
+
+			if loan.Status != LoanStatusDisbursed {
+				t.Errorf("status = %v, want %v", loan.Status, LoanStatusDisbursed)
+			}
+		})
+	}
+}
@@ -9,10 +9,13 @@ import (
 type UserRole int
 
 const (
-	RoleBorower        UserRole = 1
-	RoleFieldValidator UserRole = 2
-	RoleFieldOfficer   UserRole = 3
-	RoleInvestor       UserRole = 4
+	RoleBorower         UserRole = 1
+	RoleFieldValidator  UserRole = 2
+	RoleFieldOfficer    UserRole = 3
+	RoleInvestor        UserRole = 4
+	RoleAdmin           UserRole = 5
+	RoleSupervisor      UserRole = 6
+	RoleCreditCommittee UserRole = 7
 )
 
 func (s UserRole) String() string {
@@ -25,20 +28,49 @@ func (s UserRole) String() string {
 		return "field_officer"
 	case RoleInvestor:
 		return "role_investor"
+	case RoleAdmin:
+		return "admin"
+	case RoleSupervisor:
+		return "supervisor"
+	case RoleCreditCommittee:
+		return "credit_committee"
 	default:
 		return "unknown"
 	}
 }
 
+// ParseUserRole reads a role by the name String gives it
+func ParseUserRole(value string) (UserRole, bool) {
+	for role := RoleBorower; role <= RoleCreditCommittee; role++ {
+		if role.String() == value {
+			return role, true
+		}
+	}
+
+	return 0, false
+}
+
 type User struct {
 	bun.BaseModel `bun:"table:users"`
 
-	ID        uint      `bun:"id,pk,nullzero"`
-	Name      string    `bun:"name"`
-	Email     string    `bun:"email"`
-	Role      UserRole  `bun:"role"`
-	CreatedAt time.Time `bun:"created_at"`
-	UpdatedAt time.Time `bun:"updated_at"`
+	ID          uint     `bun:"id,pk,nullzero"`
+	Name        string   `bun:"name"`
+	Email       string   `bun:"email"`
+	Role        UserRole `bun:"role"`
+	Sector      string   `bun:"sector"`
+	Region      string   `bun:"region"`
+	RiskGrade   string   `bun:"risk_grade"`
+	KYCVerified bool     `bun:"kyc_verified"`
+
+	// Where the borrower is registered to live, field visits are checked against it
+	AddressLatitude  *float64 `bun:"address_latitude"`
+	AddressLongitude *float64 `bun:"address_longitude"`
+
+	BankCode          string    `bun:"bank_code"`
+	BankAccountNumber string    `bun:"bank_account_number"`
+	BankAccountName   string    `bun:"bank_account_name"`
+	CreatedAt         time.Time `bun:"created_at"`
+	UpdatedAt         time.Time `bun:"updated_at"`
 }
 
 // func GetUser(role UserRole) *User {
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type WalletTransactionType string
+
+const (
+	WalletTransactionDeposit    WalletTransactionType = "deposit"
+	WalletTransactionWithdrawal WalletTransactionType = "withdrawal"
+	WalletTransactionReserve    WalletTransactionType = "reserve"
+	WalletTransactionRelease    WalletTransactionType = "release"
+	WalletTransactionSettle     WalletTransactionType = "settle"
+	WalletTransactionPurchase   WalletTransactionType = "purchase"
+	WalletTransactionSale       WalletTransactionType = "sale"
+	WalletTransactionPayout     WalletTransactionType = "payout"
+	WalletTransactionRefund     WalletTransactionType = "refund"
+)
+
+var WalletTransactionTypes = map[WalletTransactionType]bool{
+	WalletTransactionDeposit:    true,
+	WalletTransactionWithdrawal: true,
+	WalletTransactionReserve:    true,
+	WalletTransactionRelease:    true,
+	WalletTransactionSettle:     true,
+	WalletTransactionPurchase:   true,
+	WalletTransactionSale:       true,
+	WalletTransactionPayout:     true,
+	WalletTransactionRefund:     true,
+}
+
+type WalletTransactionStatus string
+
+const (
+	WalletTransactionSuccess WalletTransactionStatus = "success"
+	WalletTransactionFailed  WalletTransactionStatus = "failed"
+)
+
+// Wallet holds the investor funds. Balance is everything the investor owns on
+// the platform, ReservedBalance the part committed to loans not yet disbursed.
+type Wallet struct {
+	bun.BaseModel `bun:"table:wallets"`
+
+	ID              uint       `bun:"id,pk,nullzero"`
+	UUID            uuid.UUID  `bun:"uuid"`
+	UserID          uint       `bun:"user_id"`
+	Balance         float64    `bun:"balance"`
+	ReservedBalance float64    `bun:"reserved_balance"`
+	CreatedAt       time.Time  `bun:"created_at"`
+	UpdatedAt       *time.Time `bun:"updated_at,nullzero"`
+}
+
+func NewWallet(userID uint) *Wallet {
+	return &Wallet{
+		UUID:      uuid.New(),
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (w *Wallet) AvailableBalance() float64 {
+	return w.Balance - w.ReservedBalance
+}
+
+type WalletTransaction struct {
+	bun.BaseModel `bun:"table:wallet_transactions"`
+
+	ID                   uint                    `bun:"id,pk,nullzero"`
+	UUID                 uuid.UUID               `bun:"uuid"`
+	WalletID             uint                    `bun:"wallet_id"`
+	Type                 WalletTransactionType   `bun:"type"`
+	Status               WalletTransactionStatus `bun:"status"`
+	Amount               float64                 `bun:"amount"`
+	BalanceAfter         float64                 `bun:"balance_after"`
+	ReservedBalanceAfter float64                 `bun:"reserved_balance_after"`
+	Reference            string                  `bun:"reference"`
+	FailureReason        string                  `bun:"failure_reason"`
+	LoanID               *uint                   `bun:"loan_id"`
+	InvestmentID         *uint                   `bun:"investment_id"`
+	CreatedAt            time.Time               `bun:"created_at"`
+}
+
+func NewWalletTransaction(transactionType WalletTransactionType, amount float64, reference string) *WalletTransaction {
+	return &WalletTransaction{
+		UUID:      uuid.New(),
+		Type:      transactionType,
+		Status:    WalletTransactionSuccess,
+		Amount:    amount,
+		Reference: reference,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewInvestmentTransaction moves funds held for an investment
+func NewInvestmentTransaction(transactionType WalletTransactionType, investment *Investment) *WalletTransaction {
+	transaction := NewWalletTransaction(transactionType, investment.Amount, "")
+	transaction.LoanID = &investment.LoanID
+	if investment.ID != 0 {
+		transaction.InvestmentID = &investment.ID
+	}
+
+	return transaction
+}
+
+// NewTransferTransaction books one side of a secondary market trade
+func NewTransferTransaction(transactionType WalletTransactionType, listing *InvestmentListing) *WalletTransaction {
+	transaction := NewWalletTransaction(transactionType, listing.Price, listing.UUID.String())
+	transaction.LoanID = &listing.Investment.LoanID
+	transaction.InvestmentID = &listing.InvestmentID
+
+	return transaction
+}
+
+// NewRefundTransaction gives back a withdrawal the bank transfer failed for, it
+// carries the withdrawal reference so a refund is booked only once.
+func NewRefundTransaction(withdrawal *WalletTransaction, reason string) *WalletTransaction {
+	transaction := NewWalletTransaction(WalletTransactionRefund, withdrawal.Amount, withdrawal.Reference)
+	transaction.FailureReason = reason
+
+	return transaction
+}
+
+// Deltas is how the transaction moves the balance and the reserved balance
+func (t *WalletTransaction) Deltas() (balance float64, reserved float64) {
+	if t.Status != WalletTransactionSuccess {
+		return 0, 0
+	}
+
+	switch t.Type {
+	case WalletTransactionDeposit:
+		return t.Amount, 0
+	case WalletTransactionWithdrawal:
+		return -t.Amount, 0
+	case WalletTransactionReserve:
+		return 0, t.Amount
+	case WalletTransactionRelease:
+		return 0, -t.Amount
+	case WalletTransactionSettle:
+		return -t.Amount, -t.Amount
+	case WalletTransactionPurchase:
+		return -t.Amount, 0
+	case WalletTransactionSale, WalletTransactionPayout, WalletTransactionRefund:
+		return t.Amount, 0
+	default:
+		return 0, 0
+	}
+}
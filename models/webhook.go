@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	WebhookEventLoanProposed           = "loan.proposed"
+	WebhookEventLoanRejected           = "loan.rejected"
+	WebhookEventLoanApproved           = "loan.approved"
+	WebhookEventLoanInvested           = "loan.invested"
+	WebhookEventLoanDisbursed          = "loan.disbursed"
+	WebhookEventLoanDisbursementFailed = "loan.disbursement_failed"
+	WebhookEventLoanCancelled          = "loan.cancelled"
+	WebhookEventLoanExpired            = "loan.expired"
+	WebhookEventLoanDelinquent         = "loan.delinquent"
+	WebhookEventLoanDefaulted          = "loan.defaulted"
+	WebhookEventLoanRestructured       = "loan.restructured"
+	WebhookEventLoanWrittenOff         = "loan.written_off"
+	WebhookEventLoanRepaid             = "loan.repaid"
+	WebhookEventLoanPendingApproval    = "loan.pending_approval"
+)
+
+var WebhookEventTypes = map[string]bool{
+	WebhookEventLoanProposed:           true,
+	WebhookEventLoanRejected:           true,
+	WebhookEventLoanApproved:           true,
+	WebhookEventLoanInvested:           true,
+	WebhookEventLoanDisbursed:          true,
+	WebhookEventLoanDisbursementFailed: true,
+	WebhookEventLoanCancelled:          true,
+	WebhookEventLoanExpired:            true,
+	WebhookEventLoanDelinquent:         true,
+	WebhookEventLoanDefaulted:          true,
+	WebhookEventLoanRestructured:       true,
+	WebhookEventLoanWrittenOff:         true,
+	WebhookEventLoanRepaid:             true,
+	WebhookEventLoanPendingApproval:    true,
+}
+
+type WebhookSubscription struct {
+	bun.BaseModel `bun:"table:webhook_subscriptions"`
+
+	ID         uint       `bun:"id,pk,nullzero"`
+	UUID       uuid.UUID  `bun:"uuid"`
+	URL        string     `bun:"url"`
+	EventTypes []string   `bun:"event_types,array"`
+	Secret     string     `bun:"secret"`
+	Active     bool       `bun:"active"`
+	CreatedAt  time.Time  `bun:"created_at"`
+	UpdatedAt  *time.Time `bun:"updated_at,nullzero"`
+}
+
+func NewWebhookSubscription(url string, eventTypes []string, secret string) *WebhookSubscription {
+	return &WebhookSubscription{
+		UUID:       uuid.New(),
+		URL:        url,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func (w *WebhookSubscription) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookDelivery struct {
+	bun.BaseModel `bun:"table:webhook_deliveries"`
+
+	ID             uint            `bun:"id,pk,nullzero"`
+	UUID           uuid.UUID       `bun:"uuid"`
+	SubscriptionID uint            `bun:"subscription_id"`
+	EventID        uuid.UUID       `bun:"event_id"`
+	EventType      string          `bun:"event_type"`
+	Payload        json.RawMessage `bun:"payload,type:jsonb"`
+	Attempt        int             `bun:"attempt"`
+	StatusCode     int             `bun:"status_code"`
+	ResponseBody   string          `bun:"response_body"`
+	Error          string          `bun:"error"`
+	Success        bool            `bun:"success"`
+	DurationMs     int64           `bun:"duration_ms"`
+	CreatedAt      time.Time       `bun:"created_at"`
+
+	Subscription *WebhookSubscription `bun:"rel:has-one,join:subscription_id=id"`
+}
+
+func NewWebhookDelivery(subscription *WebhookSubscription, eventID uuid.UUID, eventType string, payload json.RawMessage, attempt int) *WebhookDelivery {
+	return &WebhookDelivery{
+		UUID:           uuid.New(),
+		SubscriptionID: subscription.ID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Attempt:        attempt,
+		CreatedAt:      time.Now(),
+		Subscription:   subscription,
+	}
+}
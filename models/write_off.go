@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// WriteOff closes a loan as a loss. The schedule stays as it was when the
+// loan was written off, the principal still owed is split between the
+// investors in proportion to their investment.
+type WriteOff struct {
+	bun.BaseModel `bun:"table:write_offs"`
+
+	ID                   uint      `bun:"id,pk,nullzero"`
+	UUID                 uuid.UUID `bun:"uuid"`
+	LoanID               uint      `bun:"loan_id"`
+	ApprovedBy           uint      `bun:"approved_by"`
+	Reason               string    `bun:"reason"`
+	OutstandingPrincipal float64   `bun:"outstanding_principal"`
+	OutstandingInterest  float64   `bun:"outstanding_interest"`
+	CreatedAt            time.Time `bun:"created_at"`
+
+	Losses []InvestmentLoss `bun:"rel:has-many,join:id=write_off_id"`
+}
+
+// InvestmentLoss is the share of a write-off borne by one investment
+type InvestmentLoss struct {
+	bun.BaseModel `bun:"table:investment_losses"`
+
+	ID           uint      `bun:"id,pk,nullzero"`
+	WriteOffID   uint      `bun:"write_off_id"`
+	InvestmentID uint      `bun:"investment_id"`
+	InvestorID   uint      `bun:"investor_id"`
+	LoanID       uint      `bun:"loan_id"`
+	Amount       float64   `bun:"amount"`
+	CreatedAt    time.Time `bun:"created_at"`
+}
+
+func NewWriteOff(loan *Loan, supervisorID uint, reason string, installments []Installment, investments []Investment) *WriteOff {
+	now := time.Now()
+	writeOff := &WriteOff{
+		UUID:       uuid.New(),
+		LoanID:     loan.ID,
+		ApprovedBy: supervisorID,
+		Reason:     reason,
+		CreatedAt:  now,
+	}
+
+	for _, installment := range installments {
+		if installment.SupersededAt != nil {
+			continue
+		}
+
+		writeOff.OutstandingPrincipal += installment.OutstandingPrincipal()
+		writeOff.OutstandingInterest += installment.Outstanding() - installment.OutstandingPrincipal()
+	}
+	writeOff.OutstandingPrincipal = roundAmount(writeOff.OutstandingPrincipal)
+	writeOff.OutstandingInterest = roundAmount(writeOff.OutstandingInterest)
+
+	invested := 0.0
+	for _, investment := range investments {
+		invested += investment.Amount
+	}
+
+	// The last investment absorbs the rounding so the losses add up exactly
+	allocated := 0.0
+	for index, investment := range investments {
+		amount := roundAmount(writeOff.OutstandingPrincipal * investment.Amount / invested)
+		if index == len(investments)-1 {
+			amount = roundAmount(writeOff.OutstandingPrincipal - allocated)
+		}
+		allocated += amount
+
+		writeOff.Losses = append(writeOff.Losses, InvestmentLoss{
+			InvestmentID: investment.ID,
+			InvestorID:   investment.InvestorID,
+			LoanID:       loan.ID,
+			Amount:       amount,
+			CreatedAt:    now,
+		})
+	}
+
+	loan.WriteOff(reason)
+
+	return writeOff
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+)
+
+func TestNewWriteOff(t *testing.T) {
+	tests := []struct {
+		name          string
+		installments  []Installment
+		investments   []float64
+		wantPrincipal float64
+		wantInterest  float64
+		wantLosses    []float64
+	}{
+		{
+			name: "last investment absorbs the rounding",
+			installments: []Installment{
+				{PrincipalAmount: 500, InterestAmount: 50, SupersededAt: ptr.Of(date(2024, time.March, 1))},
+				{PrincipalAmount: 1000, InterestAmount: 100},
+			},
+			investments:   []float64{100, 100, 100},
+			wantPrincipal: 1000,
+			wantInterest:  100,
+			wantLosses:    []float64{333.33, 333.33, 333.34},
+		},
+		{
+			name: "partly paid installment",
+			installments: []Installment{
+				{PrincipalAmount: 1000, InterestAmount: 100, PaidAmount: 1100},
+				{PrincipalAmount: 1000, InterestAmount: 100, LateFee: 20, PaidAmount: 320},
+			},
+			investments:   []float64{600, 200},
+			wantPrincipal: 800,
+			wantInterest:  0,
+			wantLosses:    []float64{600, 200},
+		},
+		{
+			name: "unpaid late fees count as interest",
+			installments: []Installment{
+				{PrincipalAmount: 1000, InterestAmount: 100, LateFee: 55, PaidAmount: 30},
+			},
+			investments:   []float64{700, 300},
+			wantPrincipal: 1000,
+			wantInterest:  125,
+			wantLosses:    []float64{700, 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{ID: 1, Status: LoanStatusDefaulted}
+			investments := make([]Investment, len(tt.investments))
+			for index, amount := range tt.investments {
+				investments[index] = Investment{ID: uint(index + 1), InvestorID: uint(index + 10), LoanID: loan.ID, Amount: amount}
+			}
+
+			writeOff := NewWriteOff(loan, 6, "uncollectible", tt.installments, investments)
+			if writeOff.OutstandingPrincipal != tt.wantPrincipal {
+				t.Errorf("outstanding principal = %v, want %v", writeOff.OutstandingPrincipal, tt.wantPrincipal)
+			}
+
+			if writeOff.OutstandingInterest != tt.wantInterest {
+				t.Errorf("outstanding interest = %v, want %v", writeOff.OutstandingInterest, tt.wantInterest)
+			}
+
+			if len(writeOff.Losses) != len(tt.wantLosses) {
+				t.Fatalf("got %d losses, want %d", len(writeOff.Losses), len(tt.wantLosses))
+			}
+
+			total := 0.0
+			for index, loss := range writeOff.Losses {
+				if loss.Amount != tt.wantLosses[index] {
+					t.Errorf("loss %d = %v, want %v", index, loss.Amount, tt.wantLosses[index])
+				}
+
+				if loss.InvestorID != investments[index].InvestorID {
+					t.Errorf("loss %d investor = %v, want %v", index, loss.InvestorID, investments[index].InvestorID)
+				}
+				total += loss.Amount
+			}
+
+			if roundAmount(total) != writeOff.OutstandingPrincipal {
+				t.Errorf("losses add up to %v, want %v", roundAmount(total), writeOff.OutstandingPrincipal)
+			}
+
+			if loan.Status != LoanStatusWrittenOff {
+				t.Errorf("loan status = %v, want %v", loan.Status, LoanStatusWrittenOff)
+			}
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type ApprovalRepositoryInterface interface {
-	Save(ctx context.Context, loan *models.Approval) (*models.Approval, error)
+	Save(tx *bun.Tx, ctx context.Context, approval *models.Approval) (*models.Approval, error)
 }
 
 type approvalRepository struct {
@@ -21,8 +21,13 @@ func NewApprovalRepository(db *bun.DB) ApprovalRepositoryInterface {
 	}
 }
 
-func (r *approvalRepository) Save(ctx context.Context, approval *models.Approval) (*models.Approval, error) {
-	_, err := r.db.NewInsert().Model(approval).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+func (r *approvalRepository) Save(tx *bun.Tx, ctx context.Context, approval *models.Approval) (*models.Approval, error) {
+	var db bun.IDB = r.db
+	if tx != nil {
+		db = tx
+	}
+
+	_, err := db.NewInsert().Model(approval).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type ApprovalSignOffRepositoryInterface interface {
+	Save(ctx context.Context, loan *models.Loan, signOff *models.ApprovalSignOff) error
+	ListByApproval(ctx context.Context, approvalID uint) ([]models.ApprovalSignOff, error)
+}
+
+type approvalSignOffRepository struct {
+	db *bun.DB
+}
+
+func NewApprovalSignOffRepository(db *bun.DB) ApprovalSignOffRepositoryInterface {
+	return &approvalSignOffRepository{
+		db: db,
+	}
+}
+
+// Save records the sign-off and approves the loan when it was the last one
+// missing. The loan row is locked first so two sign-offs landing together
+// still see each other and the loan is released exactly once.
+func (r *approvalSignOffRepository) Save(ctx context.Context, loan *models.Loan, signOff *models.ApprovalSignOff) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var status models.LoanStatus
+		err := tx.NewSelect().Model((*models.Loan)(nil)).Column("status").Where("id = ?", loan.ID).For("UPDATE").Scan(ctx, &status)
+		if err != nil {
+			return err
+		}
+
+		if status != models.LoanStatusPendingApproval {
+			return errors.New("only_pending_approval_loan_allowed")
+		}
+
+		_, err = tx.NewInsert().Model(signOff).Returning("id").Exec(ctx)
+		if err != nil {
+			var pgErr pgdriver.Error
+			if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+				if pgErr.Field('n') == "idx_approval_sign_offs_role" {
+					return errors.New("sign_off_not_required")
+				}
+
+				return errors.New("loan_already_approved_by_approver")
+			}
+
+			return err
+		}
+
+		signOffs := []models.ApprovalSignOff{}
+		err = tx.NewSelect().Model(&signOffs).Where("approval_id = ?", signOff.ApprovalID).Order("id ASC").Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		loan.Approval.SignOffs = signOffs
+		if !loan.CompleteApproval(signOffs) {
+			return nil
+		}
+
+		_, err = tx.NewUpdate().Model(loan.Approval).Column("approved_at", "updated_at").WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().Model(loan).Column("status", "updated_at").WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return saveLoanStatusChanges(ctx, tx, loan)
+	})
+}
+
+func (r *approvalSignOffRepository) ListByApproval(ctx context.Context, approvalID uint) ([]models.ApprovalSignOff, error) {
+	signOffs := []models.ApprovalSignOff{}
+	err := r.db.NewSelect().Model(&signOffs).Where("approval_id = ?", approvalID).Order("id ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return signOffs, nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type AuditLogRepositoryInterface interface {
+	Append(ctx context.Context, log *models.AuditLog) error
+	List(ctx context.Context, page int, perPage int, sort string, filter AuditLogRepositoryFilter) (*[]models.AuditLog, int, error)
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]models.AuditLog, error)
+}
+
+type AuditLogRepositoryFilter struct {
+	ActorID    *uint
+	Action     *string
+	EntityType *string
+	EntityID   *string
+	LoanID     *uint
+	From       *time.Time
+	To         *time.Time
+}
+
+var auditLogSortColumns = map[string]string{
+	"id":         "audit_log.id",
+	"created_at": "audit_log.created_at",
+}
+
+type auditLogRepository struct {
+	db *bun.DB
+}
+
+func NewAuditLogRepository(db *bun.DB) AuditLogRepositoryInterface {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+// Append chains the entry to the last one and stores it. Appends are
+// serialized by a transaction lock, two entries chained to the same
+// predecessor would fork the chain.
+func (r *auditLogRepository) Append(ctx context.Context, log *models.AuditLog) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('audit_logs'))")
+		if err != nil {
+			return err
+		}
+
+		var prevHash string
+		err = tx.NewSelect().Model((*models.AuditLog)(nil)).Column("hash").Order("id DESC").Limit(1).Scan(ctx, &prevHash)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		log.Chain(prevHash)
+
+		_, err = tx.NewInsert().Model(log).Returning("id").Exec(ctx)
+		return err
+	})
+}
+
+func (r *auditLogRepository) List(ctx context.Context, page int, perPage int, sort string, filter AuditLogRepositoryFilter) (*[]models.AuditLog, int, error) {
+	sorts, err := utils.GenerateSort(sort, auditLogSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var logs []models.AuditLog
+	sl := r.db.NewSelect().Model(&logs)
+	if filter.ActorID != nil {
+		sl.Where("? = ?", bun.Ident("audit_log.actor_id"), filter.ActorID)
+	}
+
+	if filter.Action != nil {
+		sl.Where("? = ?", bun.Ident("audit_log.action"), filter.Action)
+	}
+
+	if filter.EntityType != nil {
+		sl.Where("? = ?", bun.Ident("audit_log.entity_type"), filter.EntityType)
+	}
+
+	if filter.EntityID != nil {
+		sl.Where("? = ?", bun.Ident("audit_log.entity_id"), filter.EntityID)
+	}
+
+	if filter.LoanID != nil {
+		sl.Where("? = ?", bun.Ident("audit_log.loan_id"), filter.LoanID)
+	}
+
+	if filter.From != nil {
+		sl.Where("? >= ?", bun.Ident("audit_log.created_at"), filter.From)
+	}
+
+	if filter.To != nil {
+		sl.Where("? < ?", bun.Ident("audit_log.created_at"), filter.To)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(logs) == 0 {
+		return &[]models.AuditLog{}, count, nil
+	}
+
+	return &logs, count, nil
+}
+
+// ListAfter walks the chain in order, a page at a time
+func (r *auditLogRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.AuditLog, error) {
+	logs := []models.AuditLog{}
+	err := r.db.NewSelect().Model(&logs).Where("id > ?", afterID).Order("id ASC").Limit(limit).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
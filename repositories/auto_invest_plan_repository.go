@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type AutoInvestPlanRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter AutoInvestPlanRepositoryFilter) (*[]models.AutoInvestPlan, int, error)
+	Save(ctx context.Context, plan *models.AutoInvestPlan) (*models.AutoInvestPlan, error)
+	Detail(ctx context.Context, uuid string) (*models.AutoInvestPlan, error)
+	Delete(ctx context.Context, plan *models.AutoInvestPlan) error
+	ListForMatching(ctx context.Context) (*[]models.AutoInvestPlan, error)
+	ConsumeBudget(ctx context.Context, plan *models.AutoInvestPlan, amount float64) error
+	RefundBudget(ctx context.Context, plan *models.AutoInvestPlan, amount float64) error
+	SaveMatchFailure(ctx context.Context, failure *models.AutoInvestMatchFailure) error
+}
+
+type AutoInvestPlanRepositoryFilter struct {
+	InvestorID *uint
+	Active     *bool
+}
+
+var autoInvestPlanSortColumns = map[string]string{
+	"id":         "auto_invest_plan.id",
+	"created_at": "auto_invest_plan.created_at",
+	"budget":     "auto_invest_plan.budget",
+}
+
+type autoInvestPlanRepository struct {
+	db *bun.DB
+}
+
+func NewAutoInvestPlanRepository(db *bun.DB) AutoInvestPlanRepositoryInterface {
+	return &autoInvestPlanRepository{
+		db: db,
+	}
+}
+
+func (r *autoInvestPlanRepository) Save(ctx context.Context, plan *models.AutoInvestPlan) (*models.AutoInvestPlan, error) {
+	_, err := r.db.NewInsert().Model(plan).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (r *autoInvestPlanRepository) Detail(ctx context.Context, uuid string) (*models.AutoInvestPlan, error) {
+	var plan models.AutoInvestPlan
+	err := r.db.NewSelect().Model(&plan).Where("uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+func (r *autoInvestPlanRepository) Delete(ctx context.Context, plan *models.AutoInvestPlan) error {
+	_, err := r.db.NewDelete().Model(plan).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *autoInvestPlanRepository) List(ctx context.Context, page int, perPage int, sort string, filter AutoInvestPlanRepositoryFilter) (*[]models.AutoInvestPlan, int, error) {
+	sorts, err := utils.GenerateSort(sort, autoInvestPlanSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var plans []models.AutoInvestPlan
+	sl := r.db.NewSelect().Model(&plans)
+	if filter.InvestorID != nil {
+		sl.Where("? = ?", bun.Ident("investor_id"), filter.InvestorID)
+	}
+
+	if filter.Active != nil {
+		sl.Where("? = ?", bun.Ident("active"), filter.Active)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(plans) == 0 {
+		return &[]models.AutoInvestPlan{}, count, nil
+	}
+
+	return &plans, count, nil
+}
+
+// ListForMatching returns the active plans with budget left, the plans that
+// waited the longest since their last ticket come first.
+func (r *autoInvestPlanRepository) ListForMatching(ctx context.Context) (*[]models.AutoInvestPlan, error) {
+	var plans []models.AutoInvestPlan
+	err := r.db.NewSelect().Model(&plans).
+		Where("? = TRUE", bun.Ident("active")).
+		Where("invested_amount < budget").
+		OrderExpr("last_matched_at ASC NULLS FIRST, id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plans, nil
+}
+
+// ConsumeBudget books the amount on the plan unless it would overrun the
+// budget, so plans matched by concurrent approvals never overspend.
+func (r *autoInvestPlanRepository) ConsumeBudget(ctx context.Context, plan *models.AutoInvestPlan, amount float64) error {
+	now := time.Now()
+	err := r.db.NewUpdate().Model(plan).
+		Set("invested_amount = invested_amount + ?", amount).
+		Set("last_matched_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", plan.ID).
+		Where("invested_amount + ? <= budget", amount).
+		Returning("*").
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("auto_invest_budget_exhausted")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *autoInvestPlanRepository) RefundBudget(ctx context.Context, plan *models.AutoInvestPlan, amount float64) error {
+	_, err := r.db.NewUpdate().Model(plan).
+		Set("invested_amount = invested_amount - ?", amount).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", plan.ID).
+		Exec(ctx)
+
+	return err
+}
+
+func (r *autoInvestPlanRepository) SaveMatchFailure(ctx context.Context, failure *models.AutoInvestMatchFailure) error {
+	_, err := r.db.NewInsert().Model(failure).Returning("id").Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type BorrowerGroupRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter BorrowerGroupRepositoryFilter) (*[]models.BorrowerGroup, int, error)
+	Save(ctx context.Context, group *models.BorrowerGroup) (*models.BorrowerGroup, error)
+	Detail(ctx context.Context, uuid string) (*models.BorrowerGroup, error)
+	AddMember(ctx context.Context, member *models.BorrowerGroupMember) error
+	RemoveMember(ctx context.Context, member *models.BorrowerGroupMember) error
+}
+
+type BorrowerGroupRepositoryFilter struct {
+	FieldOfficerID *uint
+	Region         *string
+}
+
+var borrowerGroupSortColumns = map[string]string{
+	"name":       "borrower_group.name",
+	"created_at": "borrower_group.created_at",
+}
+
+type borrowerGroupRepository struct {
+	db *bun.DB
+}
+
+func NewBorrowerGroupRepository(db *bun.DB) BorrowerGroupRepositoryInterface {
+	return &borrowerGroupRepository{
+		db: db,
+	}
+}
+
+func (r *borrowerGroupRepository) List(ctx context.Context, page int, perPage int, sort string, filter BorrowerGroupRepositoryFilter) (*[]models.BorrowerGroup, int, error) {
+	sorts, err := utils.GenerateSort(sort, borrowerGroupSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var groups []models.BorrowerGroup
+	sl := r.db.NewSelect().Model(&groups).Relation("Members")
+	if filter.FieldOfficerID != nil {
+		sl.Where("? = ?", bun.Ident("borrower_group.field_officer_id"), filter.FieldOfficerID)
+	}
+
+	if filter.Region != nil {
+		sl.Where("? = ?", bun.Ident("borrower_group.region"), filter.Region)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(groups) == 0 {
+		return &[]models.BorrowerGroup{}, count, nil
+	}
+
+	return &groups, count, nil
+}
+
+// Save stores the group with the members it was created with, all or nothing
+func (r *borrowerGroupRepository) Save(ctx context.Context, group *models.BorrowerGroup) (*models.BorrowerGroup, error) {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().Model(group).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		for index := range group.Members {
+			member := &group.Members[index]
+			if member.ID != 0 {
+				continue
+			}
+
+			member.GroupID = group.ID
+			err = insertGroupMember(ctx, tx, member)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (r *borrowerGroupRepository) Detail(ctx context.Context, uuid string) (*models.BorrowerGroup, error) {
+	var group models.BorrowerGroup
+	err := r.db.NewSelect().Model(&group).Relation("Members").Relation("Members.Borrower").Where("borrower_group.uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (r *borrowerGroupRepository) AddMember(ctx context.Context, member *models.BorrowerGroupMember) error {
+	return insertGroupMember(ctx, r.db, member)
+}
+
+func (r *borrowerGroupRepository) RemoveMember(ctx context.Context, member *models.BorrowerGroupMember) error {
+	_, err := r.db.NewDelete().Model(member).WherePK().Exec(ctx)
+	return err
+}
+
+// insertGroupMember adds a member, a borrower belongs to one group at a time
+func insertGroupMember(ctx context.Context, db bun.IDB, member *models.BorrowerGroupMember) error {
+	_, err := db.NewInsert().Model(member).Returning("id").Exec(ctx)
+	if err != nil {
+		var pgErr pgdriver.Error
+		if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+			return errors.New("borrower_already_in_group")
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type DisbursementRepositoryInterface interface {
-	Save(ctx context.Context, loan *models.Disbursment) (*models.Disbursment, error)
+	Save(tx *bun.Tx, ctx context.Context, disbursement *models.Disbursment) (*models.Disbursment, error)
 }
 
 type disbursementRepository struct {
@@ -21,8 +21,13 @@ func NewDisbursementRepository(db *bun.DB) DisbursementRepositoryInterface {
 	}
 }
 
-func (r *disbursementRepository) Save(ctx context.Context, disbursement *models.Disbursment) (*models.Disbursment, error) {
-	_, err := r.db.NewInsert().Model(disbursement).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+func (r *disbursementRepository) Save(tx *bun.Tx, ctx context.Context, disbursement *models.Disbursment) (*models.Disbursment, error) {
+	var db bun.IDB = r.db
+	if tx != nil {
+		db = tx
+	}
+
+	_, err := db.NewInsert().Model(disbursement).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
 	if err != nil {
 		return nil, err
 	}
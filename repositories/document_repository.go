@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/uptrace/bun"
+)
+
+type DocumentRepositoryInterface interface {
+	Save(ctx context.Context, document *models.Document) (*models.Document, error)
+	Detail(ctx context.Context, uuid string) (*models.Document, error)
+}
+
+type documentRepository struct {
+	db *bun.DB
+}
+
+func NewDocumentRepository(db *bun.DB) DocumentRepositoryInterface {
+	return &documentRepository{
+		db: db,
+	}
+}
+
+func (r *documentRepository) Save(ctx context.Context, document *models.Document) (*models.Document, error) {
+	_, err := r.db.NewInsert().Model(document).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (r *documentRepository) Detail(ctx context.Context, uuid string) (*models.Document, error) {
+	var document models.Document
+	err := r.db.NewSelect().Model(&document).Where("uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &document, nil
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type GroupLoanRepositoryInterface interface {
+	Save(ctx context.Context, groupLoan *models.GroupLoan) (*models.GroupLoan, error)
+	Detail(ctx context.Context, uuid string) (*models.GroupLoan, error)
+	DetailProposed(ctx context.Context, groupID uint) (*models.GroupLoan, error)
+	SaveCollection(ctx context.Context, collection *models.GroupCollection) (*models.GroupCollection, error)
+	DetailCollection(ctx context.Context, reference string) (*models.GroupCollection, error)
+}
+
+type groupLoanRepository struct {
+	db *bun.DB
+}
+
+func NewGroupLoanRepository(db *bun.DB) GroupLoanRepositoryInterface {
+	return &groupLoanRepository{
+		db: db,
+	}
+}
+
+func (r *groupLoanRepository) Save(ctx context.Context, groupLoan *models.GroupLoan) (*models.GroupLoan, error) {
+	_, err := r.db.NewInsert().Model(groupLoan).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		// A group has at most one loan waiting for approval
+		var pgErr pgdriver.Error
+		if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+			return nil, errors.New("group_loan_already_proposed")
+		}
+
+		return nil, err
+	}
+
+	return groupLoan, nil
+}
+
+func (r *groupLoanRepository) Detail(ctx context.Context, uuid string) (*models.GroupLoan, error) {
+	var groupLoan models.GroupLoan
+	err := r.db.NewSelect().Model(&groupLoan).
+		Relation("Group").
+		Relation("Loans", func(sl *bun.SelectQuery) *bun.SelectQuery {
+			return sl.Order("loan.id")
+		}).
+		Where("group_loan.uuid = ?", uuid).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &groupLoan, nil
+}
+
+func (r *groupLoanRepository) DetailProposed(ctx context.Context, groupID uint) (*models.GroupLoan, error) {
+	var groupLoan models.GroupLoan
+	err := r.db.NewSelect().Model(&groupLoan).
+		Relation("Group").
+		Relation("Loans").
+		Where("group_loan.group_id = ?", groupID).
+		Where("group_loan.status = ?", models.GroupLoanProposed).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &groupLoan, nil
+}
+
+// SaveCollection records a meeting collection with its allocations. A
+// reference seen before returns the collection already recorded, so a
+// field officer retrying gets the original allocations applied again.
+func (r *groupLoanRepository) SaveCollection(ctx context.Context, collection *models.GroupCollection) (*models.GroupCollection, error) {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewInsert().Model(collection).On("CONFLICT (reference) DO NOTHING").Returning("id").Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			existing := models.GroupCollection{}
+			err = tx.NewSelect().Model(&existing).Relation("Allocations").Where("group_collection.reference = ?", collection.Reference).Scan(ctx)
+			if err != nil {
+				return err
+			}
+
+			if existing.GroupLoanID != collection.GroupLoanID {
+				return errors.New("group_collection_reference_taken")
+			}
+
+			*collection = existing
+			return nil
+		}
+
+		if len(collection.Allocations) == 0 {
+			return nil
+		}
+
+		for index := range collection.Allocations {
+			collection.Allocations[index].GroupCollectionID = collection.ID
+		}
+
+		_, err = tx.NewInsert().Model(&collection.Allocations).Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+func (r *groupLoanRepository) DetailCollection(ctx context.Context, reference string) (*models.GroupCollection, error) {
+	var collection models.GroupCollection
+	err := r.db.NewSelect().Model(&collection).Relation("Allocations").Where("group_collection.reference = ?", reference).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &collection, nil
+}
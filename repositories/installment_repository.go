@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type InstallmentRepositoryInterface interface {
+	ListByLoan(ctx context.Context, loanID uint) ([]models.Installment, error)
+	ListHistory(ctx context.Context, loanID uint) ([]models.Installment, error)
+	SaveSchedule(ctx context.Context, installments []models.Installment) error
+	SaveLateFees(ctx context.Context, installments []models.Installment) error
+	SaveRepayment(ctx context.Context, payment *models.Payment) ([]models.Installment, error)
+	Count(ctx context.Context, filter InstallmentRepositoryFilter) (int, error)
+}
+
+type InstallmentRepositoryFilter struct {
+	LoanID     *uint
+	BorrowerID *uint
+	Paid       *bool
+	PaidLate   *bool
+	DueBefore  *time.Time
+}
+
+type installmentRepository struct {
+	db *bun.DB
+}
+
+func NewInstallmentRepository(db *bun.DB) InstallmentRepositoryInterface {
+	return &installmentRepository{
+		db: db,
+	}
+}
+
+// ListByLoan returns the schedule the borrower is repaying, installments
+// replaced by a restructuring are left out.
+func (r *installmentRepository) ListByLoan(ctx context.Context, loanID uint) ([]models.Installment, error) {
+	installments := []models.Installment{}
+	err := r.db.NewSelect().Model(&installments).
+		Where("loan_id = ?", loanID).
+		Where("superseded_at IS NULL").
+		Order("sequence ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return installments, nil
+}
+
+// ListHistory returns every installment the loan ever had
+func (r *installmentRepository) ListHistory(ctx context.Context, loanID uint) ([]models.Installment, error) {
+	installments := []models.Installment{}
+	err := r.db.NewSelect().Model(&installments).Where("loan_id = ?", loanID).Order("sequence ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return installments, nil
+}
+
+// SaveSchedule stores a new repayment schedule, installments already there
+// are kept so a retried disbursement callback does not reset repayments.
+func (r *installmentRepository) SaveSchedule(ctx context.Context, installments []models.Installment) error {
+	if len(installments) == 0 {
+		return nil
+	}
+
+	_, err := r.db.NewInsert().Model(&installments).On("CONFLICT (loan_id, sequence) DO NOTHING").Exec(ctx)
+	return err
+}
+
+// SaveLateFees writes only the late fees charged on the installments, so a
+// repayment allocated meanwhile keeps what it paid. A fee is charged once.
+func (r *installmentRepository) SaveLateFees(ctx context.Context, installments []models.Installment) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, installment := range installments {
+			if installment.LateFee == 0 {
+				continue
+			}
+
+			_, err := tx.NewUpdate().Model((*models.Installment)(nil)).
+				Set("late_fee = ?", installment.LateFee).
+				Where("id = ?", installment.ID).
+				Where("late_fee = 0").
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// SaveRepayment records the collection and allocates it to the installments
+// in one database transaction. The loan row is locked before the schedule is
+// read, so repayments landing together are allocated one after the other and
+// none overwrites what another paid. A gateway reference seen before is
+// refused by the unique index so a repayment is never allocated twice.
+func (r *installmentRepository) SaveRepayment(ctx context.Context, payment *models.Payment) ([]models.Installment, error) {
+	installments := []models.Installment{}
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var loanID uint
+		err := tx.NewSelect().Model((*models.Loan)(nil)).Column("id").Where("id = ?", payment.LoanID).For("UPDATE").Scan(ctx, &loanID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(payment).Returning("id").Exec(ctx)
+		if err != nil {
+			var pgErr pgdriver.Error
+			if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+				return errors.New("duplicate_payment")
+			}
+
+			return err
+		}
+
+		err = tx.NewSelect().Model(&installments).
+			Where("loan_id = ?", payment.LoanID).
+			Where("superseded_at IS NULL").
+			Order("sequence ASC").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(installments) == 0 {
+			return nil
+		}
+
+		models.AllocateRepayment(installments, payment.Amount, *payment.CompletedAt)
+
+		_, err = tx.NewInsert().Model(&installments).On("CONFLICT (id) DO UPDATE").Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return installments, nil
+}
+
+func (r *installmentRepository) Count(ctx context.Context, filter InstallmentRepositoryFilter) (int, error) {
+	sl := r.db.NewSelect().Model((*models.Installment)(nil)).Where("installment.superseded_at IS NULL")
+	if filter.LoanID != nil {
+		sl.Where("? = ?", bun.Ident("installment.loan_id"), filter.LoanID)
+	}
+
+	if filter.BorrowerID != nil {
+		sl.Where("installment.loan_id IN (SELECT id FROM loans WHERE borrower_id = ?)", filter.BorrowerID)
+	}
+
+	if filter.Paid != nil {
+		if *filter.Paid {
+			sl.Where("installment.paid_at IS NOT NULL")
+		} else {
+			sl.Where("installment.paid_at IS NULL")
+		}
+	}
+
+	if filter.PaidLate != nil {
+		if *filter.PaidLate {
+			sl.Where("installment.paid_at >= installment.due_date + INTERVAL '1 day'")
+		} else {
+			sl.Where("installment.paid_at < installment.due_date + INTERVAL '1 day'")
+		}
+	}
+
+	if filter.DueBefore != nil {
+		sl.Where("? < ?", bun.Ident("installment.due_date"), filter.DueBefore)
+	}
+
+	return sl.Count(ctx)
+}
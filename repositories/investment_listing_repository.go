@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type InvestmentListingRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter InvestmentListingRepositoryFilter) (*[]models.InvestmentListing, int, error)
+	Save(ctx context.Context, listing *models.InvestmentListing) (*models.InvestmentListing, error)
+	Detail(ctx context.Context, uuid string) (*models.InvestmentListing, error)
+	Settle(ctx context.Context, listing *models.InvestmentListing, transfer *models.InvestmentTransfer) error
+	ListTransfers(ctx context.Context, page int, perPage int, sort string, filter InvestmentTransferRepositoryFilter) (*[]models.InvestmentTransfer, int, error)
+}
+
+type InvestmentListingRepositoryFilter struct {
+	SellerID *uint
+	LoanID   *uint
+	Status   *models.InvestmentListingStatus
+}
+
+type InvestmentTransferRepositoryFilter struct {
+	InvestorID   *uint
+	InvestmentID *uint
+}
+
+var investmentListingSortColumns = map[string]string{
+	"id":         "investment_listing.id",
+	"created_at": "investment_listing.created_at",
+	"price":      "investment_listing.price",
+}
+
+var investmentTransferSortColumns = map[string]string{
+	"id":         "investment_transfer.id",
+	"created_at": "investment_transfer.created_at",
+}
+
+type investmentListingRepository struct {
+	db *bun.DB
+}
+
+func NewInvestmentListingRepository(db *bun.DB) InvestmentListingRepositoryInterface {
+	return &investmentListingRepository{
+		db: db,
+	}
+}
+
+func (r *investmentListingRepository) Save(ctx context.Context, listing *models.InvestmentListing) (*models.InvestmentListing, error) {
+	_, err := r.db.NewInsert().Model(listing).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		// Only one open listing per investment, enforced by a partial unique index
+		var pgErr pgdriver.Error
+		if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+			return nil, errors.New("investment_already_listed")
+		}
+
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+func (r *investmentListingRepository) Detail(ctx context.Context, uuid string) (*models.InvestmentListing, error) {
+	var listing models.InvestmentListing
+	err := r.db.NewSelect().Model(&listing).
+		Relation("Investment").
+		Relation("Investment.Loan").
+		Where("investment_listing.uuid = ?", uuid).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+func (r *investmentListingRepository) List(ctx context.Context, page int, perPage int, sort string, filter InvestmentListingRepositoryFilter) (*[]models.InvestmentListing, int, error) {
+	sorts, err := utils.GenerateSort(sort, investmentListingSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var listings []models.InvestmentListing
+	sl := r.db.NewSelect().Model(&listings).Relation("Investment").Relation("Investment.Loan")
+	if filter.SellerID != nil {
+		sl.Where("? = ?", bun.Ident("investment_listing.seller_id"), filter.SellerID)
+	}
+
+	if filter.LoanID != nil {
+		sl.Where("? = ?", bun.Ident("investment.loan_id"), filter.LoanID)
+	}
+
+	if filter.Status != nil {
+		sl.Where("? = ?", bun.Ident("investment_listing.status"), filter.Status)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(listings) == 0 {
+		return &[]models.InvestmentListing{}, count, nil
+	}
+
+	return &listings, count, nil
+}
+
+// Settle closes the sale, hands the investment to the buyer, moves the price
+// between the wallets and records the transfer in a single database
+// transaction. The listing and the investment are updated conditionally so a
+// listing bought twice at the same time, or already cancelled, is refused.
+func (r *investmentListingRepository) Settle(ctx context.Context, listing *models.InvestmentListing, transfer *models.InvestmentTransfer) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().Model(listing).
+			Column("status", "buyer_id", "sold_at", "updated_at").
+			WherePK().
+			Where("status = ?", models.InvestmentListingOpen).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return errors.New("investment_listing_not_available")
+		}
+
+		result, err = tx.NewUpdate().Model(listing.Investment).
+			Column("investor_id", "updated_at").
+			WherePK().
+			Where("investor_id = ?", listing.SellerID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return errors.New("investment_listing_not_available")
+		}
+
+		_, err = applyWalletTransaction(ctx, tx, transfer.BuyerID, models.NewTransferTransaction(models.WalletTransactionPurchase, listing))
+		if err != nil {
+			return err
+		}
+
+		_, err = applyWalletTransaction(ctx, tx, transfer.SellerID, models.NewTransferTransaction(models.WalletTransactionSale, listing))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(transfer).Returning("id").Exec(ctx)
+		return err
+	})
+}
+
+func (r *investmentListingRepository) ListTransfers(ctx context.Context, page int, perPage int, sort string, filter InvestmentTransferRepositoryFilter) (*[]models.InvestmentTransfer, int, error) {
+	sorts, err := utils.GenerateSort(sort, investmentTransferSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var transfers []models.InvestmentTransfer
+	sl := r.db.NewSelect().Model(&transfers).Relation("Investment").Relation("Investment.Loan")
+	if filter.InvestorID != nil {
+		sl.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("investment_transfer.seller_id = ?", filter.InvestorID).
+				WhereOr("investment_transfer.buyer_id = ?", filter.InvestorID)
+		})
+	}
+
+	if filter.InvestmentID != nil {
+		sl.Where("? = ?", bun.Ident("investment_transfer.investment_id"), filter.InvestmentID)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(transfers) == 0 {
+		return &[]models.InvestmentTransfer{}, count, nil
+	}
+
+	return &transfers, count, nil
+}
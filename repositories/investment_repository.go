@@ -2,6 +2,10 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/peang/amartha-loan-service/models"
 	"github.com/peang/amartha-loan-service/utils"
@@ -9,18 +13,31 @@ import (
 )
 
 type InvestmentRepositoryInterface interface {
-	List(ctx context.Context, page int, perPage int, sort string, filter InvestmentRepositoryFilter) (*[]models.Investment, int, error)
-	Save(ctx context.Context, loan *models.Investment) (*models.Investment, error)
+	List(ctx context.Context, page int, perPage int, cursor *string, sort string, filter InvestmentRepositoryFilter) (*[]models.Investment, int, string, error)
+	Place(ctx context.Context, loanID uint, investorID uint, amount float64, limits models.InvestmentLimits) (*models.Investment, error)
+	Detail(ctx context.Context, uuid string) (*models.Investment, error)
 	UpdateMany(ctx context.Context, filter InvestmentRepositoryFilter, value InvestmentRepositoryValues) error
+	Count(ctx context.Context, filter InvestmentRepositoryFilter) (int, error)
+	Sum(ctx context.Context, filter InvestmentRepositoryFilter) (float64, error)
+	Position(ctx context.Context, investorID uint, loan *models.Loan) (*models.InvestorPosition, error)
 }
 
 type InvestmentRepositoryFilter struct {
-	LoanID *uint
+	LoanID       *uint
+	InvestorID   *uint
+	BorrowerID   *uint
+	LoanStatuses []models.LoanStatus
 }
 type InvestmentRepositoryValues struct {
 	SendAggreementEmail *bool
 }
 
+var investmentSortColumns = map[string]string{
+	"id":         "investment.id",
+	"created_at": "investment.created_at",
+	"amount":     "investment.amount",
+}
+
 type investmentRepository struct {
 	loanRepository LoanRepositoryInterface
 	db             *bun.DB
@@ -33,46 +50,72 @@ func NewInvestmentRepository(db *bun.DB, loanRepository LoanRepositoryInterface)
 	}
 }
 
-func (r *investmentRepository) Save(ctx context.Context, investment *models.Investment) (*models.Investment, error) {
-	tx, _ := r.db.Begin()
+// Place puts a new ticket on the loan and reserves its amount in the
+// investor's wallet. The loan row and the investor's wallet are locked before
+// the limits are checked, so concurrent tickets on the loan, or from the
+// investor, are checked one after the other and can never fund the loan past
+// its proposed amount nor the investor past their limits.
+func (r *investmentRepository) Place(ctx context.Context, loanID uint, investorID uint, amount float64, limits models.InvestmentLimits) (*models.Investment, error) {
+	var investment *models.Investment
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var loan models.Loan
+		err := tx.NewSelect().Model(&loan).Where("id = ?", loanID).For("UPDATE").Scan(ctx)
+		if err != nil {
+			return err
+		}
 
-	_, err := r.db.NewInsert().Model(investment).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		if loan.Status != models.LoanStatusApproved {
+			return errors.New("only_approved_loan_allowed")
+		}
+
+		err = lockWallet(ctx, tx, investorID)
+		if err != nil {
+			return err
+		}
 
-	_, err = r.loanRepository.Save(&tx, ctx, investment.Loan)
+		position, err := investorPosition(ctx, tx, investorID, &loan)
+		if err != nil {
+			return err
+		}
+
+		investment, err = models.NewInvestment(investorID, amount, &loan, limits, *position)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(investment).Returning("id").Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Funds are held in the wallet until the loan is disbursed, cancelled or expires
+		_, err = applyWalletTransaction(ctx, tx, investorID, models.NewInvestmentTransaction(models.WalletTransactionReserve, investment))
+		if err != nil {
+			return err
+		}
+
+		_, err = r.loanRepository.Save(&tx, ctx, &loan)
+		return err
+	})
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
-	tx.Commit()
-
 	return investment, nil
 }
 
-func (r *investmentRepository) List(ctx context.Context, page int, perPage int, sort string, filter InvestmentRepositoryFilter) (*[]models.Investment, int, error) {
-	offset, limit := utils.GenerateOffsetLimit(page, perPage)
-
-	var investments []models.Investment
-	sl := r.db.NewSelect().Model(&investments)
-	sl.Relation("Investor")
-	if filter.LoanID != nil {
-		sl.Where("? = ?", bun.Ident("loan_id"), filter.LoanID)
-	}
-
-	count, err := sl.Group("investment.investor_id", "investor.id").Column("investment.investor_id").Limit(limit).Offset(offset).ScanAndCount(context.TODO())
+func (r *investmentRepository) Detail(ctx context.Context, uuid string) (*models.Investment, error) {
+	var investment models.Investment
+	err := r.db.NewSelect().Model(&investment).Relation("Loan").Where("investment.uuid = ?", uuid).Scan(ctx)
 	if err != nil {
-		return nil, 0, err
-	}
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 
-	if len(investments) == 0 {
-		return &[]models.Investment{}, count, nil
+		return nil, err
 	}
 
-	return &investments, count, nil
+	return &investment, nil
 }
 
 func (r *investmentRepository) UpdateMany(ctx context.Context, filter InvestmentRepositoryFilter, value InvestmentRepositoryValues) error {
@@ -93,3 +136,166 @@ func (r *investmentRepository) UpdateMany(ctx context.Context, filter Investment
 	}
 	return nil
 }
+
+func (r *investmentRepository) Count(ctx context.Context, filter InvestmentRepositoryFilter) (int, error) {
+	sl := r.db.NewSelect().Model((*models.Investment)(nil))
+	applyInvestmentFilter(sl, filter)
+
+	return sl.Count(ctx)
+}
+
+func (r *investmentRepository) Sum(ctx context.Context, filter InvestmentRepositoryFilter) (float64, error) {
+	return sumInvestments(ctx, r.db, filter)
+}
+
+func (r *investmentRepository) Position(ctx context.Context, investorID uint, loan *models.Loan) (*models.InvestorPosition, error) {
+	return investorPosition(ctx, r.db, investorID, loan)
+}
+
+func sumInvestments(ctx context.Context, db bun.IDB, filter InvestmentRepositoryFilter) (float64, error) {
+	var total float64
+	sl := db.NewSelect().Model((*models.Investment)(nil)).ColumnExpr("COALESCE(SUM(investment.amount), 0)")
+	applyInvestmentFilter(sl, filter)
+
+	err := sl.Scan(ctx, &total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// investorPosition sums what the investor already holds on the loan, on the
+// borrower and on the whole platform
+func investorPosition(ctx context.Context, db bun.IDB, investorID uint, loan *models.Loan) (*models.InvestorPosition, error) {
+	loanAmount, err := sumInvestments(ctx, db, InvestmentRepositoryFilter{
+		InvestorID: &investorID,
+		LoanID:     &loan.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	borrowerAmount, err := sumInvestments(ctx, db, InvestmentRepositoryFilter{
+		InvestorID:   &investorID,
+		BorrowerID:   &loan.BorrowerID,
+		LoanStatuses: models.ExposureLoanStatuses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exposure, err := sumInvestments(ctx, db, InvestmentRepositoryFilter{
+		InvestorID:   &investorID,
+		LoanStatuses: models.ExposureLoanStatuses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InvestorPosition{
+		LoanAmount:     loanAmount,
+		BorrowerAmount: borrowerAmount,
+		Exposure:       exposure,
+	}, nil
+}
+
+// List pages by offset and counts the matching investments, or, given a
+// cursor, even an empty one for the first page, pages by keyset on the sort
+// column and id and returns the cursor of the next page instead of a count.
+func (r *investmentRepository) List(ctx context.Context, page int, perPage int, cursor *string, sort string, filter InvestmentRepositoryFilter) (*[]models.Investment, int, string, error) {
+	column, desc, err := utils.ParseSort(sort, investmentSortColumns)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	investments := []models.Investment{}
+	sl := r.db.NewSelect().Model(&investments).Relation("Investor").Relation("Loan")
+	applyInvestmentFilter(sl, filter)
+	sl.OrderExpr(strings.Join(utils.KeysetOrder(column, "investment.id", desc), ", "))
+
+	if cursor == nil {
+		count, err := sl.Limit(limit).Offset(offset).ScanAndCount(ctx)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		return &investments, count, "", nil
+	}
+
+	if *cursor != "" {
+		value := investmentCursorTarget(sort)
+		id, err := utils.DecodeCursor(*cursor, sort, value)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		sl.Where(utils.KeysetCondition(column, "investment.id", desc), value, id)
+	}
+
+	// One extra row tells whether there is a next page without counting
+	err = sl.Limit(limit + 1).Scan(ctx)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	if len(investments) <= limit {
+		return &investments, 0, "", nil
+	}
+
+	investments = investments[:limit]
+	last := investments[limit-1]
+	nextCursor, err := utils.EncodeCursor(sort, investmentCursorValue(&last, sort), last.ID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return &investments, 0, nextCursor, nil
+}
+
+// investmentCursorValue is the keyset value of an investment for the sort key
+func investmentCursorValue(investment *models.Investment, sort string) interface{} {
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at":
+		return investment.CreatedAt
+	case "amount":
+		return investment.Amount
+	default:
+		return investment.ID
+	}
+}
+
+// investmentCursorTarget returns a value of the right type to decode a cursor into
+func investmentCursorTarget(sort string) interface{} {
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at":
+		return &time.Time{}
+	case "amount":
+		return new(float64)
+	default:
+		return new(uint)
+	}
+}
+
+func applyInvestmentFilter(sl *bun.SelectQuery, filter InvestmentRepositoryFilter) {
+	if filter.LoanID != nil {
+		sl.Where("? = ?", bun.Ident("investment.loan_id"), filter.LoanID)
+	}
+
+	if filter.InvestorID != nil {
+		sl.Where("? = ?", bun.Ident("investment.investor_id"), filter.InvestorID)
+	}
+
+	if filter.BorrowerID != nil || len(filter.LoanStatuses) > 0 {
+		sl.Join("JOIN loans AS investment_loan ON investment_loan.id = investment.loan_id")
+	}
+
+	if filter.BorrowerID != nil {
+		sl.Where("? = ?", bun.Ident("investment_loan.borrower_id"), filter.BorrowerID)
+	}
+
+	if len(filter.LoanStatuses) > 0 {
+		sl.Where("? IN (?)", bun.Ident("investment_loan.status"), bun.In(filter.LoanStatuses))
+	}
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type LoanProductRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter LoanProductRepositoryFilter) (*[]models.LoanProduct, int, error)
+	Save(ctx context.Context, product *models.LoanProduct) (*models.LoanProduct, error)
+	Detail(ctx context.Context, uuid string) (*models.LoanProduct, error)
+	DetailByCode(ctx context.Context, code string) (*models.LoanProduct, error)
+}
+
+type LoanProductRepositoryFilter struct {
+	Active *bool
+}
+
+var loanProductSortColumns = map[string]string{
+	"created_at": "loan_product.created_at",
+	"code":       "loan_product.code",
+	"min_amount": "loan_product.min_amount",
+	"max_amount": "loan_product.max_amount",
+}
+
+type loanProductRepository struct {
+	db *bun.DB
+}
+
+func NewLoanProductRepository(db *bun.DB) LoanProductRepositoryInterface {
+	return &loanProductRepository{
+		db: db,
+	}
+}
+
+func (r *loanProductRepository) Save(ctx context.Context, product *models.LoanProduct) (*models.LoanProduct, error) {
+	_, err := r.db.NewInsert().Model(product).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (r *loanProductRepository) Detail(ctx context.Context, uuid string) (*models.LoanProduct, error) {
+	var product models.LoanProduct
+	err := r.db.NewSelect().Model(&product).Where("uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (r *loanProductRepository) DetailByCode(ctx context.Context, code string) (*models.LoanProduct, error) {
+	var product models.LoanProduct
+	err := r.db.NewSelect().Model(&product).Where("code = ?", code).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (r *loanProductRepository) List(ctx context.Context, page int, perPage int, sort string, filter LoanProductRepositoryFilter) (*[]models.LoanProduct, int, error) {
+	sorts, err := utils.GenerateSort(sort, loanProductSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var products []models.LoanProduct
+	sl := r.db.NewSelect().Model(&products)
+	if filter.Active != nil {
+		sl.Where("? = ?", bun.Ident("active"), filter.Active)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(products) == 0 {
+		return &[]models.LoanProduct{}, count, nil
+	}
+
+	return &products, count, nil
+}
@@ -3,21 +3,58 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
+	"time"
 
-	"github.com/gotidy/ptr"
 	"github.com/peang/amartha-loan-service/models"
 	"github.com/peang/amartha-loan-service/utils"
 	"github.com/uptrace/bun"
 )
 
 type LoanRepositoryInterface interface {
-	List(ctx context.Context, page int, perPage int, sort string, filter LoanRepositoryFilter) (*[]models.Loan, int, error)
+	List(ctx context.Context, page int, perPage int, cursor *string, sort string, filter LoanRepositoryFilter) (*[]models.Loan, int, string, error)
 	Save(tx *bun.Tx, ctx context.Context, loan *models.Loan) (*models.Loan, error)
+	ClaimDisbursement(ctx context.Context, loan *models.Loan, payment *models.Payment) error
+	Release(ctx context.Context, loan *models.Loan, from []models.LoanStatus) error
+	SaveDelinquency(ctx context.Context, loan *models.Loan) error
 	Detail(ctx context.Context, id string) (loan *models.Loan, err error)
+	DetailByID(ctx context.Context, id uint) (loan *models.Loan, err error)
+	Count(ctx context.Context, filter LoanRepositoryFilter) (int, error)
+	ListStatusHistory(ctx context.Context, loanID uint) ([]models.LoanStatusChange, error)
 }
 
 type LoanRepositoryFilter struct {
-	Status *models.LoanStatus
+	IDs            []uint
+	BorrowerID     *uint
+	GroupID        *uint
+	FieldOfficerID *uint
+	Status         *models.LoanStatus
+	Statuses       []models.LoanStatus
+	Bucket         *string
+	ApprovedBefore *time.Time
+	AwaitingRole   *models.UserRole
+	MinAmount      *float64
+	MaxAmount      *float64
+	MinRate        *float64
+	MaxRate        *float64
+	MinRemaining   *float64
+	MaxRemaining   *float64
+	Tenor          *int
+	Sector         *string
+	Region         *string
+}
+
+const loanRemainingAmountExpr = "(loan.proposed_amount - COALESCE(loan.principal_amount, 0))"
+
+var loanSortColumns = map[string]string{
+	"created_at":       "loan.created_at",
+	"proposed_amount":  "loan.proposed_amount",
+	"rate":             "loan.rate",
+	"roi":              "loan.roi",
+	"tenor":            "loan.tenor",
+	"remaining_amount": loanRemainingAmountExpr,
+	"days_past_due":    "loan.days_past_due",
 }
 
 type loanRepository struct {
@@ -32,52 +69,150 @@ func NewLoanRepository(
 	disbursementRepository DisbursementRepositoryInterface,
 ) LoanRepositoryInterface {
 	return &loanRepository{
-		approvalRepository: approvalRepository,
-		db:                 db,
+		approvalRepository:     approvalRepository,
+		disbursementRepository: disbursementRepository,
+		db:                     db,
 	}
 }
 
+// Save writes the loan together with its new approval, disbursement and
+// status transitions. A caller running its own transaction passes it and
+// stays in charge of committing it, otherwise the loan is saved in a
+// transaction of its own.
 func (r *loanRepository) Save(tx *bun.Tx, ctx context.Context, loan *models.Loan) (*models.Loan, error) {
-	if tx == nil {
-		trx, _ := r.db.Begin()
-		tx = ptr.Of(trx)
+	if tx != nil {
+		err := r.save(ctx, tx, loan)
+		if err != nil {
+			return nil, err
+		}
+
+		return loan, nil
+	}
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return r.save(ctx, &tx, loan)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if loan.Status == models.LoanStatusApproved && loan.ApprovalID == nil {
+	return loan, nil
+}
+
+// ClaimDisbursement saves a loan moved to disbursing together with its
+// pending transfer. The loan row is locked and must still be invested, so of
+// concurrent requests only one ever gets to send the money.
+func (r *loanRepository) ClaimDisbursement(ctx context.Context, loan *models.Loan, payment *models.Payment) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var status models.LoanStatus
+		err := tx.NewSelect().Model((*models.Loan)(nil)).Column("status").Where("id = ?", loan.ID).For("UPDATE").Scan(ctx, &status)
+		if err != nil {
+			return err
+		}
+
+		if status != models.LoanStatusInvested {
+			return errors.New("only_invested_loan_allowed")
+		}
+
+		err = r.save(ctx, &tx, loan)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(payment).Returning("id").Exec(ctx)
+		return err
+	})
+}
+
+// Release saves a loan called off before disbursement and gives the investors
+// their reserved money back. The loan row is locked and must still be in one
+// of the from statuses, so a loan funded or disbursed meanwhile is refused and
+// the releases are never booked without the status change.
+func (r *loanRepository) Release(ctx context.Context, loan *models.Loan, from []models.LoanStatus) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var loanID uint
+		err := tx.NewSelect().Model((*models.Loan)(nil)).Column("id").
+			Where("id = ?", loan.ID).
+			Where("status IN (?)", bun.In(from)).
+			For("UPDATE").
+			Scan(ctx, &loanID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("loan_not_cancellable")
+			}
+
+			return err
+		}
+
+		var investments []models.Investment
+		err = tx.NewSelect().Model(&investments).Where("loan_id = ?", loan.ID).Order("id").Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, investment := range investments {
+			_, err = applyWalletTransaction(ctx, tx, investment.InvestorID, models.NewInvestmentTransaction(models.WalletTransactionRelease, &investment))
+			if err != nil {
+				return err
+			}
+		}
+
+		return r.save(ctx, &tx, loan)
+	})
+}
+
+// SaveDelinquency writes what a delinquency assessment changed on a loan still
+// being repaid. The update is conditional so a loan settled or written off
+// since it was read is never reopened.
+func (r *loanRepository) SaveDelinquency(ctx context.Context, loan *models.Loan) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().Model(loan).
+			Column("status", "days_past_due", "delinquency_bucket", "overdue_amount", "updated_at").
+			WherePK().
+			Where("status IN (?)", bun.In([]models.LoanStatus{models.LoanStatusDisbursed, models.LoanStatusDelinquent, models.LoanStatusDefaulted})).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return errors.New("loan_already_closed")
+		}
+
+		return saveLoanStatusChanges(ctx, tx, loan)
+	})
+}
+
+func (r *loanRepository) save(ctx context.Context, tx *bun.Tx, loan *models.Loan) error {
+	if loan.Approval != nil && loan.ApprovalID == nil {
 		approval := loan.Approval
 
-		_, err := r.approvalRepository.Save(ctx, approval)
+		_, err := r.approvalRepository.Save(tx, ctx, approval)
 		if err != nil {
-			tx.Rollback()
-			return nil, err
+			return err
 		}
 
 		loan.ApprovalID = &approval.ID
 	}
 
-	if loan.Status == models.LoanStatusDisbursed && loan.DisbursmentID == nil {
+	// A failed transfer leaves the previous attempt behind, each retry gets its own record
+	if loan.Disbursment != nil && loan.Disbursment.ID == 0 {
 		disbursement := loan.Disbursment
 
-		_, err := r.disbursementRepository.Save(ctx, disbursement)
+		_, err := r.disbursementRepository.Save(tx, ctx, disbursement)
 		if err != nil {
-			tx.Rollback()
-			return nil, err
+			return err
 		}
 
 		loan.DisbursmentID = &disbursement.ID
 	}
 
-	_, err := r.db.NewInsert().Model(loan).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	_, err := tx.NewInsert().Model(loan).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
 	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-
-	if tx != nil {
-		tx.Commit()
+		return err
 	}
 
-	return loan, nil
+	return saveLoanStatusChanges(ctx, tx, loan)
 }
 
 func (r *loanRepository) Detail(ctx context.Context, uuid string) (*models.Loan, error) {
@@ -94,24 +229,218 @@ func (r *loanRepository) Detail(ctx context.Context, uuid string) (*models.Loan,
 	return &loan, nil
 }
 
-func (r *loanRepository) List(ctx context.Context, page int, perPage int, sort string, filter LoanRepositoryFilter) (*[]models.Loan, int, error) {
-	sorts := utils.GenerateSort(sort)
+func (r *loanRepository) DetailByID(ctx context.Context, id uint) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.db.NewSelect().Model(&loan).Relation("Approval").Relation("Disbursment").Where("loan.id = ?", id).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &loan, nil
+}
+
+// List pages by offset and counts the matching loans, or, given a cursor,
+// even an empty one for the first page, pages by keyset on the sort column
+// and id and returns the cursor of the next page instead of a count.
+func (r *loanRepository) List(ctx context.Context, page int, perPage int, cursor *string, sort string, filter LoanRepositoryFilter) (*[]models.Loan, int, string, error) {
+	column, desc, err := utils.ParseSort(sort, loanSortColumns)
+	if err != nil {
+		return nil, 0, "", err
+	}
 	offset, limit := utils.GenerateOffsetLimit(page, perPage)
 
-	var loans []models.Loan
-	sl := r.db.NewSelect().Model(&loans)
-	if filter.Status != nil {
-		sl.Where("? = ?", bun.Ident("status"), filter.Status)
+	loans := []models.Loan{}
+	sl := r.db.NewSelect().Model(&loans).Relation("Borrower")
+	applyLoanFilter(sl, filter)
+	sl.OrderExpr(strings.Join(utils.KeysetOrder(column, "loan.id", desc), ", "))
+
+	if cursor == nil {
+		count, err := sl.Limit(limit).Offset(offset).ScanAndCount(ctx)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		return &loans, count, "", nil
 	}
 
-	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(context.TODO())
+	if *cursor != "" {
+		value := loanCursorTarget(sort)
+		id, err := utils.DecodeCursor(*cursor, sort, value)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		sl.Where(utils.KeysetCondition(column, "loan.id", desc), value, id)
+	}
+
+	// One extra row tells whether there is a next page without counting
+	err = sl.Limit(limit + 1).Scan(ctx)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	if len(loans) <= limit {
+		return &loans, 0, "", nil
+	}
+
+	loans = loans[:limit]
+	last := loans[limit-1]
+	nextCursor, err := utils.EncodeCursor(sort, loanCursorValue(&last, sort), last.ID)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
+	}
+
+	return &loans, 0, nextCursor, nil
+}
+
+// loanCursorValue is the keyset value of a loan for the sort key
+func loanCursorValue(loan *models.Loan, sort string) interface{} {
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at":
+		return loan.CreatedAt
+	case "proposed_amount":
+		return loan.ProposedAmount
+	case "rate":
+		return loan.Rate
+	case "roi":
+		return loan.ROI
+	case "tenor":
+		return loan.Tenor
+	case "days_past_due":
+		return loan.DaysPastDue
+	default:
+		return loan.RemainingAmount()
 	}
+}
+
+// loanCursorTarget returns a value of the right type to decode a cursor into
+func loanCursorTarget(sort string) interface{} {
+	if strings.TrimPrefix(sort, "-") == "created_at" {
+		return &time.Time{}
+	}
+
+	return new(float64)
+}
+
+func (r *loanRepository) Count(ctx context.Context, filter LoanRepositoryFilter) (int, error) {
+	sl := r.db.NewSelect().Model((*models.Loan)(nil))
+	if filter.Sector != nil || filter.Region != nil {
+		sl.Relation("Borrower")
+	}
+	applyLoanFilter(sl, filter)
+
+	return sl.Count(ctx)
+}
+
+// ListStatusHistory is the timeline of the loan, oldest transition first
+func (r *loanRepository) ListStatusHistory(ctx context.Context, loanID uint) ([]models.LoanStatusChange, error) {
+	history := []models.LoanStatusChange{}
+	err := r.db.NewSelect().Model(&history).Where("loan_id = ?", loanID).Order("created_at ASC", "id ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// saveLoanStatusChanges writes the transitions of a loan being saved, on
+// behalf of the actor of the request making them.
+func saveLoanStatusChanges(ctx context.Context, db bun.IDB, loan *models.Loan) error {
+	changes := loan.TakeStatusChanges()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	meta := utils.RequestMetaFrom(ctx)
+	for index := range changes {
+		changes[index].ActorID = meta.ActorID
+		changes[index].ActorRole = meta.ActorRole
+	}
+
+	_, err := db.NewInsert().Model(&changes).Exec(ctx)
+	return err
+}
 
-	if len(loans) == 0 {
-		return &[]models.Loan{}, count, nil
+func applyLoanFilter(sl *bun.SelectQuery, filter LoanRepositoryFilter) {
+	if filter.BorrowerID != nil {
+		sl.Where("? = ?", bun.Ident("loan.borrower_id"), filter.BorrowerID)
 	}
 
-	return &loans, count, nil
+	if len(filter.IDs) > 0 {
+		sl.Where("? IN (?)", bun.Ident("loan.id"), bun.In(filter.IDs))
+	}
+
+	// A field officer looks after the loans they disbursed and the loans of
+	// the groups they run
+	if filter.FieldOfficerID != nil {
+		sl.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("loan.disbursement_id IN (SELECT id FROM disbursements WHERE field_officer_id = ?)", filter.FieldOfficerID).
+				WhereOr("loan.group_loan_id IN (SELECT group_loans.id FROM group_loans JOIN borrower_groups ON borrower_groups.id = group_loans.group_id WHERE borrower_groups.field_officer_id = ?)", filter.FieldOfficerID)
+		})
+	}
+
+	if filter.GroupID != nil {
+		sl.Where("loan.group_loan_id IN (SELECT id FROM group_loans WHERE group_id = ?)", filter.GroupID)
+	}
+
+	if filter.Status != nil {
+		sl.Where("? = ?", bun.Ident("loan.status"), filter.Status)
+	}
+
+	if len(filter.Statuses) > 0 {
+		sl.Where("? IN (?)", bun.Ident("loan.status"), bun.In(filter.Statuses))
+	}
+
+	if filter.Bucket != nil {
+		sl.Where("? = ?", bun.Ident("loan.delinquency_bucket"), filter.Bucket)
+	}
+
+	if filter.ApprovedBefore != nil {
+		sl.Where("loan.approval_id IN (SELECT id FROM approvals WHERE approved_at < ?)", filter.ApprovedBefore)
+	}
+
+	if filter.AwaitingRole != nil {
+		sl.Where("loan.approval_id IN (SELECT a.id FROM approvals a WHERE ? = ANY(a.required_roles) AND NOT EXISTS (SELECT 1 FROM approval_sign_offs s WHERE s.approval_id = a.id AND s.role = ?))", filter.AwaitingRole, filter.AwaitingRole)
+	}
+
+	if filter.MinAmount != nil {
+		sl.Where("? >= ?", bun.Ident("loan.proposed_amount"), filter.MinAmount)
+	}
+
+	if filter.MaxAmount != nil {
+		sl.Where("? <= ?", bun.Ident("loan.proposed_amount"), filter.MaxAmount)
+	}
+
+	if filter.MinRate != nil {
+		sl.Where("? >= ?", bun.Ident("loan.rate"), filter.MinRate)
+	}
+
+	if filter.MaxRate != nil {
+		sl.Where("? <= ?", bun.Ident("loan.rate"), filter.MaxRate)
+	}
+
+	if filter.MinRemaining != nil {
+		sl.Where(loanRemainingAmountExpr+" >= ?", filter.MinRemaining)
+	}
+
+	if filter.MaxRemaining != nil {
+		sl.Where(loanRemainingAmountExpr+" <= ?", filter.MaxRemaining)
+	}
+
+	if filter.Tenor != nil {
+		sl.Where("? = ?", bun.Ident("loan.tenor"), filter.Tenor)
+	}
+
+	if filter.Sector != nil {
+		sl.Where("? = ?", bun.Ident("borrower.sector"), filter.Sector)
+	}
+
+	if filter.Region != nil {
+		sl.Where("? = ?", bun.Ident("borrower.region"), filter.Region)
+	}
 }
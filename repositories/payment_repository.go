@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type PaymentRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter PaymentRepositoryFilter) (*[]models.Payment, int, error)
+	Save(ctx context.Context, payment *models.Payment) (*models.Payment, error)
+	SetGatewayReference(ctx context.Context, payment *models.Payment) error
+	DetailByReference(ctx context.Context, reference string) (*models.Payment, error)
+	DetailByGatewayReference(ctx context.Context, gatewayReference string) (*models.Payment, error)
+}
+
+type PaymentRepositoryFilter struct {
+	LoanID    *uint
+	Direction *models.PaymentDirection
+	Status    *models.PaymentStatus
+}
+
+var paymentSortColumns = map[string]string{
+	"id":         "payment.id",
+	"created_at": "payment.created_at",
+	"amount":     "payment.amount",
+}
+
+type paymentRepository struct {
+	db *bun.DB
+}
+
+func NewPaymentRepository(db *bun.DB) PaymentRepositoryInterface {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+func (r *paymentRepository) Save(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
+	_, err := r.db.NewInsert().Model(payment).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// SetGatewayReference stores the gateway id of a transfer still pending. A
+// callback may have settled the payment already, it is then left as it is.
+func (r *paymentRepository) SetGatewayReference(ctx context.Context, payment *models.Payment) error {
+	now := time.Now()
+	payment.UpdatedAt = &now
+
+	_, err := r.db.NewUpdate().Model(payment).
+		Column("gateway_reference", "updated_at").
+		WherePK().
+		Where("status = ?", models.PaymentStatusPending).
+		Exec(ctx)
+	return err
+}
+
+func (r *paymentRepository) DetailByReference(ctx context.Context, reference string) (*models.Payment, error) {
+	var payment models.Payment
+	err := r.db.NewSelect().Model(&payment).Where("reference = ?", reference).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentRepository) DetailByGatewayReference(ctx context.Context, gatewayReference string) (*models.Payment, error) {
+	var payment models.Payment
+	err := r.db.NewSelect().Model(&payment).Where("gateway_reference = ?", gatewayReference).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentRepository) List(ctx context.Context, page int, perPage int, sort string, filter PaymentRepositoryFilter) (*[]models.Payment, int, error) {
+	sorts, err := utils.GenerateSort(sort, paymentSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var payments []models.Payment
+	sl := r.db.NewSelect().Model(&payments)
+	if filter.LoanID != nil {
+		sl.Where("? = ?", bun.Ident("loan_id"), filter.LoanID)
+	}
+
+	if filter.Direction != nil {
+		sl.Where("? = ?", bun.Ident("direction"), filter.Direction)
+	}
+
+	if filter.Status != nil {
+		sl.Where("? = ?", bun.Ident("status"), filter.Status)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(payments) == 0 {
+		return &[]models.Payment{}, count, nil
+	}
+
+	return &payments, count, nil
+}
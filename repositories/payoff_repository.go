@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type PayoffRepositoryInterface interface {
+	Claim(ctx context.Context, loan *models.Loan, payment *models.Payment) error
+	Save(ctx context.Context, loan *models.Loan, payment *models.Payment, installments []models.Installment, payouts []*models.WalletTransaction) error
+}
+
+type payoffRepository struct {
+	db *bun.DB
+}
+
+func NewPayoffRepository(db *bun.DB) PayoffRepositoryInterface {
+	return &payoffRepository{
+		db: db,
+	}
+}
+
+// Claim stores the pending payment a settlement is charged with. The loan row
+// is locked while it must still be repaying, and only one settlement payment
+// may be pending per loan, so concurrent settlements never charge twice.
+func (r *payoffRepository) Claim(ctx context.Context, loan *models.Loan, payment *models.Payment) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var loanID uint
+		err := tx.NewSelect().Model((*models.Loan)(nil)).Column("id").
+			Where("id = ?", loan.ID).
+			Where("status IN (?)", bun.In([]models.LoanStatus{models.LoanStatusDisbursed, models.LoanStatusDelinquent, models.LoanStatusDefaulted})).
+			For("UPDATE").
+			Scan(ctx, &loanID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("loan_already_closed")
+			}
+
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(payment).Returning("id").Exec(ctx)
+		if err != nil {
+			var pgErr pgdriver.Error
+			if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+				return errors.New("payoff_in_progress")
+			}
+
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Save closes a repaid loan, records the settlement payment and the
+// installments it paid when there is one, credits the investor payouts and
+// withdraws the loan positions still on sale, all or nothing. The loan update
+// is conditional so a loan is paid out only once.
+func (r *payoffRepository) Save(ctx context.Context, loan *models.Loan, payment *models.Payment, installments []models.Installment, payouts []*models.WalletTransaction) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().Model(loan).
+			Column("status", "early_settlement_fee", "days_past_due", "delinquency_bucket", "overdue_amount", "closed_at", "updated_at").
+			WherePK().
+			Where("status IN (?)", bun.In([]models.LoanStatus{models.LoanStatusDisbursed, models.LoanStatusDelinquent, models.LoanStatusDefaulted})).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return errors.New("loan_already_closed")
+		}
+
+		err = saveLoanStatusChanges(ctx, tx, loan)
+		if err != nil {
+			return err
+		}
+
+		if payment != nil {
+			_, err = tx.NewInsert().Model(payment).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(installments) > 0 {
+			_, err = tx.NewInsert().Model(&installments).On("CONFLICT (id) DO UPDATE").Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, payout := range payouts {
+			// A position may have changed hands since the payouts were split
+			investment := models.Investment{}
+			err = tx.NewSelect().Model(&investment).Column("investor_id").Where("id = ?", *payout.InvestmentID).Scan(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = applyWalletTransaction(ctx, tx, investment.InvestorID, payout)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NewUpdate().Model((*models.InvestmentListing)(nil)).
+			Set("status = ?", models.InvestmentListingCancelled).
+			Set("updated_at = ?", *loan.ClosedAt).
+			Where("status = ?", models.InvestmentListingOpen).
+			Where("investment_id IN (SELECT id FROM investments WHERE loan_id = ?)", loan.ID).
+			Exec(ctx)
+		return err
+	})
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type RateCardRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter RateCardRepositoryFilter) (*[]models.RateCard, int, error)
+	Save(ctx context.Context, rateCard *models.RateCard) (*models.RateCard, error)
+	SaveVersion(ctx context.Context, previous *models.RateCard, next *models.RateCard) (*models.RateCard, error)
+	Detail(ctx context.Context, uuid string) (*models.RateCard, error)
+	FindActive(ctx context.Context, product string, riskGrade string, tenor int) (*models.RateCard, error)
+}
+
+type RateCardRepositoryFilter struct {
+	Product   *string
+	RiskGrade *string
+	Active    *bool
+}
+
+var rateCardSortColumns = map[string]string{
+	"created_at": "rate_card.created_at",
+	"product":    "rate_card.product",
+	"risk_grade": "rate_card.risk_grade",
+	"rate":       "rate_card.rate",
+	"version":    "rate_card.version",
+}
+
+type rateCardRepository struct {
+	db *bun.DB
+}
+
+func NewRateCardRepository(db *bun.DB) RateCardRepositoryInterface {
+	return &rateCardRepository{
+		db: db,
+	}
+}
+
+func (r *rateCardRepository) Save(ctx context.Context, rateCard *models.RateCard) (*models.RateCard, error) {
+	_, err := r.db.NewInsert().Model(rateCard).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rateCard, nil
+}
+
+// SaveVersion retires the previous card and stores its successor atomically,
+// so there is never a moment with zero or two active versions.
+func (r *rateCardRepository) SaveVersion(ctx context.Context, previous *models.RateCard, next *models.RateCard) (*models.RateCard, error) {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model(previous).Column("active", "updated_at").WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(next).Returning("id").Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+func (r *rateCardRepository) Detail(ctx context.Context, uuid string) (*models.RateCard, error) {
+	var rateCard models.RateCard
+	err := r.db.NewSelect().Model(&rateCard).Where("uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &rateCard, nil
+}
+
+func (r *rateCardRepository) FindActive(ctx context.Context, product string, riskGrade string, tenor int) (*models.RateCard, error) {
+	var rateCard models.RateCard
+	err := r.db.NewSelect().Model(&rateCard).
+		Where("? = ?", bun.Ident("product"), product).
+		Where("? = ?", bun.Ident("risk_grade"), riskGrade).
+		Where("? <= ?", bun.Ident("min_tenor"), tenor).
+		Where("? >= ?", bun.Ident("max_tenor"), tenor).
+		Where("? = TRUE", bun.Ident("active")).
+		OrderExpr("version DESC, id DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &rateCard, nil
+}
+
+func (r *rateCardRepository) List(ctx context.Context, page int, perPage int, sort string, filter RateCardRepositoryFilter) (*[]models.RateCard, int, error) {
+	sorts, err := utils.GenerateSort(sort, rateCardSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var rateCards []models.RateCard
+	sl := r.db.NewSelect().Model(&rateCards)
+	if filter.Product != nil {
+		sl.Where("? = ?", bun.Ident("product"), filter.Product)
+	}
+
+	if filter.RiskGrade != nil {
+		sl.Where("? = ?", bun.Ident("risk_grade"), filter.RiskGrade)
+	}
+
+	if filter.Active != nil {
+		sl.Where("? = ?", bun.Ident("active"), filter.Active)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(rateCards) == 0 {
+		return &[]models.RateCard{}, count, nil
+	}
+
+	return &rateCards, count, nil
+}
@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type ReportRepositoryInterface interface {
+	LoansByStatus(ctx context.Context, filter ReportRepositoryFilter) ([]models.StatusReport, error)
+	Disbursements(ctx context.Context, period string, filter ReportRepositoryFilter) ([]models.DisbursementReport, error)
+	TimeToFund(ctx context.Context, filter ReportRepositoryFilter) (*models.TimeToFundReport, error)
+	Funding(ctx context.Context, filter ReportRepositoryFilter) (*models.FundingReport, error)
+	PortfolioAtRisk(ctx context.Context, filter ReportRepositoryFilter) (*models.PortfolioAtRiskReport, error)
+	InvestorReturns(ctx context.Context, page int, perPage int, sort string, filter ReportRepositoryFilter) ([]models.InvestorReturnReport, int, error)
+	Branches(ctx context.Context, filter ReportRepositoryFilter) ([]models.BranchReport, error)
+}
+
+// ReportRepositoryFilter bounds every report by a date range, each report
+// applies it to the date that matters for it, and by the borrower region.
+type ReportRepositoryFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Region *string
+}
+
+var investorReturnSortColumns = map[string]string{
+	"investor_id":     "investment.investor_id",
+	"investments":     "investments",
+	"invested_amount": "invested_amount",
+	"payouts":         "payouts",
+	"losses":          "losses",
+}
+
+// outstandingPrincipalExpr is the principal still owed on the current
+// schedule of a loan, payments go to late fees and interest first.
+const outstandingPrincipalExpr = `(SELECT COALESCE(SUM(GREATEST(0, installment.principal_amount - LEAST(installment.principal_amount, GREATEST(0, installment.paid_amount - installment.late_fee - installment.interest_amount)))), 0)
+	FROM installments AS installment
+	WHERE installment.loan_id = loan.id AND installment.superseded_at IS NULL)`
+
+type reportRepository struct {
+	db *bun.DB
+}
+
+func NewReportRepository(db *bun.DB) ReportRepositoryInterface {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+func (r *reportRepository) LoansByStatus(ctx context.Context, filter ReportRepositoryFilter) ([]models.StatusReport, error) {
+	reports := []models.StatusReport{}
+	sl := r.db.NewSelect().Model((*models.Loan)(nil)).
+		ColumnExpr("loan.status").
+		ColumnExpr("COUNT(*) AS loans").
+		ColumnExpr("COALESCE(SUM(loan.proposed_amount), 0) AS proposed_amount").
+		ColumnExpr("COALESCE(SUM(loan.principal_amount), 0) AS invested_amount").
+		GroupExpr("loan.status").
+		OrderExpr("loan.status")
+	applyReportFilter(sl, "loan.created_at", filter)
+
+	err := sl.Scan(ctx, &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (r *reportRepository) Disbursements(ctx context.Context, period string, filter ReportRepositoryFilter) ([]models.DisbursementReport, error) {
+	reports := []models.DisbursementReport{}
+	sl := r.db.NewSelect().Model((*models.Loan)(nil)).
+		Join("JOIN disbursements AS disbursement ON disbursement.id = loan.disbursement_id").
+		ColumnExpr("date_trunc(?, disbursement.created_at) AS period", period).
+		ColumnExpr("COUNT(*) AS loans").
+		ColumnExpr("COALESCE(SUM(loan.principal_amount), 0) AS amount").
+		Where("loan.status IN (?)", bun.In(models.DisbursedLoanStatuses)).
+		GroupExpr("period").
+		OrderExpr("period")
+	applyReportFilter(sl, "disbursement.created_at", filter)
+
+	err := sl.Scan(ctx, &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (r *reportRepository) TimeToFund(ctx context.Context, filter ReportRepositoryFilter) (*models.TimeToFundReport, error) {
+	funding := r.db.NewSelect().Model((*models.Loan)(nil)).
+		Join("JOIN approvals AS approval ON approval.id = loan.approval_id").
+		ColumnExpr("approval.approved_at").
+		ColumnExpr("(SELECT MAX(investment.created_at) FROM investments AS investment WHERE investment.loan_id = loan.id) AS funded_at").
+		Where("loan.status IN (?)", bun.In(models.FundedLoanStatuses)).
+		Where("approval.approved_at IS NOT NULL")
+	applyReportFilter(funding, "approval.approved_at", filter)
+
+	var report models.TimeToFundReport
+	err := r.db.NewSelect().TableExpr("(?) AS funding", funding).
+		ColumnExpr("COUNT(*) AS loans").
+		ColumnExpr("COALESCE(AVG(EXTRACT(EPOCH FROM funding.funded_at - funding.approved_at)), 0) AS average_seconds").
+		ColumnExpr("COALESCE(MIN(EXTRACT(EPOCH FROM funding.funded_at - funding.approved_at)), 0) AS min_seconds").
+		ColumnExpr("COALESCE(MAX(EXTRACT(EPOCH FROM funding.funded_at - funding.approved_at)), 0) AS max_seconds").
+		Where("funding.funded_at IS NOT NULL").
+		Scan(ctx, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (r *reportRepository) Funding(ctx context.Context, filter ReportRepositoryFilter) (*models.FundingReport, error) {
+	var report models.FundingReport
+	sl := r.db.NewSelect().Model((*models.Loan)(nil)).
+		Join("JOIN approvals AS approval ON approval.id = loan.approval_id").
+		ColumnExpr("COUNT(*) AS loans").
+		ColumnExpr("COUNT(*) FILTER (WHERE loan.status IN (?)) AS funded_loans", bun.In(models.FundedLoanStatuses)).
+		ColumnExpr("COALESCE(SUM(loan.proposed_amount), 0) AS proposed_amount").
+		ColumnExpr("COALESCE(SUM(loan.principal_amount), 0) AS invested_amount").
+		Where("approval.approved_at IS NOT NULL")
+	applyReportFilter(sl, "approval.approved_at", filter)
+
+	err := sl.Scan(ctx, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// PortfolioAtRisk looks at the loans still being repaid today, the date range
+// picks them by disbursement date.
+func (r *reportRepository) PortfolioAtRisk(ctx context.Context, filter ReportRepositoryFilter) (*models.PortfolioAtRiskReport, error) {
+	var report models.PortfolioAtRiskReport
+	sl := r.db.NewSelect().Model((*models.Loan)(nil)).
+		Join("JOIN disbursements AS disbursement ON disbursement.id = loan.disbursement_id").
+		ColumnExpr("COUNT(*) AS loans").
+		ColumnExpr("COALESCE(SUM("+outstandingPrincipalExpr+"), 0) AS outstanding").
+		ColumnExpr("COUNT(*) FILTER (WHERE loan.days_past_due > 30) AS par30_loans").
+		ColumnExpr("COALESCE(SUM("+outstandingPrincipalExpr+") FILTER (WHERE loan.days_past_due > 30), 0) AS par30_amount").
+		ColumnExpr("COUNT(*) FILTER (WHERE loan.days_past_due > 90) AS par90_loans").
+		ColumnExpr("COALESCE(SUM("+outstandingPrincipalExpr+") FILTER (WHERE loan.days_past_due > 90), 0) AS par90_amount").
+		Where("loan.status IN (?)", bun.In(models.RepayingLoanStatuses))
+	applyReportFilter(sl, "disbursement.created_at", filter)
+
+	err := sl.Scan(ctx, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (r *reportRepository) InvestorReturns(ctx context.Context, page int, perPage int, sort string, filter ReportRepositoryFilter) ([]models.InvestorReturnReport, int, error) {
+	sorts, err := utils.GenerateSort(sort, investorReturnSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	reports := []models.InvestorReturnReport{}
+	sl := r.db.NewSelect().Model((*models.Investment)(nil)).
+		Join("JOIN users AS investor ON investor.id = investment.investor_id").
+		Join("JOIN loans AS loan ON loan.id = investment.loan_id").
+		ColumnExpr("investment.investor_id").
+		ColumnExpr("investor.name AS investor_name").
+		ColumnExpr("COUNT(*) AS investments").
+		ColumnExpr("COALESCE(SUM(investment.amount), 0) AS invested_amount").
+		ColumnExpr("COALESCE(SUM(investment.amount) FILTER (WHERE loan.status = ?), 0) AS repaid_principal", models.LoanStatusRepaid).
+		ColumnExpr(`COALESCE(SUM((SELECT SUM(wallet_transaction.amount) FROM wallet_transactions AS wallet_transaction
+			WHERE wallet_transaction.investment_id = investment.id AND wallet_transaction.type = ? AND wallet_transaction.status = ?)), 0) AS payouts`,
+			models.WalletTransactionPayout, models.WalletTransactionSuccess).
+		ColumnExpr(`COALESCE(SUM((SELECT SUM(investment_loss.amount) FROM investment_losses AS investment_loss
+			WHERE investment_loss.investment_id = investment.id)), 0) AS losses`).
+		GroupExpr("investment.investor_id, investor.name")
+	applyReportFilter(sl, "investment.created_at", filter)
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx, &reports)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reports, count, nil
+}
+
+func (r *reportRepository) Branches(ctx context.Context, filter ReportRepositoryFilter) ([]models.BranchReport, error) {
+	reports := []models.BranchReport{}
+	sl := r.db.NewSelect().Model((*models.Loan)(nil)).
+		Join("JOIN users AS borrower ON borrower.id = loan.borrower_id").
+		ColumnExpr("borrower.region").
+		ColumnExpr("COUNT(*) AS loans").
+		ColumnExpr("COUNT(*) FILTER (WHERE loan.status IN (?)) AS disbursed_loans", bun.In(models.DisbursedLoanStatuses)).
+		ColumnExpr("COALESCE(SUM(loan.principal_amount) FILTER (WHERE loan.status IN (?)), 0) AS disbursed_amount", bun.In(models.DisbursedLoanStatuses)).
+		ColumnExpr("COALESCE(SUM("+outstandingPrincipalExpr+") FILTER (WHERE loan.status IN (?)), 0) AS outstanding", bun.In(models.RepayingLoanStatuses)).
+		ColumnExpr("COALESCE(SUM("+outstandingPrincipalExpr+") FILTER (WHERE loan.status IN (?) AND loan.days_past_due > 30), 0) AS par30_amount", bun.In(models.RepayingLoanStatuses)).
+		ColumnExpr(`COALESCE(SUM((SELECT SUM(write_off.outstanding_principal) FROM write_offs AS write_off
+			WHERE write_off.loan_id = loan.id)), 0) AS written_off_amount`).
+		ColumnExpr("COUNT(*) FILTER (WHERE loan.status = ?) AS repaid_loans", models.LoanStatusRepaid).
+		GroupExpr("borrower.region").
+		OrderExpr("borrower.region")
+	applyReportFilter(sl, "loan.created_at", filter)
+
+	err := sl.Scan(ctx, &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// applyReportFilter expects the query to select from loans, or to join them
+// as loan.
+func applyReportFilter(sl *bun.SelectQuery, dateColumn string, filter ReportRepositoryFilter) {
+	if filter.From != nil {
+		sl.Where("? >= ?", bun.Ident(dateColumn), filter.From)
+	}
+
+	if filter.To != nil {
+		sl.Where("? < ?", bun.Ident(dateColumn), filter.To)
+	}
+
+	if filter.Region != nil {
+		sl.Where("loan.borrower_id IN (SELECT id FROM users WHERE region = ?)", filter.Region)
+	}
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type RestructuringRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter RestructuringRepositoryFilter) (*[]models.Restructuring, int, error)
+	Save(ctx context.Context, restructuring *models.Restructuring) (*models.Restructuring, error)
+	Detail(ctx context.Context, uuid string) (*models.Restructuring, error)
+	SaveDecision(ctx context.Context, restructuring *models.Restructuring, installments []models.Installment) error
+}
+
+type RestructuringRepositoryFilter struct {
+	LoanID *uint
+	Status *models.RestructuringStatus
+}
+
+var restructuringSortColumns = map[string]string{
+	"id":         "restructuring.id",
+	"created_at": "restructuring.created_at",
+}
+
+type restructuringRepository struct {
+	db *bun.DB
+}
+
+func NewRestructuringRepository(db *bun.DB) RestructuringRepositoryInterface {
+	return &restructuringRepository{
+		db: db,
+	}
+}
+
+func (r *restructuringRepository) Save(ctx context.Context, restructuring *models.Restructuring) (*models.Restructuring, error) {
+	_, err := r.db.NewInsert().Model(restructuring).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		// A loan has at most one request waiting for a supervisor
+		var pgErr pgdriver.Error
+		if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+			return nil, errors.New("restructuring_already_requested")
+		}
+
+		return nil, err
+	}
+
+	return restructuring, nil
+}
+
+func (r *restructuringRepository) Detail(ctx context.Context, uuid string) (*models.Restructuring, error) {
+	var restructuring models.Restructuring
+	err := r.db.NewSelect().Model(&restructuring).Relation("Loan").Where("restructuring.uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &restructuring, nil
+}
+
+func (r *restructuringRepository) List(ctx context.Context, page int, perPage int, sort string, filter RestructuringRepositoryFilter) (*[]models.Restructuring, int, error) {
+	sorts, err := utils.GenerateSort(sort, restructuringSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var restructurings []models.Restructuring
+	sl := r.db.NewSelect().Model(&restructurings).Relation("Loan")
+	if filter.LoanID != nil {
+		sl.Where("? = ?", bun.Ident("restructuring.loan_id"), filter.LoanID)
+	}
+
+	if filter.Status != nil {
+		sl.Where("? = ?", bun.Ident("restructuring.status"), filter.Status)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(restructurings) == 0 {
+		return &[]models.Restructuring{}, count, nil
+	}
+
+	return &restructurings, count, nil
+}
+
+// SaveDecision stores the supervisor decision together with the new schedule
+// and the loan. The request is only updated while still pending, so two
+// supervisors deciding at once cannot both reschedule the loan.
+func (r *restructuringRepository) SaveDecision(ctx context.Context, restructuring *models.Restructuring, installments []models.Installment) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().Model(restructuring).
+			Column("status", "decided_by", "decision_note", "decided_at", "updated_at").
+			WherePK().
+			Where("status = ?", models.RestructuringRequested).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return errors.New("restructuring_already_decided")
+		}
+
+		if restructuring.Status != models.RestructuringApproved {
+			return nil
+		}
+
+		if len(installments) > 0 {
+			_, err = tx.NewInsert().Model(&installments).On("CONFLICT (id) DO UPDATE").Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NewUpdate().Model(restructuring.Loan).
+			Column("tenor", "status", "days_past_due", "delinquency_bucket", "overdue_amount", "updated_at").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return saveLoanStatusChanges(ctx, tx, restructuring.Loan)
+	})
+}
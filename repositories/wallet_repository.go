@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type WalletRepositoryInterface interface {
+	FindOrCreate(ctx context.Context, userID uint) (*models.Wallet, error)
+	Apply(ctx context.Context, userID uint, transaction *models.WalletTransaction) (*models.Wallet, error)
+	ListTransactions(ctx context.Context, page int, perPage int, sort string, filter WalletTransactionRepositoryFilter) (*[]models.WalletTransaction, int, error)
+	DetailTransactionByReference(ctx context.Context, transactionType models.WalletTransactionType, reference string) (*models.WalletTransaction, error)
+	Refund(ctx context.Context, withdrawal *models.WalletTransaction, reason string) (*models.WalletTransaction, error)
+}
+
+type WalletTransactionRepositoryFilter struct {
+	WalletID *uint
+	Type     *models.WalletTransactionType
+	Status   *models.WalletTransactionStatus
+}
+
+var walletTransactionSortColumns = map[string]string{
+	"id":         "wallet_transaction.id",
+	"created_at": "wallet_transaction.created_at",
+	"amount":     "wallet_transaction.amount",
+}
+
+type walletRepository struct {
+	db *bun.DB
+}
+
+func NewWalletRepository(db *bun.DB) WalletRepositoryInterface {
+	return &walletRepository{
+		db: db,
+	}
+}
+
+func (r *walletRepository) FindOrCreate(ctx context.Context, userID uint) (*models.Wallet, error) {
+	return findOrCreateWallet(ctx, r.db, userID)
+}
+
+// Apply books the transaction and moves the wallet balances in the same
+// database transaction. The balance update is conditional so two concurrent
+// requests can never spend the same money, and a release or settlement booked
+// twice for one investment is refused by the unique index.
+func (r *walletRepository) Apply(ctx context.Context, userID uint, transaction *models.WalletTransaction) (*models.Wallet, error) {
+	var wallet *models.Wallet
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		wallet, err = applyWalletTransaction(ctx, tx, userID, transaction)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+func (r *walletRepository) ListTransactions(ctx context.Context, page int, perPage int, sort string, filter WalletTransactionRepositoryFilter) (*[]models.WalletTransaction, int, error) {
+	sorts, err := utils.GenerateSort(sort, walletTransactionSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var transactions []models.WalletTransaction
+	sl := r.db.NewSelect().Model(&transactions)
+	if filter.WalletID != nil {
+		sl.Where("? = ?", bun.Ident("wallet_id"), filter.WalletID)
+	}
+
+	if filter.Type != nil {
+		sl.Where("? = ?", bun.Ident("type"), filter.Type)
+	}
+
+	if filter.Status != nil {
+		sl.Where("? = ?", bun.Ident("status"), filter.Status)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(transactions) == 0 {
+		return &[]models.WalletTransaction{}, count, nil
+	}
+
+	return &transactions, count, nil
+}
+
+func (r *walletRepository) DetailTransactionByReference(ctx context.Context, transactionType models.WalletTransactionType, reference string) (*models.WalletTransaction, error) {
+	var transaction models.WalletTransaction
+	err := r.db.NewSelect().Model(&transaction).Where("type = ?", transactionType).Where("reference = ?", reference).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// Refund credits a failed withdrawal back to its wallet. The refund shares the
+// withdrawal reference and the unique index refuses a second one.
+func (r *walletRepository) Refund(ctx context.Context, withdrawal *models.WalletTransaction, reason string) (*models.WalletTransaction, error) {
+	refund := models.NewRefundTransaction(withdrawal, reason)
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var wallet models.Wallet
+		err := tx.NewSelect().Model(&wallet).Where("id = ?", withdrawal.WalletID).Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = applyWalletTransaction(ctx, tx, wallet.UserID, refund)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func findOrCreateWallet(ctx context.Context, db bun.IDB, userID uint) (*models.Wallet, error) {
+	_, err := db.NewInsert().Model(models.NewWallet(userID)).On("CONFLICT (user_id) DO NOTHING").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet models.Wallet
+	err = db.NewSelect().Model(&wallet).Where("user_id = ?", userID).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+// lockWallet holds the user's wallet row until the transaction ends, so
+// writes depending on what the user already holds run one at a time.
+func lockWallet(ctx context.Context, tx bun.Tx, userID uint) error {
+	wallet, err := findOrCreateWallet(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.NewSelect().Model(wallet).WherePK().For("UPDATE").Scan(ctx)
+}
+
+// applyWalletTransaction is Apply for callers that already run a database
+// transaction and need the wallet to move together with their own writes.
+func applyWalletTransaction(ctx context.Context, tx bun.Tx, userID uint, transaction *models.WalletTransaction) (*models.Wallet, error) {
+	wallet, err := findOrCreateWallet(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, reserved := transaction.Deltas()
+	err = tx.NewUpdate().Model(wallet).
+		Set("balance = balance + ?", balance).
+		Set("reserved_balance = reserved_balance + ?", reserved).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", wallet.ID).
+		Where("balance + ? >= reserved_balance + ?", balance, reserved).
+		Where("reserved_balance + ? >= 0", reserved).
+		Returning("*").
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("insufficient_balance")
+		}
+
+		return nil, err
+	}
+
+	transaction.WalletID = wallet.ID
+	transaction.BalanceAfter = wallet.Balance
+	transaction.ReservedBalanceAfter = wallet.ReservedBalance
+
+	_, err = tx.NewInsert().Model(transaction).Returning("id").Exec(ctx)
+	if err != nil {
+		var pgErr pgdriver.Error
+		if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+			return nil, errors.New("duplicate_wallet_transaction")
+		}
+
+		return nil, err
+	}
+
+	return wallet, nil
+}
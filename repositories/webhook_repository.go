@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/utils"
+	"github.com/uptrace/bun"
+)
+
+type WebhookRepositoryInterface interface {
+	List(ctx context.Context, page int, perPage int, sort string, filter WebhookRepositoryFilter) (*[]models.WebhookSubscription, int, error)
+	Save(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Detail(ctx context.Context, uuid string) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, subscription *models.WebhookSubscription) error
+
+	ListDeliveries(ctx context.Context, page int, perPage int, sort string, filter WebhookDeliveryRepositoryFilter) (*[]models.WebhookDelivery, int, error)
+	SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	DetailDelivery(ctx context.Context, uuid string) (*models.WebhookDelivery, error)
+	LastDeliveryAttempt(ctx context.Context, subscriptionID uint, eventID uuid.UUID) (int, error)
+}
+
+type WebhookRepositoryFilter struct {
+	Active    *bool
+	EventType *string
+}
+
+type WebhookDeliveryRepositoryFilter struct {
+	SubscriptionID *uint
+	EventID        *string
+	Success        *bool
+}
+
+var webhookSortColumns = map[string]string{
+	"id":         "webhook_subscription.id",
+	"created_at": "webhook_subscription.created_at",
+}
+
+var webhookDeliverySortColumns = map[string]string{
+	"id":         "webhook_delivery.id",
+	"created_at": "webhook_delivery.created_at",
+}
+
+type webhookRepository struct {
+	db *bun.DB
+}
+
+func NewWebhookRepository(db *bun.DB) WebhookRepositoryInterface {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) Save(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	_, err := r.db.NewInsert().Model(subscription).On("CONFLICT (id) DO UPDATE").Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookRepository) Detail(ctx context.Context, uuid string) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.db.NewSelect().Model(&subscription).Where("uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, subscription *models.WebhookSubscription) error {
+	_, err := r.db.NewDelete().Model(subscription).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *webhookRepository) List(ctx context.Context, page int, perPage int, sort string, filter WebhookRepositoryFilter) (*[]models.WebhookSubscription, int, error) {
+	sorts, err := utils.GenerateSort(sort, webhookSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var subscriptions []models.WebhookSubscription
+	sl := r.db.NewSelect().Model(&subscriptions)
+	if filter.Active != nil {
+		sl.Where("? = ?", bun.Ident("active"), filter.Active)
+	}
+
+	if filter.EventType != nil {
+		sl.Where("? = ANY(?)", filter.EventType, bun.Ident("event_types"))
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(subscriptions) == 0 {
+		return &[]models.WebhookSubscription{}, count, nil
+	}
+
+	return &subscriptions, count, nil
+}
+
+func (r *webhookRepository) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	_, err := r.db.NewInsert().Model(delivery).Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// LastDeliveryAttempt is the highest attempt made to deliver the event to the
+// subscription, zero when none was made.
+func (r *webhookRepository) LastDeliveryAttempt(ctx context.Context, subscriptionID uint, eventID uuid.UUID) (int, error) {
+	var attempt int
+	err := r.db.NewSelect().Model((*models.WebhookDelivery)(nil)).
+		ColumnExpr("COALESCE(MAX(webhook_delivery.attempt), 0)").
+		Where("webhook_delivery.subscription_id = ?", subscriptionID).
+		Where("webhook_delivery.event_id = ?", eventID).
+		Scan(ctx, &attempt)
+	if err != nil {
+		return 0, err
+	}
+
+	return attempt, nil
+}
+
+func (r *webhookRepository) DetailDelivery(ctx context.Context, uuid string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.NewSelect().Model(&delivery).Relation("Subscription").Where("webhook_delivery.uuid = ?", uuid).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, page int, perPage int, sort string, filter WebhookDeliveryRepositoryFilter) (*[]models.WebhookDelivery, int, error) {
+	sorts, err := utils.GenerateSort(sort, webhookDeliverySortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := utils.GenerateOffsetLimit(page, perPage)
+
+	var deliveries []models.WebhookDelivery
+	sl := r.db.NewSelect().Model(&deliveries)
+	if filter.SubscriptionID != nil {
+		sl.Where("? = ?", bun.Ident("subscription_id"), filter.SubscriptionID)
+	}
+
+	if filter.EventID != nil {
+		sl.Where("? = ?", bun.Ident("event_id"), filter.EventID)
+	}
+
+	if filter.Success != nil {
+		sl.Where("? = ?", bun.Ident("success"), filter.Success)
+	}
+
+	count, err := sl.Limit(limit).Offset(offset).OrderExpr(sorts).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(deliveries) == 0 {
+		return &[]models.WebhookDelivery{}, count, nil
+	}
+
+	return &deliveries, count, nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/uptrace/bun"
+)
+
+type WriteOffRepositoryInterface interface {
+	Save(ctx context.Context, loan *models.Loan, supervisorID uint, reason string) (*models.WriteOff, error)
+	DetailByLoan(ctx context.Context, loanID uint) (*models.WriteOff, error)
+}
+
+type writeOffRepository struct {
+	db *bun.DB
+}
+
+func NewWriteOffRepository(db *bun.DB) WriteOffRepositoryInterface {
+	return &writeOffRepository{
+		db: db,
+	}
+}
+
+// Save closes the loan, records the write-off and the loss of every
+// investment, and withdraws the loan positions still on sale, all or nothing.
+// The loan row is locked first and the amounts are taken from the schedule and
+// investments read under that lock, so a repayment or a position changing
+// hands meanwhile is not lost, and a loan is written off only once.
+func (r *writeOffRepository) Save(ctx context.Context, loan *models.Loan, supervisorID uint, reason string) (*models.WriteOff, error) {
+	var writeOff *models.WriteOff
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var loanID uint
+		err := tx.NewSelect().Model((*models.Loan)(nil)).Column("id").
+			Where("id = ?", loan.ID).
+			Where("status IN (?)", bun.In([]models.LoanStatus{models.LoanStatusDelinquent, models.LoanStatusDefaulted})).
+			For("UPDATE").
+			Scan(ctx, &loanID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("only_delinquent_loan_allowed")
+			}
+
+			return err
+		}
+
+		installments := []models.Installment{}
+		err = tx.NewSelect().Model(&installments).
+			Where("loan_id = ?", loan.ID).
+			Where("superseded_at IS NULL").
+			Order("sequence ASC").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		investments := []models.Investment{}
+		err = tx.NewSelect().Model(&investments).Where("loan_id = ?", loan.ID).Order("id ASC").Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		writeOff = models.NewWriteOff(loan, supervisorID, reason, installments, investments)
+
+		result, err := tx.NewUpdate().Model(loan).
+			Column("status", "rejection_reason", "closed_at", "updated_at").
+			WherePK().
+			Where("status IN (?)", bun.In([]models.LoanStatus{models.LoanStatusDelinquent, models.LoanStatusDefaulted})).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return errors.New("only_delinquent_loan_allowed")
+		}
+
+		err = saveLoanStatusChanges(ctx, tx, loan)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().Model(writeOff).Returning("id").Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(writeOff.Losses) > 0 {
+			for index := range writeOff.Losses {
+				writeOff.Losses[index].WriteOffID = writeOff.ID
+			}
+
+			_, err = tx.NewInsert().Model(&writeOff.Losses).Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NewUpdate().Model((*models.InvestmentListing)(nil)).
+			Set("status = ?", models.InvestmentListingCancelled).
+			Set("updated_at = ?", writeOff.CreatedAt).
+			Where("status = ?", models.InvestmentListingOpen).
+			Where("investment_id IN (SELECT id FROM investments WHERE loan_id = ?)", loan.ID).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return writeOff, nil
+}
+
+func (r *writeOffRepository) DetailByLoan(ctx context.Context, loanID uint) (*models.WriteOff, error) {
+	var writeOff models.WriteOff
+	err := r.db.NewSelect().Model(&writeOff).Relation("Losses").Where("write_off.loan_id = ?", loanID).Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &writeOff, nil
+}
@@ -1,32 +1,26 @@
 package services
 
 import (
-	"fmt"
-	"io/ioutil"
+	"bytes"
 
 	"github.com/jung-kurt/gofpdf"
 )
 
-func GenerateAgreementPDF(loanId string) (*string, error) {
+// GenerateAgreementPDF renders the agreement in memory, the caller decides where it is stored
+func GenerateAgreementPDF(loanId string) (*bytes.Reader, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 
-	filepath := fmt.Sprintf("file_uploads/loan_%s.pdf", loanId)
-
 	pdf.SetTitle("Loan Aggreement", true)
+	pdf.SetSubject("Loan "+loanId, true)
 	pdf.AddPage()
 	pdf.SetFont("Arial", "B", 16)
 	pdf.Cell(40, 10, "Hello, World!")
 
-	tmpFile, err := ioutil.TempFile("", "*.pdf")
-	if err != nil {
-		return nil, err
-	}
-	defer tmpFile.Close()
-
-	err = pdf.Output(tmpFile)
+	var buffer bytes.Buffer
+	err := pdf.Output(&buffer)
 	if err != nil {
 		return nil, err
 	}
 
-	return &filepath, nil
+	return bytes.NewReader(buffer.Bytes()), nil
 }
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type AuditServiceInterface interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+}
+
+type auditService struct {
+	auditLogRepository repositories.AuditLogRepositoryInterface
+}
+
+func NewAuditService(auditLogRepository repositories.AuditLogRepositoryInterface) AuditServiceInterface {
+	return &auditService{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+// Record appends the change to the audit log on behalf of the actor of the
+// request, changes made outside of a request are recorded without actor.
+func (s *auditService) Record(ctx context.Context, entry *models.AuditEntry) error {
+	meta := utils.RequestMetaFrom(ctx)
+
+	log, err := models.NewAuditLog(entry, meta.ActorID, meta.ActorRole, meta.RequestID, meta.ClientIP)
+	if err != nil {
+		return err
+	}
+
+	return s.auditLogRepository.Append(ctx, log)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+)
+
+const (
+	minCreditScore = 300
+	maxCreditScore = 850
+)
+
+// CreditScorer assesses a borrower when a loan is proposed, before the loan
+// ever reaches a field validator. Implementations may call external bureaus.
+type CreditScorer interface {
+	Score(ctx context.Context, borrower *models.User) (*CreditAssessment, error)
+}
+
+type CreditAssessment struct {
+	Score      int
+	Grade      string
+	AutoReject bool
+}
+
+// CreditHistory is what the platform knows about the borrower's past loans
+type CreditHistory struct {
+	TotalLoans         int
+	RejectedLoans      int
+	OpenLoans          int
+	DisbursedLoans     int
+	PaidInstallments   int
+	LateInstallments   int
+	MissedInstallments int
+}
+
+type ruleBasedCreditScorer struct {
+	loanRepository        repositories.LoanRepositoryInterface
+	installmentRepository repositories.InstallmentRepositoryInterface
+	autoRejectScore       int
+}
+
+// NewRuleBasedCreditScorer scores from KYC and loan history, loans scoring
+// below autoRejectScore are rejected outright, zero disables auto rejection.
+func NewRuleBasedCreditScorer(
+	loanRepository repositories.LoanRepositoryInterface,
+	installmentRepository repositories.InstallmentRepositoryInterface,
+	autoRejectScore int,
+) CreditScorer {
+	return &ruleBasedCreditScorer{
+		loanRepository:        loanRepository,
+		installmentRepository: installmentRepository,
+		autoRejectScore:       autoRejectScore,
+	}
+}
+
+func (s *ruleBasedCreditScorer) Score(ctx context.Context, borrower *models.User) (*CreditAssessment, error) {
+	history, err := s.history(ctx, borrower.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	score := ScoreCredit(borrower, history)
+
+	return &CreditAssessment{
+		Score:      score,
+		Grade:      GradeCreditScore(score),
+		AutoReject: score < s.autoRejectScore,
+	}, nil
+}
+
+func (s *ruleBasedCreditScorer) history(ctx context.Context, borrowerID uint) (*CreditHistory, error) {
+	history := &CreditHistory{}
+	counts := []struct {
+		status *models.LoanStatus
+		target *int
+	}{
+		{nil, &history.TotalLoans},
+		{ptr.Of(models.LoanStatusRejected), &history.RejectedLoans},
+		{ptr.Of(models.LoanStatusProposed), &history.OpenLoans},
+		{ptr.Of(models.LoanStatusDisbursed), &history.DisbursedLoans},
+	}
+
+	for _, count := range counts {
+		total, err := s.loanRepository.Count(ctx, repositories.LoanRepositoryFilter{
+			BorrowerID: &borrowerID,
+			Status:     count.status,
+		})
+		if err != nil {
+			return nil, err
+		}
+		*count.target = total
+	}
+
+	// Paid on time, paid late, and still unpaid after the due date
+	year, month, day := time.Now().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	installmentCounts := []struct {
+		filter repositories.InstallmentRepositoryFilter
+		target *int
+	}{
+		{repositories.InstallmentRepositoryFilter{BorrowerID: &borrowerID, Paid: ptr.Of(true), PaidLate: ptr.Of(false)}, &history.PaidInstallments},
+		{repositories.InstallmentRepositoryFilter{BorrowerID: &borrowerID, Paid: ptr.Of(true), PaidLate: ptr.Of(true)}, &history.LateInstallments},
+		{repositories.InstallmentRepositoryFilter{BorrowerID: &borrowerID, Paid: ptr.Of(false), DueBefore: &today}, &history.MissedInstallments},
+	}
+
+	for _, count := range installmentCounts {
+		total, err := s.installmentRepository.Count(ctx, count.filter)
+		if err != nil {
+			return nil, err
+		}
+		*count.target = total
+	}
+
+	return history, nil
+}
+
+// ScoreCredit applies the default rules. Every borrower starts at 600, KYC
+// and completed loans raise the score while open proposals, rejections and
+// late or missed installments lower it.
+func ScoreCredit(borrower *models.User, history *CreditHistory) int {
+	score := 600
+
+	if borrower.KYCVerified {
+		score += 50
+	} else {
+		score -= 100
+	}
+
+	if borrower.Sector != "" && borrower.Region != "" {
+		score += 10
+	}
+
+	score += min(history.DisbursedLoans*25, 100)
+	score -= history.OpenLoans * 30
+	score -= min(history.RejectedLoans*20, 100)
+
+	// Borrowers without installments yet are neither rewarded nor penalised
+	installments := history.PaidInstallments + history.LateInstallments + history.MissedInstallments
+	if installments > 0 {
+		punctuality := float64(history.PaidInstallments) / float64(installments)
+		score += int((punctuality - 0.8) * 250)
+		score -= history.MissedInstallments * 15
+	}
+
+	return max(minCreditScore, min(score, maxCreditScore))
+}
+
+func GradeCreditScore(score int) string {
+	switch {
+	case score >= 750:
+		return "A"
+	case score >= 680:
+		return "B"
+	case score >= 600:
+		return "C"
+	case score >= 520:
+		return "D"
+	default:
+		return "E"
+	}
+}
@@ -0,0 +1,315 @@
+package file_services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+)
+
+const (
+	exifTagExifIFD            = 0x8769
+	exifTagGPSIFD             = 0x8825
+	exifTagDateTime           = 0x0132
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+
+	gpsTagLatitudeRef  = 0x01
+	gpsTagLatitude     = 0x02
+	gpsTagLongitudeRef = 0x03
+	gpsTagLongitude    = 0x04
+	gpsTagTimeStamp    = 0x07
+	gpsTagDateStamp    = 0x1D
+
+	exifTypeASCII     = 2
+	exifTypeShort     = 3
+	exifTypeLong      = 4
+	exifTypeRational  = 5
+	exifDateLayout    = "2006:01:02 15:04:05"
+	exifGPSDateLayout = "2006:01:02"
+)
+
+// exifTypeSizes holds the byte size of one value of each TIFF field type
+var exifTypeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+
+// PhotoMetadata is where and when a photo says it was taken
+type PhotoMetadata struct {
+	Latitude  *float64
+	Longitude *float64
+	TakenAt   *time.Time
+}
+
+func (m *PhotoMetadata) HasLocation() bool {
+	return m.Latitude != nil && m.Longitude != nil
+}
+
+// ReadPhotoMetadata reads the EXIF location and capture time of a JPEG
+// upload. Other files, and photos without readable EXIF, yield nil: the
+// metadata is evidence to weigh, not a reason to refuse the upload.
+func ReadPhotoMetadata(file *multipart.FileHeader) (*PhotoMetadata, error) {
+	uploadedFile, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer uploadedFile.Close()
+
+	segment, err := readExifSegment(bufio.NewReader(uploadedFile))
+	if err != nil || segment == nil {
+		return nil, err
+	}
+
+	return parseExif(segment), nil
+}
+
+// readExifSegment walks the JPEG markers up to the image data and returns
+// the TIFF block of the APP1 Exif segment.
+func readExifSegment(reader *bufio.Reader) ([]byte, error) {
+	soi := make([]byte, 2)
+	if _, err := io.ReadFull(reader, soi); err != nil || soi[0] != 0xFF || soi[1] != 0xD8 {
+		return nil, nil
+	}
+
+	for {
+		marker := make([]byte, 4)
+		if _, err := io.ReadFull(reader, marker); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		// Start of scan, the metadata segments all come before it
+		if marker[0] != 0xFF || marker[1] == 0xDA {
+			return nil, nil
+		}
+
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return nil, nil
+		}
+
+		if marker[1] != 0xE1 {
+			if _, err := reader.Discard(length); err != nil {
+				return nil, nil
+			}
+			continue
+		}
+
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(reader, segment); err != nil {
+			return nil, nil
+		}
+
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+type exifEntry struct {
+	fieldType uint16
+	count     uint32
+	value     []byte
+}
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func parseExif(data []byte) *PhotoMetadata {
+	if len(data) < 8 {
+		return nil
+	}
+
+	reader := &exifReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		reader.order = binary.LittleEndian
+	case "MM":
+		reader.order = binary.BigEndian
+	default:
+		return nil
+	}
+
+	ifd0, err := reader.readIFD(reader.order.Uint32(data[4:]))
+	if err != nil {
+		return nil
+	}
+
+	metadata := &PhotoMetadata{}
+	var location *time.Location
+	var takenAt string
+	if entry, ok := ifd0[exifTagDateTime]; ok {
+		takenAt = entry.ascii()
+	}
+
+	if offset, ok := reader.long(ifd0[exifTagExifIFD]); ok {
+		if exifIFD, err := reader.readIFD(offset); err == nil {
+			if entry, ok := exifIFD[exifTagDateTimeOriginal]; ok {
+				takenAt = entry.ascii()
+			}
+
+			if entry, ok := exifIFD[exifTagOffsetTimeOriginal]; ok {
+				location = parseExifOffset(entry.ascii())
+			}
+		}
+	}
+
+	if takenAt != "" {
+		if location == nil {
+			location = time.Local
+		}
+
+		if parsed, err := time.ParseInLocation(exifDateLayout, takenAt, location); err == nil {
+			metadata.TakenAt = &parsed
+		}
+	}
+
+	if offset, ok := reader.long(ifd0[exifTagGPSIFD]); ok {
+		if gpsIFD, err := reader.readIFD(offset); err == nil {
+			reader.readGPS(gpsIFD, metadata)
+		}
+	}
+
+	return metadata
+}
+
+func (r *exifReader) readIFD(offset uint32) (map[uint16]exifEntry, error) {
+	if uint64(offset)+2 > uint64(len(r.data)) {
+		return nil, errors.New("invalid_exif")
+	}
+
+	count := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(r.data) {
+		return nil, errors.New("invalid_exif")
+	}
+
+	entries := make(map[uint16]exifEntry, count)
+	for index := 0; index < count; index++ {
+		raw := r.data[start+index*12 : start+(index+1)*12]
+		entry := exifEntry{
+			fieldType: r.order.Uint16(raw[2:]),
+			count:     r.order.Uint32(raw[4:]),
+		}
+
+		size, ok := exifTypeSizes[entry.fieldType]
+		if !ok || entry.count > uint32(len(r.data)) {
+			continue
+		}
+
+		// Values up to four bytes sit in the entry, longer ones at an offset
+		total := uint64(size) * uint64(entry.count)
+		if total <= 4 {
+			entry.value = raw[8 : 8+total]
+		} else {
+			valueOffset := uint64(r.order.Uint32(raw[8:]))
+			if valueOffset+total > uint64(len(r.data)) {
+				continue
+			}
+			entry.value = r.data[valueOffset : valueOffset+total]
+		}
+
+		entries[r.order.Uint16(raw)] = entry
+	}
+
+	return entries, nil
+}
+
+func (r *exifReader) long(entry exifEntry) (uint32, bool) {
+	switch {
+	case entry.fieldType == exifTypeLong && len(entry.value) >= 4:
+		return r.order.Uint32(entry.value), true
+	case entry.fieldType == exifTypeShort && len(entry.value) >= 2:
+		return uint32(r.order.Uint16(entry.value)), true
+	default:
+		return 0, false
+	}
+}
+
+// rationals reads unsigned fractions, such as the degrees, minutes and
+// seconds of a GPS coordinate.
+func (r *exifReader) rationals(entry exifEntry, count int) ([]float64, bool) {
+	if entry.fieldType != exifTypeRational || len(entry.value) < count*8 {
+		return nil, false
+	}
+
+	values := make([]float64, count)
+	for index := range values {
+		numerator := r.order.Uint32(entry.value[index*8:])
+		denominator := r.order.Uint32(entry.value[index*8+4:])
+		if denominator == 0 {
+			return nil, false
+		}
+		values[index] = float64(numerator) / float64(denominator)
+	}
+
+	return values, true
+}
+
+func (r *exifReader) coordinate(entries map[uint16]exifEntry, refTag uint16, valueTag uint16, negativeRef string, limit float64) *float64 {
+	parts, ok := r.rationals(entries[valueTag], 3)
+	if !ok {
+		return nil
+	}
+
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+	if strings.EqualFold(entries[refTag].ascii(), negativeRef) {
+		value = -value
+	}
+
+	if value < -limit || value > limit {
+		return nil
+	}
+
+	return &value
+}
+
+func (r *exifReader) readGPS(entries map[uint16]exifEntry, metadata *PhotoMetadata) {
+	latitude := r.coordinate(entries, gpsTagLatitudeRef, gpsTagLatitude, "S", 90)
+	longitude := r.coordinate(entries, gpsTagLongitudeRef, gpsTagLongitude, "W", 180)
+	if latitude != nil && longitude != nil {
+		metadata.Latitude = latitude
+		metadata.Longitude = longitude
+	}
+
+	// The GPS fix time is UTC and set by the satellites, it is trusted over
+	// the camera clock
+	date, err := time.Parse(exifGPSDateLayout, entries[gpsTagDateStamp].ascii())
+	if err != nil {
+		return
+	}
+
+	clock, ok := r.rationals(entries[gpsTagTimeStamp], 3)
+	if !ok {
+		return
+	}
+
+	takenAt := date.Add(time.Duration((clock[0]*3600 + clock[1]*60 + clock[2]) * float64(time.Second)))
+	metadata.TakenAt = &takenAt
+}
+
+func (e exifEntry) ascii() string {
+	if e.fieldType != exifTypeASCII {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+}
+
+// parseExifOffset reads the "+07:00" style offset cameras store next to
+// the capture time.
+func parseExifOffset(value string) *time.Location {
+	offset, err := time.Parse("-07:00", value)
+	if err != nil {
+		return nil
+	}
+
+	_, seconds := offset.Zone()
+	return time.FixedZone(value, seconds)
+}
@@ -0,0 +1,271 @@
+package file_services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+)
+
+// testByteOrder writes the TIFF block the way exifReader reads it
+type testByteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+type testExifEntry struct {
+	tag       uint16
+	fieldType uint16
+	count     uint32
+	value     []byte
+}
+
+func asciiEntry(tag uint16, value string) testExifEntry {
+	return testExifEntry{tag: tag, fieldType: exifTypeASCII, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+}
+
+func rationalsEntry(order testByteOrder, tag uint16, values ...uint32) testExifEntry {
+	value := make([]byte, 0, len(values)*8)
+	for _, numerator := range values {
+		value = order.AppendUint32(value, numerator)
+		value = order.AppendUint32(value, 1)
+	}
+
+	return testExifEntry{tag: tag, fieldType: exifTypeRational, count: uint32(len(values)), value: value}
+}
+
+// buildTIFF lays out IFD0 and, when given, the Exif and GPS IFDs it points
+// to, with the values longer than four bytes after the directories.
+func buildTIFF(order testByteOrder, ifd0 []testExifEntry, exifIFD []testExifEntry, gpsIFD []testExifEntry) []byte {
+	ifds := [][]testExifEntry{ifd0}
+	pointers := []uint16{0}
+	if exifIFD != nil {
+		ifds = append(ifds, exifIFD)
+		pointers = append(pointers, exifTagExifIFD)
+	}
+	if gpsIFD != nil {
+		ifds = append(ifds, gpsIFD)
+		pointers = append(pointers, exifTagGPSIFD)
+	}
+
+	// IFD0 gains one pointer entry per sub IFD
+	sizes := make([]int, len(ifds))
+	for index, ifd := range ifds {
+		count := len(ifd)
+		if index == 0 {
+			count += len(ifds) - 1
+		}
+		sizes[index] = 2 + count*12 + 4
+	}
+
+	offsets := make([]uint32, len(ifds))
+	next := uint32(8)
+	for index := range ifds {
+		offsets[index] = next
+		next += uint32(sizes[index])
+	}
+
+	for index := 1; index < len(ifds); index++ {
+		ifds[0] = append(ifds[0], testExifEntry{tag: pointers[index], fieldType: exifTypeLong, count: 1, value: order.AppendUint32(nil, offsets[index])})
+	}
+
+	header := []byte("II")
+	if order.String() == binary.BigEndian.String() {
+		header = []byte("MM")
+	}
+	header = order.AppendUint16(header, 42)
+	header = order.AppendUint32(header, 8)
+
+	directories := []byte{}
+	values := []byte{}
+	for _, ifd := range ifds {
+		directories = order.AppendUint16(directories, uint16(len(ifd)))
+		for _, entry := range ifd {
+			directories = order.AppendUint16(directories, entry.tag)
+			directories = order.AppendUint16(directories, entry.fieldType)
+			directories = order.AppendUint32(directories, entry.count)
+			if len(entry.value) <= 4 {
+				directories = append(directories, append(entry.value, make([]byte, 4-len(entry.value))...)...)
+				continue
+			}
+
+			directories = order.AppendUint32(directories, next+uint32(len(values)))
+			values = append(values, entry.value...)
+		}
+		directories = order.AppendUint32(directories, 0)
+	}
+
+	return append(append(header, directories...), values...)
+}
+
+// buildJPEG wraps the TIFF block in an APP1 segment behind an APP0 one, the
+// way cameras write them.
+func buildJPEG(tiff []byte) []byte {
+	jpeg := []byte{0xFF, 0xD8}
+	jfif := []byte("JFIF\x00\x01\x02\x00\x00\x01\x00\x01\x00\x00")
+	jpeg = append(jpeg, 0xFF, 0xE0)
+	jpeg = binary.BigEndian.AppendUint16(jpeg, uint16(len(jfif)+2))
+	jpeg = append(jpeg, jfif...)
+
+	if tiff != nil {
+		exif := append([]byte("Exif\x00\x00"), tiff...)
+		jpeg = append(jpeg, 0xFF, 0xE1)
+		jpeg = binary.BigEndian.AppendUint16(jpeg, uint16(len(exif)+2))
+		jpeg = append(jpeg, exif...)
+	}
+
+	return append(jpeg, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+func TestReadExifSegment(t *testing.T) {
+	tiff := buildTIFF(binary.LittleEndian, []testExifEntry{asciiEntry(exifTagDateTime, "2024:03:01 09:30:00")}, nil, nil)
+
+	tests := []struct {
+		name string
+		file []byte
+		want []byte
+	}{
+		{name: "exif after jfif", file: buildJPEG(tiff), want: tiff},
+		{name: "jpeg without exif", file: buildJPEG(nil)},
+		{name: "not a jpeg", file: []byte("%PDF-1.7")},
+		{name: "truncated segment", file: buildJPEG(tiff)[:30]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segment, err := readExifSegment(bufio.NewReader(bytes.NewReader(tt.file)))
+			if err != nil {
+				t.Fatalf("read exif segment: %v", err)
+			}
+
+			if !bytes.Equal(segment, tt.want) {
+				t.Errorf("segment = %x, want %x", segment, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExif(t *testing.T) {
+	jakarta := time.FixedZone("+07:00", 7*60*60)
+
+	tests := []struct {
+		name          string
+		order         testByteOrder
+		ifd0          []testExifEntry
+		exifIFD       []testExifEntry
+		gpsIFD        func(order testByteOrder) []testExifEntry
+		wantLatitude  *float64
+		wantLongitude *float64
+		wantTakenAt   *time.Time
+	}{
+		{
+			name:    "gps fix time wins over the camera clock",
+			order:   binary.LittleEndian,
+			exifIFD: []testExifEntry{asciiEntry(exifTagDateTimeOriginal, "2024:03:01 09:30:00"), asciiEntry(exifTagOffsetTimeOriginal, "+07:00")},
+			gpsIFD: func(order testByteOrder) []testExifEntry {
+				return []testExifEntry{
+					asciiEntry(gpsTagLatitudeRef, "S"),
+					rationalsEntry(order, gpsTagLatitude, 6, 10, 30),
+					asciiEntry(gpsTagLongitudeRef, "E"),
+					rationalsEntry(order, gpsTagLongitude, 106, 49, 12),
+					asciiEntry(gpsTagDateStamp, "2024:03:01"),
+					rationalsEntry(order, gpsTagTimeStamp, 2, 31, 0),
+				}
+			},
+			wantLatitude:  ptr.Of(-6.175),
+			wantLongitude: ptr.Of(106.82),
+			wantTakenAt:   ptr.Of(time.Date(2024, time.March, 1, 2, 31, 0, 0, time.UTC)),
+		},
+		{
+			name:        "big endian capture time with its offset",
+			order:       binary.BigEndian,
+			ifd0:        []testExifEntry{asciiEntry(exifTagDateTime, "2024:03:02 10:00:00")},
+			exifIFD:     []testExifEntry{asciiEntry(exifTagDateTimeOriginal, "2024:03:01 09:30:00"), asciiEntry(exifTagOffsetTimeOriginal, "+07:00")},
+			wantTakenAt: ptr.Of(time.Date(2024, time.March, 1, 9, 30, 0, 0, jakarta)),
+		},
+		{
+			name:  "coordinates out of range are dropped",
+			order: binary.LittleEndian,
+			gpsIFD: func(order testByteOrder) []testExifEntry {
+				return []testExifEntry{
+					asciiEntry(gpsTagLatitudeRef, "N"),
+					rationalsEntry(order, gpsTagLatitude, 95, 0, 0),
+					asciiEntry(gpsTagLongitudeRef, "W"),
+					rationalsEntry(order, gpsTagLongitude, 10, 0, 0),
+				}
+			},
+		},
+		{
+			name:  "western longitude",
+			order: binary.BigEndian,
+			gpsIFD: func(order testByteOrder) []testExifEntry {
+				return []testExifEntry{
+					asciiEntry(gpsTagLatitudeRef, "N"),
+					rationalsEntry(order, gpsTagLatitude, 40, 30, 0),
+					asciiEntry(gpsTagLongitudeRef, "W"),
+					rationalsEntry(order, gpsTagLongitude, 73, 45, 0),
+				}
+			},
+			wantLatitude:  ptr.Of(40.5),
+			wantLongitude: ptr.Of(-73.75),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gpsIFD []testExifEntry
+			if tt.gpsIFD != nil {
+				gpsIFD = tt.gpsIFD(tt.order)
+			}
+
+			metadata := parseExif(buildTIFF(tt.order, tt.ifd0, tt.exifIFD, gpsIFD))
+			if metadata == nil {
+				t.Fatal("no metadata parsed")
+			}
+
+			if !floatsEqual(metadata.Latitude, tt.wantLatitude) || !floatsEqual(metadata.Longitude, tt.wantLongitude) {
+				t.Errorf("location = %v, %v, want %v, %v", deref(metadata.Latitude), deref(metadata.Longitude), deref(tt.wantLatitude), deref(tt.wantLongitude))
+			}
+
+			if (metadata.TakenAt == nil) != (tt.wantTakenAt == nil) || (metadata.TakenAt != nil && !metadata.TakenAt.Equal(*tt.wantTakenAt)) {
+				t.Errorf("taken at = %v, want %v", metadata.TakenAt, tt.wantTakenAt)
+			}
+		})
+	}
+}
+
+func TestParseExifRejectsMalformed(t *testing.T) {
+	valid := buildTIFF(binary.LittleEndian, []testExifEntry{asciiEntry(exifTagDateTime, "2024:03:01 09:30:00")}, nil, nil)
+
+	badOrder := append([]byte("XX"), valid[2:]...)
+	badOffset := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint32(badOffset[4:], uint32(len(valid)))
+
+	for name, data := range map[string][]byte{"too short": valid[:6], "unknown byte order": badOrder, "ifd past the end": badOffset} {
+		t.Run(name, func(t *testing.T) {
+			if metadata := parseExif(data); metadata != nil {
+				t.Errorf("metadata = %+v, want nil", metadata)
+			}
+		})
+	}
+}
+
+func floatsEqual(got *float64, want *float64) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+
+	diff := *got - *want
+	return diff < 1e-9 && diff > -1e-9
+}
+
+func deref(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
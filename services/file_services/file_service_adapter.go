@@ -1,14 +1,42 @@
 package file_services
 
-import "mime/multipart"
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/peang/amartha-loan-service/configs"
+)
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
 
 type FileServiceInterface interface {
-	Upload(file *multipart.FileHeader) (fileUrl string, err error)
+	Upload(file *multipart.FileHeader) (*UploadedFile, error)
+	UploadContent(content io.ReadSeeker) (*UploadedFile, error)
+	DownloadURL(fileUrl string) (string, error)
+	Open(fileUrl string) (io.ReadSeekCloser, error)
 }
 
-func NewFileService() FileServiceInterface {
-	// you can pass config here, and return instance depends on the config
-
-	// for this purpose we're gonna local upload file service in file
-	return NewLocalFileService()
+func NewFileService(conf *configs.Config) (FileServiceInterface, error) {
+	switch conf.FileStorage {
+	case StorageS3:
+		return NewS3FileService(S3Config{
+			Endpoint:      conf.S3Endpoint,
+			Region:        conf.S3Region,
+			Bucket:        conf.S3Bucket,
+			AccessKey:     conf.S3AccessKey,
+			SecretKey:     conf.S3SecretKey,
+			UsePathStyle:  conf.S3UsePathStyle,
+			PresignExpiry: conf.S3PresignExpiry,
+			MaxUploadSize: conf.UploadMaxSize,
+		}, &http.Client{Timeout: conf.S3Timeout})
+	case StorageLocal:
+		return NewLocalFileService(conf.UploadMaxSize), nil
+	default:
+		return nil, fmt.Errorf("unknown file storage %q, use %s or %s", conf.FileStorage, StorageLocal, StorageS3)
+	}
 }
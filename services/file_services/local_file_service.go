@@ -1,43 +1,94 @@
 package file_services
 
 import (
+	"errors"
 	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
-type localFileService struct{}
+const localFolderPath = "file_uploads"
+
+type localFileService struct {
+	maxSize int64
+}
+
+func NewLocalFileService(maxSize int64) FileServiceInterface {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxUploadSize
+	}
 
-func NewLocalFileService() FileServiceInterface {
-	return &localFileService{}
+	return &localFileService{
+		maxSize: maxSize,
+	}
 }
 
-func (s *localFileService) Upload(file *multipart.FileHeader) (string, error) {
-	uploadedFile, err := file.Open()
+func (s *localFileService) Upload(file *multipart.FileHeader) (*UploadedFile, error) {
+	uploadedFile, result, extension, err := openUpload(file, s.maxSize)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer uploadedFile.Close()
 
-	folderPath := "file_uploads"
-	err = os.MkdirAll(folderPath, os.ModePerm)
+	return s.store(uploadedFile, result, extension)
+}
+
+func (s *localFileService) UploadContent(content io.ReadSeeker) (*UploadedFile, error) {
+	result, extension, err := inspectContent(content, s.maxSize)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	filename := filepath.Join(folderPath, file.Filename)
-	newFile, err := os.Create(filename)
+	return s.store(content, result, extension)
+}
+
+// DownloadURL returns the stored path as is, local files are served from disk
+func (s *localFileService) DownloadURL(fileUrl string) (string, error) {
+	return fileUrl, nil
+}
+
+func (s *localFileService) Open(fileUrl string) (io.ReadSeekCloser, error) {
+	filename := filepath.Clean(fileUrl)
+	if !strings.HasPrefix(filename, localFolderPath+string(filepath.Separator)) {
+		return nil, errors.New("file_not_found")
+	}
+
+	file, err := os.Open(filename)
 	if err != nil {
-		return "", err
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("file_not_found")
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *localFileService) store(content io.Reader, result *UploadedFile, extension string) (*UploadedFile, error) {
+	err := os.MkdirAll(localFolderPath, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never trust the client filename, two uploads of "proof.jpeg" must not collide
+	filename := filepath.Join(localFolderPath, uuid.New().String()+extension)
+	newFile, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
 	}
 	defer newFile.Close()
 
 	// Copy the uploaded file to the new file
-	_, err = io.Copy(newFile, uploadedFile)
+	_, err = io.Copy(newFile, content)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return filename, nil
+	result.URL = filename
+
+	return result, nil
 }
@@ -0,0 +1,290 @@
+package file_services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3Service         = "s3"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+type S3Config struct {
+	Endpoint      string
+	Region        string
+	Bucket        string
+	AccessKey     string
+	SecretKey     string
+	UsePathStyle  bool
+	PresignExpiry time.Duration
+	MaxUploadSize int64
+}
+
+// s3FileService talks to any S3-compatible storage (AWS S3, MinIO) using
+// SigV4-signed requests, storing uploads under content-addressed keys.
+type s3FileService struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3FileService(config S3Config, client *http.Client) (FileServiceInterface, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+
+	if config.PresignExpiry <= 0 {
+		config.PresignExpiry = 15 * time.Minute
+	}
+
+	if config.MaxUploadSize <= 0 {
+		config.MaxUploadSize = DefaultMaxUploadSize
+	}
+
+	return &s3FileService{
+		config:   config,
+		endpoint: endpoint,
+		client:   client,
+	}, nil
+}
+
+func (s *s3FileService) Upload(file *multipart.FileHeader) (*UploadedFile, error) {
+	uploadedFile, result, extension, err := openUpload(file, s.config.MaxUploadSize)
+	if err != nil {
+		return nil, err
+	}
+	defer uploadedFile.Close()
+
+	return s.put(uploadedFile, result, extension)
+}
+
+func (s *s3FileService) UploadContent(content io.ReadSeeker) (*UploadedFile, error) {
+	result, extension, err := inspectContent(content, s.config.MaxUploadSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.put(content, result, extension)
+}
+
+func (s *s3FileService) Open(key string) (io.ReadSeekCloser, error) {
+	req, err := http.NewRequest(http.MethodHead, s.presign(http.MethodHead, key, time.Now().UTC()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("file_not_found")
+	}
+
+	// Our credentials or bucket policy are wrong, not the key
+	if resp.StatusCode == http.StatusForbidden {
+		return nil, errors.New("file_storage_forbidden")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3 head failed with status %d", resp.StatusCode)
+	}
+
+	return &s3Object{
+		service: s,
+		key:     key,
+		size:    resp.ContentLength,
+	}, nil
+}
+
+func (s *s3FileService) put(content io.Reader, result *UploadedFile, extension string) (*UploadedFile, error) {
+	// The checksum doubles as the SigV4 payload hash
+	key := fmt.Sprintf("uploads/%s%s", result.Checksum, extension)
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key).String(), content)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = result.Size
+	req.Header.Set("Content-Type", result.ContentType)
+	s.sign(req, result.Checksum, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 upload failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	result.URL = key
+
+	return result, nil
+}
+
+func (s *s3FileService) DownloadURL(key string) (string, error) {
+	return s.presign(http.MethodGet, key, time.Now().UTC()), nil
+}
+
+func (s *s3FileService) presign(method string, key string, now time.Time) string {
+	objectURL := s.objectURL(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", fmt.Sprintf("%d", int(s.config.PresignExpiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		objectURL.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + objectURL.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(now, canonicalRequest))
+	objectURL.RawQuery = canonicalQuery(query)
+
+	return objectURL.String()
+}
+
+func (s *s3FileService) objectURL(key string) *url.URL {
+	objectURL := *s.endpoint
+
+	escapedKey := escapePath(key)
+	if s.config.UsePathStyle {
+		objectURL.Path = "/" + s.config.Bucket + "/" + key
+		objectURL.RawPath = "/" + s.config.Bucket + "/" + escapedKey
+	} else {
+		objectURL.Host = s.config.Bucket + "." + objectURL.Host
+		objectURL.Path = "/" + key
+		objectURL.RawPath = "/" + escapedKey
+	}
+
+	return &objectURL
+}
+
+func (s *s3FileService) sign(req *http.Request, payloadHash string, now time.Time) {
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		signedHeaders = append([]string{"content-type"}, signedHeaders...)
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, header := range signedHeaders {
+		value := req.Header.Get(header)
+		if header == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm,
+		s.config.AccessKey,
+		s.scope(now),
+		strings.Join(signedHeaders, ";"),
+		s.signature(now, canonicalRequest),
+	))
+}
+
+func (s *s3FileService) scope(now time.Time) string {
+	return strings.Join([]string{now.Format(s3DateFormat), s.config.Region, s3Service, "aws4_request"}, "/")
+}
+
+func (s *s3FileService) signature(now time.Time, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		s.scope(now),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		vals := append([]string{}, values[key]...)
+		sort.Strings(vals)
+		for _, val := range vals {
+			pairs = append(pairs, escapeQuery(key)+"="+escapeQuery(val))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// escapeQuery encodes per SigV4 rules, which want %20 rather than '+' for spaces.
+func escapeQuery(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escapeQuery(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
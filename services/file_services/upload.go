@@ -0,0 +1,112 @@
+package file_services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+)
+
+const DefaultMaxUploadSize int64 = 5 << 20
+
+type UploadedFile struct {
+	URL         string
+	Checksum    string
+	ContentType string
+	Size        int64
+}
+
+type fileSignature struct {
+	magic       []byte
+	contentType string
+	extension   string
+}
+
+// Only the leading bytes are trusted, the client supplied name and
+// Content-Type header are never used to decide what a file is.
+var allowedSignatures = []fileSignature{
+	{magic: []byte("%PDF-"), contentType: "application/pdf", extension: ".pdf"},
+	{magic: []byte{0xFF, 0xD8, 0xFF}, contentType: "image/jpeg", extension: ".jpeg"},
+	{magic: []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}, contentType: "image/png", extension: ".png"},
+}
+
+// openUpload validates an upload and returns it rewound together with its
+// sniffed content type, storage extension, size and SHA-256 checksum.
+func openUpload(file *multipart.FileHeader, maxSize int64) (multipart.File, *UploadedFile, string, error) {
+	if file.Filename == "" || strings.Contains(file.Filename, "..") || strings.ContainsAny(file.Filename, "/\\\x00") {
+		return nil, nil, "", errors.New("invalid_file_name")
+	}
+
+	if file.Size <= 0 {
+		return nil, nil, "", errors.New("empty_file")
+	}
+
+	if file.Size > maxSize {
+		return nil, nil, "", errors.New("file_too_large")
+	}
+
+	uploadedFile, err := file.Open()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	result, extension, err := inspectContent(uploadedFile, maxSize)
+	if err != nil {
+		uploadedFile.Close()
+		return nil, nil, "", err
+	}
+
+	return uploadedFile, result, extension, nil
+}
+
+// inspectContent sniffs and hashes content, leaving it rewound for storage.
+func inspectContent(content io.ReadSeeker, maxSize int64) (*UploadedFile, string, error) {
+	header := make([]byte, 512)
+	n, err := io.ReadFull(content, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, "", errors.New("empty_file")
+		}
+		return nil, "", err
+	}
+	header = header[:n]
+
+	var signature *fileSignature
+	for i := range allowedSignatures {
+		if bytes.HasPrefix(header, allowedSignatures[i].magic) {
+			signature = &allowedSignatures[i]
+			break
+		}
+	}
+
+	if signature == nil {
+		return nil, "", errors.New("file_type_not_allowed")
+	}
+
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if size > maxSize {
+		return nil, "", errors.New("file_too_large")
+	}
+
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+
+	return &UploadedFile{
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		ContentType: signature.contentType,
+		Size:        size,
+	}, signature.extension, nil
+}
@@ -0,0 +1,57 @@
+package payment_services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// CallbackTolerance bounds how old a signed callback may be, replaying a
+// captured callback later than this is refused.
+const CallbackTolerance = 5 * time.Minute
+
+// SignCallback computes the callback signature as HMAC-SHA256 over
+// "<timestamp>.<body>" with the shared gateway secret.
+func SignCallback(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyCallback checks the signature and freshness of a callback and decodes it
+func VerifyCallback(secret string, body []byte, timestamp string, signature string, now time.Time) (*Callback, error) {
+	// Without a configured secret anybody could forge a callback
+	if secret == "" {
+		return nil, errors.New("invalid_callback_signature")
+	}
+
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid_callback_signature")
+	}
+
+	age := now.Sub(time.Unix(sentAt, 0))
+	if age > CallbackTolerance || age < -CallbackTolerance {
+		return nil, errors.New("invalid_callback_signature")
+	}
+
+	expected := SignCallback(secret, sentAt, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, errors.New("invalid_callback_signature")
+	}
+
+	var callback Callback
+	err = json.Unmarshal(body, &callback)
+	if err != nil || callback.Reference == "" {
+		return nil, errors.New("invalid_callback_payload")
+	}
+
+	return &callback, nil
+}
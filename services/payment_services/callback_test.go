@@ -0,0 +1,135 @@
+package payment_services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testCallbackSecret = "callback-secret"
+
+func TestVerifyCallback(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"transfer.completed","reference":"ref-1","gateway_reference":"gw-1","status":"success","amount":1000}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := SignCallback(testCallbackSecret, now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		timestamp string
+		signature string
+		wantErr   string
+	}{
+		{name: "valid", secret: testCallbackSecret, body: body, timestamp: timestamp, signature: signature},
+		{name: "no secret configured", body: body, timestamp: timestamp, signature: SignCallback("", now.Unix(), body), wantErr: "invalid_callback_signature"},
+		{name: "wrong secret", secret: "other-secret", body: body, timestamp: timestamp, signature: signature, wantErr: "invalid_callback_signature"},
+		{name: "tampered body", secret: testCallbackSecret, body: []byte(`{"event":"transfer.completed","reference":"ref-1","status":"success","amount":9000}`), timestamp: timestamp, signature: signature, wantErr: "invalid_callback_signature"},
+		{name: "malformed timestamp", secret: testCallbackSecret, body: body, timestamp: "yesterday", signature: signature, wantErr: "invalid_callback_signature"},
+		{
+			name:      "replayed too late",
+			secret:    testCallbackSecret,
+			body:      body,
+			timestamp: strconv.FormatInt(now.Add(-CallbackTolerance-time.Second).Unix(), 10),
+			signature: SignCallback(testCallbackSecret, now.Add(-CallbackTolerance-time.Second).Unix(), body),
+			wantErr:   "invalid_callback_signature",
+		},
+		{
+			name:      "missing reference",
+			secret:    testCallbackSecret,
+			body:      []byte(`{"event":"transfer.completed"}`),
+			timestamp: timestamp,
+			signature: SignCallback(testCallbackSecret, now.Unix(), []byte(`{"event":"transfer.completed"}`)),
+			wantErr:   "invalid_callback_payload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callback, err := VerifyCallback(tt.secret, tt.body, tt.timestamp, tt.signature, now)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+
+			if callback.Reference != "ref-1" || callback.GatewayReference != "gw-1" || callback.Status != PaymentStatusSuccess || callback.Amount != 1000 {
+				t.Errorf("callback = %+v, want the decoded body", callback)
+			}
+		})
+	}
+}
+
+func TestSimulatedTransferCallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		accountNumber string
+		wantEvent     string
+		wantStatus    string
+	}{
+		{name: "completed", accountNumber: "1234567890", wantEvent: CallbackTransferCompleted, wantStatus: PaymentStatusSuccess},
+		{name: "refused account", accountNumber: "0001234567", wantEvent: CallbackTransferFailed, wantStatus: PaymentStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbacks := make(chan *Callback, 1)
+			var gateway PaymentGateway
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				// The signed request must pass the same check the handler runs
+				callback, err := gateway.ParseCallback(body, r.Header.Get("X-Callback-Timestamp"), r.Header.Get("X-Callback-Signature"))
+				if err != nil {
+					t.Errorf("parse callback: %v", err)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				callbacks <- callback
+			}))
+			defer server.Close()
+
+			gateway = NewSimulatedPaymentGateway(SimulatedConfig{
+				CallbackURL:    server.URL,
+				CallbackSecret: testCallbackSecret,
+			}, server.Client())
+
+			result, err := gateway.Transfer(context.Background(), &Transfer{
+				Reference:         "ref-1",
+				Amount:            1000,
+				BankAccountNumber: tt.accountNumber,
+			})
+			if err != nil {
+				t.Fatalf("transfer: %v", err)
+			}
+
+			if result.Status != PaymentStatusPending {
+				t.Errorf("transfer status = %s, want %s until the callback", result.Status, PaymentStatusPending)
+			}
+
+			select {
+			case callback := <-callbacks:
+				if callback.Event != tt.wantEvent || callback.Status != tt.wantStatus {
+					t.Errorf("callback = %s %s, want %s %s", callback.Event, callback.Status, tt.wantEvent, tt.wantStatus)
+				}
+
+				if callback.Reference != "ref-1" || callback.GatewayReference != result.GatewayReference {
+					t.Errorf("callback references = %s %s, want ref-1 %s", callback.Reference, callback.GatewayReference, result.GatewayReference)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no callback received")
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package payment_services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peang/amartha-loan-service/configs"
+)
+
+const (
+	GatewaySimulated = "simulated"
+)
+
+const (
+	PaymentMethodBankTransfer   = "bank_transfer"
+	PaymentMethodVirtualAccount = "virtual_account"
+	PaymentMethodEwallet        = "ewallet"
+	// PaymentMethodCash is collected in person by a field officer, it never
+	// goes through the gateway
+	PaymentMethodCash = "cash"
+)
+
+var PaymentMethods = map[string]bool{
+	PaymentMethodBankTransfer:   true,
+	PaymentMethodVirtualAccount: true,
+	PaymentMethodEwallet:        true,
+}
+
+const (
+	PaymentStatusPending = "pending"
+	PaymentStatusSuccess = "success"
+	PaymentStatusFailed  = "failed"
+)
+
+const (
+	CallbackTransferCompleted  = "transfer.completed"
+	CallbackTransferFailed     = "transfer.failed"
+	CallbackVirtualAccountPaid = "virtual_account.paid"
+)
+
+// PaymentGateway moves money in and out of the platform. Charges settle
+// synchronously, transfers and virtual account payments are confirmed later
+// through a signed callback that ParseCallback verifies.
+type PaymentGateway interface {
+	Charge(ctx context.Context, charge *Charge) (*ChargeResult, error)
+	Transfer(ctx context.Context, transfer *Transfer) (*TransferResult, error)
+	Refund(ctx context.Context, refund *Refund) (*RefundResult, error)
+	CreateVirtualAccount(ctx context.Context, request *VirtualAccountRequest) (*VirtualAccount, error)
+	ParseCallback(body []byte, timestamp string, signature string) (*Callback, error)
+}
+
+// Charge collects money from a customer, Reference is our own idempotency key
+type Charge struct {
+	Reference  string
+	CustomerID uint
+	Amount     float64
+	Method     string
+}
+
+type ChargeResult struct {
+	Reference        string
+	GatewayReference string
+	Status           string
+	FailureReason    string
+}
+
+// Transfer pays out to a bank account, Reference is our idempotency key
+type Transfer struct {
+	Reference         string
+	Amount            float64
+	BankCode          string
+	BankAccountNumber string
+	BankAccountName   string
+}
+
+type TransferResult struct {
+	Reference        string
+	GatewayReference string
+	Status           string
+}
+
+// Refund gives back a charge, GatewayReference identifies the charge
+type Refund struct {
+	Reference        string
+	GatewayReference string
+	Amount           float64
+}
+
+type RefundResult struct {
+	Reference        string
+	GatewayReference string
+	Status           string
+}
+
+type VirtualAccountRequest struct {
+	Reference string
+	Name      string
+}
+
+type VirtualAccount struct {
+	Reference string
+	Number    string
+}
+
+type Callback struct {
+	Event                string  `json:"event"`
+	Reference            string  `json:"reference"`
+	GatewayReference     string  `json:"gateway_reference"`
+	Status               string  `json:"status"`
+	Amount               float64 `json:"amount"`
+	VirtualAccountNumber string  `json:"virtual_account_number,omitempty"`
+	FailureReason        string  `json:"failure_reason,omitempty"`
+}
+
+func NewPaymentGateway(conf *configs.Config) PaymentGateway {
+	switch conf.PaymentGateway {
+	default:
+		return NewSimulatedPaymentGateway(SimulatedConfig{
+			CallbackURL:    conf.PaymentCallbackURL,
+			CallbackSecret: conf.PaymentCallbackSecret,
+			CallbackDelay:  conf.PaymentCallbackDelay,
+		}, &http.Client{Timeout: conf.PaymentCallbackTimeout})
+	}
+}
@@ -0,0 +1,137 @@
+package payment_services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SimulatedConfig struct {
+	CallbackURL    string
+	CallbackSecret string
+	CallbackDelay  time.Duration
+}
+
+// simulatedPaymentGateway stands in for a real provider in local and staging
+// environments. Charges and refunds settle instantly, transfers are confirmed by posting
+// a signed callback back to the service after CallbackDelay. Transfers to
+// account numbers starting with "000" fail, to exercise the failure path.
+type simulatedPaymentGateway struct {
+	config SimulatedConfig
+	client *http.Client
+}
+
+func NewSimulatedPaymentGateway(config SimulatedConfig, client *http.Client) PaymentGateway {
+	return &simulatedPaymentGateway{
+		config: config,
+		client: client,
+	}
+}
+
+func (g *simulatedPaymentGateway) Charge(ctx context.Context, charge *Charge) (*ChargeResult, error) {
+	result := &ChargeResult{
+		Reference:        charge.Reference,
+		GatewayReference: "sim_" + uuid.NewString(),
+		Status:           PaymentStatusSuccess,
+	}
+
+	if !PaymentMethods[charge.Method] {
+		result.Status = PaymentStatusFailed
+		result.FailureReason = "unsupported_payment_method"
+	}
+
+	return result, nil
+}
+
+func (g *simulatedPaymentGateway) Transfer(ctx context.Context, transfer *Transfer) (*TransferResult, error) {
+	result := &TransferResult{
+		Reference:        transfer.Reference,
+		GatewayReference: "sim_" + uuid.NewString(),
+		Status:           PaymentStatusPending,
+	}
+
+	callback := Callback{
+		Event:            CallbackTransferCompleted,
+		Reference:        transfer.Reference,
+		GatewayReference: result.GatewayReference,
+		Status:           PaymentStatusSuccess,
+		Amount:           transfer.Amount,
+	}
+
+	if transfer.BankAccountNumber == "" || strings.HasPrefix(transfer.BankAccountNumber, "000") {
+		callback.Event = CallbackTransferFailed
+		callback.Status = PaymentStatusFailed
+		callback.FailureReason = "invalid_bank_account"
+	}
+
+	go func() {
+		time.Sleep(g.config.CallbackDelay)
+		if err := g.sendCallback(&callback); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	return result, nil
+}
+
+func (g *simulatedPaymentGateway) Refund(ctx context.Context, refund *Refund) (*RefundResult, error) {
+	return &RefundResult{
+		Reference:        refund.Reference,
+		GatewayReference: "sim_" + uuid.NewString(),
+		Status:           PaymentStatusSuccess,
+	}, nil
+}
+
+func (g *simulatedPaymentGateway) CreateVirtualAccount(ctx context.Context, request *VirtualAccountRequest) (*VirtualAccount, error) {
+	// Derived from the reference so asking twice gives the same number
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, request.Reference)
+
+	return &VirtualAccount{
+		Reference: request.Reference,
+		Number:    "8808" + fmt.Sprintf("%012s", digits[:min(len(digits), 12)]),
+	}, nil
+}
+
+func (g *simulatedPaymentGateway) ParseCallback(body []byte, timestamp string, signature string) (*Callback, error) {
+	return VerifyCallback(g.config.CallbackSecret, body, timestamp, signature, time.Now())
+}
+
+func (g *simulatedPaymentGateway) sendCallback(callback *Callback) error {
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequest(http.MethodPost, g.config.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Callback-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Callback-Signature", SignCallback(g.config.CallbackSecret, timestamp, body))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("payment callback rejected with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+)
+
+type PricingServiceInterface interface {
+	Price(ctx context.Context, product string, riskGrade string, tenor int) (*models.RateCard, error)
+}
+
+type pricingService struct {
+	rateCardRepository repositories.RateCardRepositoryInterface
+}
+
+func NewPricingService(rateCardRepository repositories.RateCardRepositoryInterface) PricingServiceInterface {
+	return &pricingService{
+		rateCardRepository: rateCardRepository,
+	}
+}
+
+// Price picks the active rate card for the product, grade and tenor, the
+// loan then snapshots the card id and version so later edits never reprice it.
+func (s *pricingService) Price(ctx context.Context, product string, riskGrade string, tenor int) (*models.RateCard, error) {
+	if !models.RiskGrades[riskGrade] {
+		return nil, errors.New("invalid_risk_grade")
+	}
+
+	rateCard, err := s.rateCardRepository.FindActive(ctx, product, riskGrade, tenor)
+	if err != nil {
+		return nil, err
+	}
+
+	if rateCard == nil {
+		return nil, errors.New("rate_card_not_found")
+	}
+
+	return rateCard, nil
+}
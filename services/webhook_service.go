@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gotidy/ptr"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+)
+
+const (
+	WebhookHeaderID        = "X-Webhook-Id"
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+
+	webhookResponseBodyLimit = 1024
+)
+
+type WebhookEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookServiceInterface interface {
+	Publish(ctx context.Context, eventType string, data interface{}) error
+	Redeliver(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookRepository repositories.WebhookRepositoryInterface
+	client            *http.Client
+	maxAttempts       int
+	backoff           time.Duration
+}
+
+func NewWebhookService(
+	webhookRepository repositories.WebhookRepositoryInterface,
+	client *http.Client,
+	maxAttempts int,
+	backoff time.Duration,
+) WebhookServiceInterface {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &webhookService{
+		webhookRepository: webhookRepository,
+		client:            client,
+		maxAttempts:       maxAttempts,
+		backoff:           backoff,
+	}
+}
+
+// SignWebhookPayload returns the signature partners use to verify a delivery,
+// computed as HMAC-SHA256 over "<timestamp>.<body>" with the subscription secret.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookService) Publish(ctx context.Context, eventType string, data interface{}) error {
+	event := WebhookEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	page := 1
+	for {
+		subscriptions, _, err := s.webhookRepository.List(ctx, page, 100, "id", repositories.WebhookRepositoryFilter{
+			Active:    ptr.Of(true),
+			EventType: ptr.Of(eventType),
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(*subscriptions) == 0 {
+			break
+		}
+
+		for _, subscription := range *subscriptions {
+			// Deliveries outlive the request that triggered them
+			go s.deliver(context.Background(), subscription, event.ID, eventType, payload)
+		}
+
+		page++
+	}
+
+	return nil
+}
+
+// Redeliver sends the event of the delivery again, numbered after the latest
+// attempt for the event so redelivering an older delivery repeats no number.
+func (s *webhookService) Redeliver(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	last, err := s.webhookRepository.LastDeliveryAttempt(ctx, delivery.SubscriptionID, delivery.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attempt(ctx, delivery.Subscription, delivery.EventID, delivery.EventType, delivery.Payload, last+1)
+}
+
+func (s *webhookService) deliver(ctx context.Context, subscription models.WebhookSubscription, eventID uuid.UUID, eventType string, payload json.RawMessage) {
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		delivery, err := s.attempt(ctx, &subscription, eventID, eventType, payload, attempt)
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		if delivery != nil && delivery.Success {
+			return
+		}
+
+		if attempt < s.maxAttempts {
+			time.Sleep(s.backoff * time.Duration(1<<(attempt-1)))
+		}
+	}
+}
+
+func (s *webhookService) attempt(ctx context.Context, subscription *models.WebhookSubscription, eventID uuid.UUID, eventType string, payload json.RawMessage, attempt int) (*models.WebhookDelivery, error) {
+	delivery := models.NewWebhookDelivery(subscription, eventID, eventType, payload, attempt)
+
+	started := time.Now()
+	statusCode, body, err := s.send(ctx, subscription, eventID, eventType, payload)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.StatusCode = statusCode
+	delivery.ResponseBody = body
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	delivery.Success = err == nil && statusCode >= 200 && statusCode < 300
+
+	return s.webhookRepository.SaveDelivery(ctx, delivery)
+}
+
+func (s *webhookService) send(ctx context.Context, subscription *models.WebhookSubscription, eventID uuid.UUID, eventType string, payload json.RawMessage) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderID, eventID.String())
+	req.Header.Set(WebhookHeaderEvent, eventType)
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(subscription.Secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+)
+
+// fakeWebhookRepository keeps deliveries in memory, the methods the service
+// does not call are left to the embedded nil interface.
+type fakeWebhookRepository struct {
+	repositories.WebhookRepositoryInterface
+
+	mu          sync.Mutex
+	deliveries  []models.WebhookDelivery
+	lastAttempt int
+}
+
+func (r *fakeWebhookRepository) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deliveries = append(r.deliveries, *delivery)
+	return delivery, nil
+}
+
+func (r *fakeWebhookRepository) LastDeliveryAttempt(ctx context.Context, subscriptionID uint, eventID uuid.UUID) (int, error) {
+	return r.lastAttempt, nil
+}
+
+type receivedWebhook struct {
+	at      time.Time
+	headers http.Header
+	body    []byte
+}
+
+// webhookReceiver answers the statuses in turn, the last one repeating
+func webhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, func() []receivedWebhook) {
+	var mu sync.Mutex
+	var received []receivedWebhook
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+
+		mu.Lock()
+		received = append(received, receivedWebhook{at: time.Now(), headers: r.Header.Clone(), body: body})
+		status := statuses[min(len(received), len(statuses))-1]
+		mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedWebhook {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]receivedWebhook{}, received...)
+	}
+}
+
+func TestWebhookDeliverySignature(t *testing.T) {
+	server, received := webhookReceiver(t, http.StatusOK)
+	repository := &fakeWebhookRepository{}
+	service := NewWebhookService(repository, server.Client(), 3, time.Millisecond).(*webhookService)
+
+	subscription := models.WebhookSubscription{ID: 1, URL: server.URL, Secret: "whsec_test"}
+	eventID := uuid.New()
+	payload := json.RawMessage(`{"type":"loan.approved"}`)
+
+	service.deliver(context.Background(), subscription, eventID, models.WebhookEventLoanApproved, payload)
+
+	requests := received()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+
+	request := requests[0]
+	timestamp, err := strconv.ParseInt(request.headers.Get(WebhookHeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+
+	want := SignWebhookPayload(subscription.Secret, timestamp, request.body)
+	if got := request.headers.Get(WebhookHeaderSignature); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	if got := request.headers.Get(WebhookHeaderID); got != eventID.String() {
+		t.Errorf("event id header = %q, want %q", got, eventID)
+	}
+
+	if got := request.headers.Get(WebhookHeaderEvent); got != models.WebhookEventLoanApproved {
+		t.Errorf("event header = %q, want %q", got, models.WebhookEventLoanApproved)
+	}
+
+	if string(request.body) != string(payload) {
+		t.Errorf("body = %s, want %s", request.body, payload)
+	}
+
+	if len(repository.deliveries) != 1 || !repository.deliveries[0].Success {
+		t.Errorf("deliveries = %+v, want one successful delivery", repository.deliveries)
+	}
+}
+
+func TestWebhookDeliveryRetries(t *testing.T) {
+	const backoff = 20 * time.Millisecond
+
+	tests := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		wantSuccess []bool
+	}{
+		{
+			name:        "succeeds on first attempt",
+			statuses:    []int{http.StatusOK},
+			maxAttempts: 3,
+			wantSuccess: []bool{true},
+		},
+		{
+			name:        "retries until the receiver accepts",
+			statuses:    []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent},
+			maxAttempts: 3,
+			wantSuccess: []bool{false, false, true},
+		},
+		{
+			name:        "gives up after max attempts",
+			statuses:    []int{http.StatusServiceUnavailable},
+			maxAttempts: 2,
+			wantSuccess: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := webhookReceiver(t, tt.statuses...)
+			repository := &fakeWebhookRepository{}
+			service := NewWebhookService(repository, server.Client(), tt.maxAttempts, backoff).(*webhookService)
+
+			subscription := models.WebhookSubscription{ID: 1, URL: server.URL, Secret: "whsec_test"}
+			service.deliver(context.Background(), subscription, uuid.New(), models.WebhookEventLoanApproved, json.RawMessage(`{}`))
+
+			if len(repository.deliveries) != len(tt.wantSuccess) {
+				t.Fatalf("got %d deliveries, want %d", len(repository.deliveries), len(tt.wantSuccess))
+			}
+
+			for index, delivery := range repository.deliveries {
+				if delivery.Attempt != index+1 {
+					t.Errorf("delivery %d attempt = %d, want %d", index, delivery.Attempt, index+1)
+				}
+
+				if delivery.Success != tt.wantSuccess[index] {
+					t.Errorf("delivery %d success = %v, want %v", index, delivery.Success, tt.wantSuccess[index])
+				}
+			}
+
+			// The wait doubles after every failed attempt
+			requests := received()
+			for index := 1; index < len(requests); index++ {
+				wait := backoff * time.Duration(1<<(index-1))
+				if gap := requests[index].at.Sub(requests[index-1].at); gap < wait {
+					t.Errorf("attempt %d came %v after the previous one, want at least %v", index+1, gap, wait)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookRedeliverNumbersAfterLatestAttempt(t *testing.T) {
+	server, _ := webhookReceiver(t, http.StatusOK)
+	repository := &fakeWebhookRepository{lastAttempt: 4}
+	service := NewWebhookService(repository, server.Client(), 3, time.Millisecond)
+
+	subscription := &models.WebhookSubscription{ID: 1, URL: server.URL, Secret: "whsec_test"}
+	older := models.NewWebhookDelivery(subscription, uuid.New(), models.WebhookEventLoanApproved, json.RawMessage(`{}`), 1)
+
+	delivery, err := service.Redeliver(context.Background(), older)
+	if err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+
+	if delivery.Attempt != 5 {
+		t.Errorf("attempt = %d, want 5", delivery.Attempt)
+	}
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+const auditVerifyBatchSize = 500
+
+type AuditLogUsecaseInterface interface {
+	List(ctx context.Context, dto *dto_request.AuditLogListDTO) (*[]models.AuditLog, int, error)
+	Verify(ctx context.Context) (*models.AuditVerification, error)
+}
+
+type auditLogUsecase struct {
+	auditLogRepository repositories.AuditLogRepositoryInterface
+	loanRepository     repositories.LoanRepositoryInterface
+}
+
+func NewAuditLogUsecase(
+	auditLogRepository repositories.AuditLogRepositoryInterface,
+	loanRepository repositories.LoanRepositoryInterface,
+) AuditLogUsecaseInterface {
+	return &auditLogUsecase{
+		auditLogRepository: auditLogRepository,
+		loanRepository:     loanRepository,
+	}
+}
+
+func (u *auditLogUsecase) List(ctx context.Context, dto *dto_request.AuditLogListDTO) (*[]models.AuditLog, int, error) {
+	filter := repositories.AuditLogRepositoryFilter{
+		Action:     utils.ParseStringParam(dto.Action),
+		EntityType: utils.ParseStringParam(dto.EntityType),
+		EntityID:   utils.ParseStringParam(dto.EntityID),
+	}
+
+	if dto.ActorID != "" {
+		actorID, err := strconv.ParseUint(dto.ActorID, 10, 64)
+		if err != nil {
+			return nil, 0, errors.New("invalid_filter")
+		}
+		id := uint(actorID)
+		filter.ActorID = &id
+	}
+
+	if dto.LoanID != "" {
+		loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if loan == nil {
+			return nil, 0, errors.New("loan_not_found")
+		}
+		filter.LoanID = &loan.ID
+	}
+
+	var err error
+	if filter.From, err = utils.ParseTimeParam(dto.From); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.To, err = utils.ParseTimeParam(dto.To); err != nil {
+		return nil, 0, err
+	}
+
+	sort := dto.Sort
+	if sort == "" {
+		sort = "-id"
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.auditLogRepository.List(ctx, page, perPage, sort, filter)
+}
+
+// Verify recomputes the whole chain and stops at the first entry that was
+// altered, or that no longer follows the entry before it.
+func (u *auditLogUsecase) Verify(ctx context.Context) (*models.AuditVerification, error) {
+	verification := &models.AuditVerification{Intact: true}
+
+	var afterID uint
+	prevHash := ""
+	for {
+		logs, err := u.auditLogRepository.ListAfter(ctx, afterID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, log := range logs {
+			verification.Checked++
+			if !log.Intact(prevHash) {
+				verification.Intact = false
+				verification.BrokenAt = &log.UUID
+				return verification, nil
+			}
+
+			prevHash = log.Hash
+			afterID = log.ID
+		}
+
+		if len(logs) < auditVerifyBatchSize {
+			return verification, nil
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type AutoInvestPlanUsecaseInterface interface {
+	Create(ctx context.Context, dto *dto_request.CreateAutoInvestPlanDTO) (*models.AutoInvestPlan, error)
+	Update(ctx context.Context, dto *dto_request.UpdateAutoInvestPlanDTO) (*models.AutoInvestPlan, error)
+	Delete(ctx context.Context, dto *dto_request.DeleteAutoInvestPlanDTO) error
+	List(ctx context.Context, dto *dto_request.AutoInvestPlanListDTO) (*[]models.AutoInvestPlan, int, error)
+}
+
+type autoInvestPlanUsecase struct {
+	autoInvestPlanRepository repositories.AutoInvestPlanRepositoryInterface
+	auditService             services.AuditServiceInterface
+}
+
+func NewAutoInvestPlanUsecase(autoInvestPlanRepository repositories.AutoInvestPlanRepositoryInterface, auditService services.AuditServiceInterface) AutoInvestPlanUsecaseInterface {
+	return &autoInvestPlanUsecase{
+		autoInvestPlanRepository: autoInvestPlanRepository,
+		auditService:             auditService,
+	}
+}
+
+func (u *autoInvestPlanUsecase) Create(ctx context.Context, dto *dto_request.CreateAutoInvestPlanDTO) (*models.AutoInvestPlan, error) {
+	plan := models.NewAutoInvestPlan(dto.InvestorID, dto.Budget, dto.AmountPerLoan)
+	plan.MinRate = dto.MinRate
+	plan.MaxRate = dto.MaxRate
+	plan.Tenors = dto.Tenors
+	plan.RiskGrades = dto.RiskGrades
+	plan.Regions = dto.Regions
+
+	if err := validateAutoInvestPlan(plan); err != nil {
+		return nil, err
+	}
+
+	plan, err := u.autoInvestPlanRepository.Save(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, autoInvestPlanAuditEntry(models.AuditActionAutoInvestPlanCreate, plan, nil))
+
+	return plan, nil
+}
+
+func (u *autoInvestPlanUsecase) Update(ctx context.Context, dto *dto_request.UpdateAutoInvestPlanDTO) (*models.AutoInvestPlan, error) {
+	plan, err := u.ownedPlan(ctx, dto.AutoInvestPlanID, dto.InvestorID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := models.AuditSnapshot(dto_response.AutoInvestPlanDetailResponse(plan))
+
+	if dto.Budget != nil {
+		plan.Budget = *dto.Budget
+	}
+
+	if dto.AmountPerLoan != nil {
+		plan.AmountPerLoan = *dto.AmountPerLoan
+	}
+
+	if dto.MinRate != nil {
+		plan.MinRate = dto.MinRate
+	}
+
+	if dto.MaxRate != nil {
+		plan.MaxRate = dto.MaxRate
+	}
+
+	if dto.Tenors != nil {
+		plan.Tenors = dto.Tenors
+	}
+
+	if dto.RiskGrades != nil {
+		plan.RiskGrades = dto.RiskGrades
+	}
+
+	if dto.Regions != nil {
+		plan.Regions = dto.Regions
+	}
+
+	if dto.Active != nil {
+		plan.Active = *dto.Active
+	}
+
+	if err := validateAutoInvestPlan(plan); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plan.UpdatedAt = &now
+
+	plan, err = u.autoInvestPlanRepository.Save(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, autoInvestPlanAuditEntry(models.AuditActionAutoInvestPlanUpdate, plan, before))
+
+	return plan, nil
+}
+
+func (u *autoInvestPlanUsecase) Delete(ctx context.Context, dto *dto_request.DeleteAutoInvestPlanDTO) error {
+	plan, err := u.ownedPlan(ctx, dto.AutoInvestPlanID, dto.InvestorID)
+	if err != nil {
+		return err
+	}
+
+	err = u.autoInvestPlanRepository.Delete(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.auditService, &models.AuditEntry{
+		Action:     models.AuditActionAutoInvestPlanDelete,
+		EntityType: models.AuditEntityAutoInvestPlan,
+		EntityID:   plan.UUID.String(),
+		Before:     dto_response.AutoInvestPlanDetailResponse(plan),
+	})
+
+	return nil
+}
+
+func (u *autoInvestPlanUsecase) List(ctx context.Context, dto *dto_request.AutoInvestPlanListDTO) (*[]models.AutoInvestPlan, int, error) {
+	filter := repositories.AutoInvestPlanRepositoryFilter{
+		InvestorID: &dto.InvestorID,
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.autoInvestPlanRepository.List(ctx, page, perPage, "-created_at", filter)
+}
+
+// ownedPlan hides plans of other investors behind the same not found error
+func (u *autoInvestPlanUsecase) ownedPlan(ctx context.Context, planID string, investorID uint) (*models.AutoInvestPlan, error) {
+	plan, err := u.autoInvestPlanRepository.Detail(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan == nil || plan.InvestorID != investorID {
+		return nil, errors.New("auto_invest_plan_not_found")
+	}
+
+	return plan, nil
+}
+
+func autoInvestPlanAuditEntry(action string, plan *models.AutoInvestPlan, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityAutoInvestPlan,
+		EntityID:   plan.UUID.String(),
+		Before:     before,
+		After:      dto_response.AutoInvestPlanDetailResponse(plan),
+	}
+}
+
+func validateAutoInvestPlan(plan *models.AutoInvestPlan) error {
+	if plan.Budget <= 0 || plan.AmountPerLoan <= 0 || plan.AmountPerLoan > plan.Budget {
+		return errors.New("invalid_auto_invest_plan")
+	}
+
+	// A lowered budget may not drop under what the plan already invested
+	if plan.Budget < plan.InvestedAmount {
+		return errors.New("invalid_auto_invest_plan")
+	}
+
+	if plan.MinRate != nil && plan.MaxRate != nil && *plan.MinRate > *plan.MaxRate {
+		return errors.New("invalid_auto_invest_plan")
+	}
+
+	for _, tenor := range plan.Tenors {
+		if tenor <= 0 {
+			return errors.New("invalid_auto_invest_plan")
+		}
+	}
+
+	for _, grade := range plan.RiskGrades {
+		if !models.RiskGrades[grade] {
+			return errors.New("invalid_risk_grade")
+		}
+	}
+
+	return nil
+}
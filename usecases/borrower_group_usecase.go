@@ -0,0 +1,257 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type BorrowerGroupUsecaseInterface interface {
+	Create(ctx context.Context, dto *dto_request.CreateBorrowerGroupDTO) (*models.BorrowerGroup, error)
+	List(ctx context.Context, dto *dto_request.BorrowerGroupListDTO) (*[]models.BorrowerGroup, int, error)
+	Detail(ctx context.Context, dto *dto_request.BorrowerGroupDetailDTO) (*models.BorrowerGroup, error)
+	AddMember(ctx context.Context, dto *dto_request.BorrowerGroupMemberDTO) (*models.BorrowerGroup, error)
+	RemoveMember(ctx context.Context, dto *dto_request.BorrowerGroupMemberDTO) (*models.BorrowerGroup, error)
+	ChangeLeader(ctx context.Context, dto *dto_request.BorrowerGroupMemberDTO) (*models.BorrowerGroup, error)
+}
+
+type borrowerGroupUsecase struct {
+	borrowerGroupRepository repositories.BorrowerGroupRepositoryInterface
+	userRepository          repositories.UserRepositoryInterface
+	loanRepository          repositories.LoanRepositoryInterface
+	auditService            services.AuditServiceInterface
+}
+
+func NewBorrowerGroupUsecase(
+	borrowerGroupRepository repositories.BorrowerGroupRepositoryInterface,
+	userRepository repositories.UserRepositoryInterface,
+	loanRepository repositories.LoanRepositoryInterface,
+	auditService services.AuditServiceInterface,
+) BorrowerGroupUsecaseInterface {
+	return &borrowerGroupUsecase{
+		borrowerGroupRepository: borrowerGroupRepository,
+		userRepository:          userRepository,
+		loanRepository:          loanRepository,
+		auditService:            auditService,
+	}
+}
+
+// openLoanStatuses are the loans a borrower still owes or may still get
+var openLoanStatuses = []models.LoanStatus{
+	models.LoanStatusProposed,
+	models.LoanStatusPendingApproval,
+	models.LoanStatusApproved,
+	models.LoanStatusInvested,
+	models.LoanStatusDisbursing,
+	models.LoanStatusDisbursed,
+	models.LoanStatusDelinquent,
+	models.LoanStatusDefaulted,
+}
+
+// Create sets up a group run by the field officer creating it, an admin
+// names the field officer instead.
+func (u *borrowerGroupUsecase) Create(ctx context.Context, dto *dto_request.CreateBorrowerGroupDTO) (*models.BorrowerGroup, error) {
+	fieldOfficerID := dto.FieldOfficerID
+	if dto.Role == models.RoleFieldOfficer {
+		fieldOfficerID = dto.UserID
+	}
+
+	fieldOfficer, err := u.userRepository.Detail(ctx, fieldOfficerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fieldOfficer == nil || fieldOfficer.Role != models.RoleFieldOfficer {
+		return nil, errors.New("invalid_borrower_group")
+	}
+
+	for _, memberID := range dto.MemberIDs {
+		if err := u.checkBorrower(ctx, memberID); err != nil {
+			return nil, err
+		}
+	}
+
+	group, err := models.NewBorrowerGroup(dto.Name, dto.Region, fieldOfficerID, dto.LeaderID, dto.MemberIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err = u.borrowerGroupRepository.Save(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, borrowerGroupAuditEntry(models.AuditActionBorrowerGroupCreate, group, nil))
+
+	return group, nil
+}
+
+// List shows a field officer their own groups, an admin every group
+func (u *borrowerGroupUsecase) List(ctx context.Context, dto *dto_request.BorrowerGroupListDTO) (*[]models.BorrowerGroup, int, error) {
+	filter := repositories.BorrowerGroupRepositoryFilter{
+		Region: utils.ParseStringParam(dto.Region),
+	}
+
+	if dto.Role == models.RoleFieldOfficer {
+		filter.FieldOfficerID = &dto.UserID
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.borrowerGroupRepository.List(ctx, page, perPage, "name", filter)
+}
+
+func (u *borrowerGroupUsecase) Detail(ctx context.Context, dto *dto_request.BorrowerGroupDetailDTO) (*models.BorrowerGroup, error) {
+	return borrowerGroupFor(ctx, u.borrowerGroupRepository, dto.GroupID, dto.UserID, dto.Role)
+}
+
+func (u *borrowerGroupUsecase) AddMember(ctx context.Context, dto *dto_request.BorrowerGroupMemberDTO) (*models.BorrowerGroup, error) {
+	group, err := borrowerGroupFor(ctx, u.borrowerGroupRepository, dto.GroupID, dto.UserID, dto.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.HasMember(dto.BorrowerID) {
+		return nil, errors.New("borrower_already_in_group")
+	}
+
+	if err := u.checkBorrower(ctx, dto.BorrowerID); err != nil {
+		return nil, err
+	}
+
+	member := models.BorrowerGroupMember{
+		GroupID:    group.ID,
+		BorrowerID: dto.BorrowerID,
+		CreatedAt:  time.Now(),
+	}
+
+	before := models.AuditSnapshot(dto_response.BorrowerGroupDetailResponse(group))
+
+	err = u.borrowerGroupRepository.AddMember(ctx, &member)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reloadGroup(ctx, models.AuditActionBorrowerGroupAddMember, dto.GroupID, before)
+}
+
+// RemoveMember lets a borrower leave the group. The leader hands the group
+// over first, and a member still liable for a group loan cannot leave.
+func (u *borrowerGroupUsecase) RemoveMember(ctx context.Context, dto *dto_request.BorrowerGroupMemberDTO) (*models.BorrowerGroup, error) {
+	group, err := borrowerGroupFor(ctx, u.borrowerGroupRepository, dto.GroupID, dto.UserID, dto.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	member := group.Member(dto.BorrowerID)
+	if member == nil {
+		return nil, errors.New("borrower_not_group_member")
+	}
+
+	if group.LeaderID == dto.BorrowerID {
+		return nil, errors.New("group_leader_cannot_leave")
+	}
+
+	count, err := u.loanRepository.Count(ctx, repositories.LoanRepositoryFilter{
+		BorrowerID: &dto.BorrowerID,
+		GroupID:    &group.ID,
+		Statuses:   openLoanStatuses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, errors.New("group_member_has_open_loan")
+	}
+
+	before := models.AuditSnapshot(dto_response.BorrowerGroupDetailResponse(group))
+
+	err = u.borrowerGroupRepository.RemoveMember(ctx, member)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reloadGroup(ctx, models.AuditActionBorrowerGroupRemoveMember, dto.GroupID, before)
+}
+
+func (u *borrowerGroupUsecase) ChangeLeader(ctx context.Context, dto *dto_request.BorrowerGroupMemberDTO) (*models.BorrowerGroup, error) {
+	group, err := borrowerGroupFor(ctx, u.borrowerGroupRepository, dto.GroupID, dto.UserID, dto.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	before := models.AuditSnapshot(dto_response.BorrowerGroupDetailResponse(group))
+
+	err = group.ChangeLeader(dto.BorrowerID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err = u.borrowerGroupRepository.Save(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, borrowerGroupAuditEntry(models.AuditActionBorrowerGroupChangeLeader, group, before))
+
+	return group, nil
+}
+
+// reloadGroup reads back a group whose members changed and records it
+func (u *borrowerGroupUsecase) reloadGroup(ctx context.Context, action string, groupID string, before json.RawMessage) (*models.BorrowerGroup, error) {
+	group, err := u.borrowerGroupRepository.Detail(ctx, groupID)
+	if err != nil || group == nil {
+		return group, err
+	}
+
+	recordAudit(ctx, u.auditService, borrowerGroupAuditEntry(action, group, before))
+
+	return group, nil
+}
+
+func (u *borrowerGroupUsecase) checkBorrower(ctx context.Context, borrowerID uint) error {
+	borrower, err := u.userRepository.Detail(ctx, borrowerID)
+	if err != nil {
+		return err
+	}
+
+	if borrower == nil || borrower.Role != models.RoleBorower {
+		return errors.New("borrower_not_found")
+	}
+
+	return nil
+}
+
+func borrowerGroupAuditEntry(action string, group *models.BorrowerGroup, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityBorrowerGroup,
+		EntityID:   group.UUID.String(),
+		Before:     before,
+		After:      dto_response.BorrowerGroupDetailResponse(group),
+	}
+}
+
+// borrowerGroupFor loads a group the user may manage, a field officer only
+// manages the groups they run.
+func borrowerGroupFor(ctx context.Context, borrowerGroupRepository repositories.BorrowerGroupRepositoryInterface, groupID string, userID uint, role models.UserRole) (*models.BorrowerGroup, error) {
+	group, err := borrowerGroupRepository.Detail(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if group == nil || (role == models.RoleFieldOfficer && group.FieldOfficerID != userID) {
+		return nil, errors.New("borrower_group_not_found")
+	}
+
+	return group, nil
+}
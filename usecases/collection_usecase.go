@@ -0,0 +1,458 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type CollectionUsecaseInterface interface {
+	AssessDelinquency(ctx context.Context, asOf time.Time) (int, error)
+	ApplyRepayment(ctx context.Context, loan *models.Loan, payment *models.Payment) error
+	WorkList(ctx context.Context, dto *dto_request.CollectionListDTO) (*[]models.Loan, int, error)
+	WriteOff(ctx context.Context, dto *dto_request.WriteOffLoanDTO) (*models.WriteOff, error)
+	PayoffQuote(ctx context.Context, dto *dto_request.PayoffQuoteDTO) (*models.PayoffQuote, error)
+	Settle(ctx context.Context, dto *dto_request.SettleLoanDTO) (*models.Loan, error)
+}
+
+type collectionUsecase struct {
+	loanRepository        repositories.LoanRepositoryInterface
+	installmentRepository repositories.InstallmentRepositoryInterface
+	investmentRepository  repositories.InvestmentRepositoryInterface
+	writeOffRepository    repositories.WriteOffRepositoryInterface
+	paymentRepository     repositories.PaymentRepositoryInterface
+	payoffRepository      repositories.PayoffRepositoryInterface
+	webhookService        services.WebhookServiceInterface
+	auditService          services.AuditServiceInterface
+	paymentGateway        payment_services.PaymentGateway
+	delinquencyPolicy     models.DelinquencyPolicy
+}
+
+func NewCollectionUsecase(
+	loanRepository repositories.LoanRepositoryInterface,
+	installmentRepository repositories.InstallmentRepositoryInterface,
+	investmentRepository repositories.InvestmentRepositoryInterface,
+	writeOffRepository repositories.WriteOffRepositoryInterface,
+	paymentRepository repositories.PaymentRepositoryInterface,
+	payoffRepository repositories.PayoffRepositoryInterface,
+	webhookService services.WebhookServiceInterface,
+	auditService services.AuditServiceInterface,
+	paymentGateway payment_services.PaymentGateway,
+	delinquencyPolicy models.DelinquencyPolicy,
+) CollectionUsecaseInterface {
+	return &collectionUsecase{
+		loanRepository:        loanRepository,
+		installmentRepository: installmentRepository,
+		investmentRepository:  investmentRepository,
+		writeOffRepository:    writeOffRepository,
+		paymentRepository:     paymentRepository,
+		payoffRepository:      payoffRepository,
+		webhookService:        webhookService,
+		auditService:          auditService,
+		paymentGateway:        paymentGateway,
+		delinquencyPolicy:     delinquencyPolicy,
+	}
+}
+
+// collectionLoanStatuses are the loans the borrower is repaying
+var collectionLoanStatuses = []models.LoanStatus{
+	models.LoanStatusDisbursed,
+	models.LoanStatusDelinquent,
+	models.LoanStatusDefaulted,
+}
+
+// AssessDelinquency is the daily run over every repaying loan. It returns the
+// number of loans that changed status.
+func (u *collectionUsecase) AssessDelinquency(ctx context.Context, asOf time.Time) (int, error) {
+	changed := 0
+	cursor := ""
+	for {
+		loans, _, nextCursor, err := u.loanRepository.List(ctx, 1, 50, &cursor, "created_at", repositories.LoanRepositoryFilter{
+			Statuses: collectionLoanStatuses,
+		})
+		if err != nil {
+			return changed, err
+		}
+
+		for _, loan := range *loans {
+			statusChanged, err := u.assess(ctx, &loan, asOf)
+			if err != nil {
+				return changed, err
+			}
+
+			if statusChanged {
+				changed++
+			}
+		}
+
+		if nextCursor == "" {
+			return changed, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// ApplyRepayment allocates a collection to the oldest installments first and
+// reassesses the loan right away, so a borrower catching up is current again
+// without waiting for the next daily run. The repayment clearing the last
+// installment closes the loan and pays the investors out.
+func (u *collectionUsecase) ApplyRepayment(ctx context.Context, loan *models.Loan, payment *models.Payment) error {
+	installments, err := u.installmentRepository.SaveRepayment(ctx, payment)
+	if err != nil {
+		if err.Error() == "duplicate_payment" {
+			return nil
+		}
+
+		return err
+	}
+
+	recordAudit(ctx, u.auditService, &models.AuditEntry{
+		Action:     models.AuditActionLoanRepay,
+		EntityType: models.AuditEntityPayment,
+		EntityID:   payment.UUID.String(),
+		LoanID:     &loan.ID,
+		After:      dto_response.PaymentDetailResponse(payment),
+	})
+
+	if len(installments) == 0 || !loan.Repaying() {
+		return nil
+	}
+
+	// Repayments are allocated oldest first, the last installment is paid
+	// only once every installment is
+	if installments[len(installments)-1].Paid() {
+		before := loanSnapshot(loan)
+		loan.Repay()
+
+		err = u.closeRepaidLoan(ctx, loan, nil, nil)
+		if err != nil {
+			if err.Error() == "loan_already_closed" {
+				return nil
+			}
+
+			return err
+		}
+
+		recordAudit(ctx, u.auditService, loanAuditEntry(models.AuditActionLoanRepay, loan, before))
+		return nil
+	}
+
+	_, err = u.assess(ctx, loan, time.Now())
+	return err
+}
+
+func (u *collectionUsecase) WorkList(ctx context.Context, dto *dto_request.CollectionListDTO) (*[]models.Loan, int, error) {
+	filter := repositories.LoanRepositoryFilter{
+		Statuses: []models.LoanStatus{models.LoanStatusDelinquent, models.LoanStatusDefaulted},
+	}
+
+	if dto.Bucket != "" {
+		if !models.DelinquencyBuckets[dto.Bucket] {
+			return nil, 0, errors.New("invalid_filter")
+		}
+		filter.Bucket = &dto.Bucket
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	loans, count, _, err := u.loanRepository.List(ctx, page, perPage, nil, "-days_past_due", filter)
+
+	return loans, count, err
+}
+
+// WriteOff closes a delinquent or defaulted loan as a loss and books each
+// investor's share of the principal still owed.
+func (u *collectionUsecase) WriteOff(ctx context.Context, dto *dto_request.WriteOffLoanDTO) (*models.WriteOff, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil {
+		return nil, errors.New("loan_not_found")
+	}
+
+	if loan.Status != models.LoanStatusDelinquent && loan.Status != models.LoanStatusDefaulted {
+		return nil, errors.New("only_delinquent_loan_allowed")
+	}
+
+	before := loanSnapshot(loan)
+	writeOff, err := u.writeOffRepository.Save(ctx, loan, dto.SupervisorID, dto.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, loanAuditEntry(models.AuditActionLoanWriteOff, loan, before))
+
+	publishLoanWebhook(ctx, u.webhookService, models.WebhookEventLoanWrittenOff, loan)
+
+	return writeOff, nil
+}
+
+// PayoffQuote prices the early settlement of a repaying loan on the given
+// date, today when none is given. A borrower only gets quotes for their own
+// loans.
+func (u *collectionUsecase) PayoffQuote(ctx context.Context, dto *dto_request.PayoffQuoteDTO) (*models.PayoffQuote, error) {
+	year, month, day := time.Now().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	date := today
+	if dto.Date != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, dto.Date, time.Local)
+		if err != nil || parsed.Before(today) {
+			return nil, errors.New("invalid_payoff_date")
+		}
+		date = parsed
+	}
+
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil || (dto.Role == models.RoleBorower && loan.BorrowerID != dto.UserID) {
+		return nil, errors.New("loan_not_found")
+	}
+
+	_, quote, err := u.quote(ctx, loan, date)
+	return quote, err
+}
+
+// Settle closes a loan early. The borrower pays exactly today's payoff quote,
+// the remaining installments are paid with the interest not accrued waived,
+// and the investors get their final payout. A charge that fails leaves the
+// loan untouched.
+func (u *collectionUsecase) Settle(ctx context.Context, dto *dto_request.SettleLoanDTO) (*models.Loan, error) {
+	if !payment_services.PaymentMethods[dto.Method] {
+		return nil, errors.New("invalid_payment_method")
+	}
+
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil || loan.BorrowerID != dto.BorrowerID {
+		return nil, errors.New("loan_not_found")
+	}
+
+	installments, quote, err := u.quote(ctx, loan, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if math.Abs(dto.Amount-quote.Total) >= 0.005 {
+		return nil, errors.New("payoff_amount_mismatch")
+	}
+
+	// The payment is stored pending first so a charge is always traceable, and
+	// claiming the loan with it keeps a concurrent settlement from charging too
+	payment := models.NewPayment(loan.ID, models.PaymentDirectionCollection, dto.Method, quote.Total)
+	err = u.payoffRepository.Claim(ctx, loan, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.paymentGateway.Charge(ctx, &payment_services.Charge{
+		Reference:  payment.Reference,
+		CustomerID: loan.BorrowerID,
+		Amount:     payment.Amount,
+		Method:     payment.Method,
+	})
+	if err != nil {
+		payment.Fail(err.Error())
+		if _, err := u.paymentRepository.Save(ctx, payment); err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("payoff_charge_failed")
+	}
+
+	payment.GatewayReference = result.GatewayReference
+	if result.Status != payment_services.PaymentStatusSuccess {
+		payment.Fail(result.FailureReason)
+		if _, err := u.paymentRepository.Save(ctx, payment); err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("payoff_charge_failed")
+	}
+
+	before := loanSnapshot(loan)
+	payment.Complete()
+	quote.Settle(installments, *payment.CompletedAt)
+	loan.Settle(quote.EarlySettlementFee)
+
+	err = u.closeRepaidLoan(ctx, loan, payment, installments)
+	if err != nil {
+		return nil, u.refund(ctx, payment, err)
+	}
+
+	recordAudit(ctx, u.auditService, loanAuditEntry(models.AuditActionLoanSettle, loan, before))
+
+	return loan, nil
+}
+
+// refund gives the borrower back a settlement charge the loan could not be
+// closed with, the payment is kept as failed with the reason it was refused.
+// A refund the gateway does not take leaves the payment pending, which also
+// blocks another settlement until it is sorted out.
+func (u *collectionUsecase) refund(ctx context.Context, payment *models.Payment, cause error) error {
+	_, err := u.paymentGateway.Refund(ctx, &payment_services.Refund{
+		Reference:        payment.Reference,
+		GatewayReference: payment.GatewayReference,
+		Amount:           payment.Amount,
+	})
+	if err != nil {
+		fmt.Printf("refund of payment %s failed: %v\n", payment.Reference, err)
+		return err
+	}
+
+	payment.Fail(cause.Error())
+	_, err = u.paymentRepository.Save(ctx, payment)
+	if err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// quote loads the active schedule of a repaying loan and prices its payoff
+func (u *collectionUsecase) quote(ctx context.Context, loan *models.Loan, date time.Time) ([]models.Installment, *models.PayoffQuote, error) {
+	if !loan.Repaying() {
+		return nil, nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	installments, err := u.installmentRepository.ListByLoan(ctx, loan.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(installments) == 0 {
+		return nil, nil, errors.New("repayment_schedule_not_found")
+	}
+
+	return installments, models.NewPayoffQuote(loan, installments, date), nil
+}
+
+// closeRepaidLoan saves a loan the borrower paid back in full together with
+// the final payout of every investor. Installments are the settled active
+// schedule, nil when it is already saved.
+func (u *collectionUsecase) closeRepaidLoan(ctx context.Context, loan *models.Loan, payment *models.Payment, installments []models.Installment) error {
+	history, err := u.installmentRepository.ListHistory(ctx, loan.ID)
+	if err != nil {
+		return err
+	}
+
+	settled := map[uint]models.Installment{}
+	for _, installment := range installments {
+		settled[installment.ID] = installment
+	}
+
+	for index, installment := range history {
+		if installment, ok := settled[installment.ID]; ok {
+			history[index] = installment
+		}
+	}
+
+	investments, err := u.loanInvestments(ctx, loan)
+	if err != nil {
+		return err
+	}
+
+	payouts := models.InvestorPayouts(loan, history, investments)
+
+	err = u.payoffRepository.Save(ctx, loan, payment, installments, payouts)
+	if err != nil {
+		return err
+	}
+
+	publishLoanWebhook(ctx, u.webhookService, models.WebhookEventLoanRepaid, loan)
+
+	return nil
+}
+
+func (u *collectionUsecase) loanInvestments(ctx context.Context, loan *models.Loan) ([]models.Investment, error) {
+	investments := []models.Investment{}
+	cursor := ""
+	for {
+		page, _, nextCursor, err := u.investmentRepository.List(ctx, 1, 100, &cursor, "id", repositories.InvestmentRepositoryFilter{
+			LoanID: &loan.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		investments = append(investments, *page...)
+
+		if nextCursor == "" {
+			return investments, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// assess applies the delinquency policy to one loan and saves what changed.
+// Loans disbursed before schedules were tracked have no installments and are
+// left alone.
+func (u *collectionUsecase) assess(ctx context.Context, loan *models.Loan, asOf time.Time) (bool, error) {
+	installments, err := u.installmentRepository.ListByLoan(ctx, loan.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(installments) == 0 {
+		return false, nil
+	}
+
+	before := loanSnapshot(loan)
+	status, daysPastDue, overdue := loan.Status, loan.DaysPastDue, loan.OverdueAmount
+	charged := u.delinquencyPolicy.Assess(loan, installments, asOf)
+
+	if charged {
+		err = u.installmentRepository.SaveLateFees(ctx, installments)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if loan.Status == status && loan.DaysPastDue == daysPastDue && loan.OverdueAmount == overdue {
+		return false, nil
+	}
+
+	now := time.Now()
+	loan.UpdatedAt = &now
+
+	err = u.loanRepository.SaveDelinquency(ctx, loan)
+	if err != nil {
+		// Settled or written off while it was assessed, nothing left to track
+		if err.Error() == "loan_already_closed" {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	recordAudit(ctx, u.auditService, loanAuditEntry(models.AuditActionLoanAssessDelinquency, loan, before))
+
+	if loan.Status == status {
+		return false, nil
+	}
+
+	switch loan.Status {
+	case models.LoanStatusDelinquent:
+		publishLoanWebhook(ctx, u.webhookService, models.WebhookEventLoanDelinquent, loan)
+	case models.LoanStatusDefaulted:
+		publishLoanWebhook(ctx, u.webhookService, models.WebhookEventLoanDefaulted, loan)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services/file_services"
+)
+
+type DocumentUsecaseInterface interface {
+	Download(ctx context.Context, dto *dto_request.DownloadDocumentDTO) (*models.Document, io.ReadSeekCloser, string, error)
+}
+
+type documentUsecase struct {
+	documentRepository        repositories.DocumentRepositoryInterface
+	loanRepository            repositories.LoanRepositoryInterface
+	investmentRepository      repositories.InvestmentRepositoryInterface
+	approvalSignOffRepository repositories.ApprovalSignOffRepositoryInterface
+	fileService               file_services.FileServiceInterface
+	presignDownloads          bool
+}
+
+func NewDocumentUsecase(
+	documentRepository repositories.DocumentRepositoryInterface,
+	loanRepository repositories.LoanRepositoryInterface,
+	investmentRepository repositories.InvestmentRepositoryInterface,
+	approvalSignOffRepository repositories.ApprovalSignOffRepositoryInterface,
+	fileService file_services.FileServiceInterface,
+	presignDownloads bool,
+) DocumentUsecaseInterface {
+	return &documentUsecase{
+		documentRepository:        documentRepository,
+		loanRepository:            loanRepository,
+		investmentRepository:      investmentRepository,
+		approvalSignOffRepository: approvalSignOffRepository,
+		fileService:               fileService,
+		presignDownloads:          presignDownloads,
+	}
+}
+
+// Download checks the user may see the document and opens it, or gives the
+// presigned URL to fetch it from when the storage hands out its own links.
+func (u *documentUsecase) Download(ctx context.Context, dto *dto_request.DownloadDocumentDTO) (*models.Document, io.ReadSeekCloser, string, error) {
+	document, err := u.documentRepository.Detail(ctx, dto.DocumentID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if document == nil {
+		return nil, nil, "", errors.New("document_not_found")
+	}
+
+	loan, err := u.loanRepository.DetailByID(ctx, document.LoanID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if loan == nil {
+		return nil, nil, "", errors.New("loan_not_found")
+	}
+
+	allowed, err := u.canAccess(ctx, dto, document, loan)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if !allowed {
+		return nil, nil, "", errors.New("document_access_denied")
+	}
+
+	// Storage that presigns downloads serves the file itself
+	if u.presignDownloads {
+		url, err := u.fileService.DownloadURL(document.FileURL)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		return document, nil, url, nil
+	}
+
+	file, err := u.fileService.Open(document.FileURL)
+	if err != nil {
+		if err.Error() == "file_not_found" {
+			return nil, nil, "", errors.New("document_not_found")
+		}
+		return nil, nil, "", err
+	}
+
+	return document, file, "", nil
+}
+
+// canAccess limits documents to the people involved in the loan: the borrower,
+// the staff who worked on it, the approvers its sign-off waits for or got and
+// the investors who funded it.
+func (u *documentUsecase) canAccess(ctx context.Context, dto *dto_request.DownloadDocumentDTO, document *models.Document, loan *models.Loan) (bool, error) {
+	switch {
+	case dto.Role == models.RoleAdmin:
+		return true, nil
+	case document.UploadedBy == dto.UserID:
+		return true, nil
+	case dto.Role == models.RoleBorower:
+		return loan.BorrowerID == dto.UserID, nil
+	case dto.Role == models.RoleFieldValidator:
+		return loan.Approval != nil && loan.Approval.FieldValidatorID == dto.UserID, nil
+	case dto.Role == models.RoleFieldOfficer:
+		return loan.Disbursment != nil && loan.Disbursment.FieldOfficerID == dto.UserID, nil
+	case dto.Role == models.RoleSupervisor || dto.Role == models.RoleCreditCommittee:
+		return u.isSignOffApprover(ctx, dto, loan)
+	case dto.Role == models.RoleInvestor:
+		count, err := u.investmentRepository.Count(ctx, repositories.InvestmentRepositoryFilter{
+			LoanID:     &loan.ID,
+			InvestorID: &dto.UserID,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return count > 0, nil
+	default:
+		return false, nil
+	}
+}
+
+// isSignOffApprover tells whether the user signed the loan off, or holds a
+// role its approval still waits for, they need the evidence to decide.
+func (u *documentUsecase) isSignOffApprover(ctx context.Context, dto *dto_request.DownloadDocumentDTO, loan *models.Loan) (bool, error) {
+	if loan.Approval == nil {
+		return false, nil
+	}
+
+	signOffs, err := u.approvalSignOffRepository.ListByApproval(ctx, loan.Approval.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, signOff := range signOffs {
+		if signOff.ApproverID == dto.UserID {
+			return true, nil
+		}
+	}
+
+	if loan.Status != models.LoanStatusPendingApproval {
+		return false, nil
+	}
+
+	for _, role := range loan.Approval.MissingRoles(signOffs) {
+		if role == dto.Role {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,285 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+// maxBundleRecords bounds the work a single sync request may ask for
+const maxBundleRecords = 500
+
+type FieldSyncUsecaseInterface interface {
+	Key(ctx context.Context, fieldOfficerID uint) (string, error)
+	Sync(ctx context.Context, dto *dto_request.SyncCollectionsDTO) (*models.SyncReport, error)
+}
+
+type fieldSyncUsecase struct {
+	loanRepository        repositories.LoanRepositoryInterface
+	installmentRepository repositories.InstallmentRepositoryInterface
+	paymentRepository     repositories.PaymentRepositoryInterface
+	groupLoanRepository   repositories.GroupLoanRepositoryInterface
+	collectionUsecase     CollectionUsecaseInterface
+	groupLoanUsecase      GroupLoanUsecaseInterface
+	fieldSyncSigner       services.FieldSyncSigner
+}
+
+func NewFieldSyncUsecase(
+	loanRepository repositories.LoanRepositoryInterface,
+	installmentRepository repositories.InstallmentRepositoryInterface,
+	paymentRepository repositories.PaymentRepositoryInterface,
+	groupLoanRepository repositories.GroupLoanRepositoryInterface,
+	collectionUsecase CollectionUsecaseInterface,
+	groupLoanUsecase GroupLoanUsecaseInterface,
+	fieldSyncSigner services.FieldSyncSigner,
+) FieldSyncUsecaseInterface {
+	return &fieldSyncUsecase{
+		loanRepository:        loanRepository,
+		installmentRepository: installmentRepository,
+		paymentRepository:     paymentRepository,
+		groupLoanRepository:   groupLoanRepository,
+		collectionUsecase:     collectionUsecase,
+		groupLoanUsecase:      groupLoanUsecase,
+		fieldSyncSigner:       fieldSyncSigner,
+	}
+}
+
+// Key hands the field app of an officer the key it signs bundles with
+func (u *fieldSyncUsecase) Key(ctx context.Context, fieldOfficerID uint) (string, error) {
+	return u.fieldSyncSigner.Key(fieldOfficerID)
+}
+
+// Sync applies a bundle of collections captured offline, oldest first so
+// repayments land on the installments in the order they were collected.
+// Every record applies once however often the bundle is sent, a record that
+// cannot apply is reported as a conflict without failing the others.
+func (u *fieldSyncUsecase) Sync(ctx context.Context, dto *dto_request.SyncCollectionsDTO) (*models.SyncReport, error) {
+	err := u.fieldSyncSigner.Verify(dto.FieldOfficerID, dto.Body, dto.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle dto_request.CollectionBundleDTO
+	err = json.Unmarshal(dto.Body, &bundle)
+	if err != nil || len(bundle.Records) > maxBundleRecords {
+		return nil, errors.New("invalid_bundle_payload")
+	}
+
+	records := bundle.Records
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CollectedAt.Before(records[j].CollectedAt)
+	})
+
+	report := &models.SyncReport{
+		Results: make([]models.SyncRecordResult, 0, len(records)),
+	}
+
+	seen := map[string]bool{}
+	touched := []uint{}
+	for _, record := range records {
+		result := models.SyncRecordResult{
+			RecordID: record.ID,
+			Status:   models.SyncRecordApplied,
+		}
+
+		var loanIDs []uint
+		var recordErr error
+		if seen[record.ID] {
+			result.Status = models.SyncRecordDuplicate
+		} else {
+			seen[record.ID] = true
+			result.Status, loanIDs, recordErr = u.apply(ctx, dto.FieldOfficerID, &record)
+		}
+
+		if recordErr != nil {
+			// Business errors only concern the record, anything else stops the
+			// sync and the field app sends the bundle again
+			if utils.GetErrorCode(recordErr.Error()) >= 500 {
+				return nil, recordErr
+			}
+
+			result.Status = models.SyncRecordConflict
+			result.Reason = recordErr.Error()
+		}
+
+		touched = append(touched, loanIDs...)
+		report.Results = append(report.Results, result)
+	}
+
+	report.Loans, err = u.positions(ctx, dto.FieldOfficerID, touched)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// apply books one record and returns the loans it concerns
+func (u *fieldSyncUsecase) apply(ctx context.Context, fieldOfficerID uint, record *dto_request.CollectionRecordDTO) (models.SyncRecordStatus, []uint, error) {
+	if record.ID == "" || record.Amount <= 0 || record.CollectedAt.IsZero() || record.CollectedAt.After(time.Now()) {
+		return "", nil, errors.New("invalid_collection_record")
+	}
+
+	// Record ids come from the field app, they are only unique per officer
+	reference := fmt.Sprintf("field:%d:%s", fieldOfficerID, record.ID)
+
+	switch {
+	case record.LoanID != "" && record.GroupLoanID == "":
+		return u.applyLoanRecord(ctx, fieldOfficerID, reference, record)
+	case record.GroupLoanID != "" && record.LoanID == "":
+		return u.applyGroupRecord(ctx, fieldOfficerID, reference, record)
+	default:
+		return "", nil, errors.New("invalid_collection_record")
+	}
+}
+
+func (u *fieldSyncUsecase) applyLoanRecord(ctx context.Context, fieldOfficerID uint, reference string, record *dto_request.CollectionRecordDTO) (models.SyncRecordStatus, []uint, error) {
+	existing, err := u.paymentRepository.DetailByGatewayReference(ctx, reference)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if existing != nil {
+		return models.SyncRecordDuplicate, []uint{existing.LoanID}, nil
+	}
+
+	loan, err := u.loanRepository.Detail(ctx, record.LoanID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if loan == nil {
+		return "", nil, errors.New("loan_not_found")
+	}
+
+	count, err := u.loanRepository.Count(ctx, repositories.LoanRepositoryFilter{
+		IDs:            []uint{loan.ID},
+		FieldOfficerID: &fieldOfficerID,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if count == 0 {
+		return "", nil, errors.New("loan_not_found")
+	}
+
+	if !loan.Repaying() {
+		return "", []uint{loan.ID}, errors.New("loan_already_closed")
+	}
+
+	installments, err := u.installmentRepository.ListByLoan(ctx, loan.ID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	outstanding := models.NewLoanPosition(loan, installments).Outstanding
+	if record.Amount-outstanding >= 0.005 {
+		return "", []uint{loan.ID}, errors.New("amount_exceeds_outstanding")
+	}
+
+	payment := models.NewPayment(loan.ID, models.PaymentDirectionCollection, payment_services.PaymentMethodCash, record.Amount)
+	payment.GatewayReference = reference
+	payment.Complete()
+	collectedAt := record.CollectedAt
+	payment.CompletedAt = &collectedAt
+
+	err = u.collectionUsecase.ApplyRepayment(ctx, loan, payment)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return models.SyncRecordApplied, []uint{loan.ID}, nil
+}
+
+// applyGroupRecord books a group meeting collection, the record id becomes
+// the reference of the meeting collection
+func (u *fieldSyncUsecase) applyGroupRecord(ctx context.Context, fieldOfficerID uint, reference string, record *dto_request.CollectionRecordDTO) (models.SyncRecordStatus, []uint, error) {
+	existing, err := u.groupLoanRepository.DetailCollection(ctx, reference)
+	if err != nil {
+		return "", nil, err
+	}
+
+	status := models.SyncRecordApplied
+	if existing != nil {
+		status = models.SyncRecordDuplicate
+	}
+
+	// A duplicate goes through as well, finishing a collection that failed
+	// halfway the first time
+	collection, err := u.groupLoanUsecase.Collect(ctx, &dto_request.CollectGroupLoanDTO{
+		GroupLoanID:    record.GroupLoanID,
+		FieldOfficerID: fieldOfficerID,
+		Amount:         record.Amount,
+		MeetingDate:    record.CollectedAt.In(time.Local).Format(time.DateOnly),
+		Reference:      reference,
+		Allocations:    record.Allocations,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	loanIDs := make([]uint, 0, len(collection.Allocations))
+	for _, allocation := range collection.Allocations {
+		loanIDs = append(loanIDs, allocation.LoanID)
+	}
+
+	return status, loanIDs, nil
+}
+
+// positions lists the loans the officer is collecting, with the loans the
+// bundle touched even when they are closed now
+func (u *fieldSyncUsecase) positions(ctx context.Context, fieldOfficerID uint, touched []uint) ([]models.LoanPosition, error) {
+	loans := []models.Loan{}
+	filters := []repositories.LoanRepositoryFilter{
+		{FieldOfficerID: &fieldOfficerID, Statuses: collectionLoanStatuses},
+	}
+	if len(touched) > 0 {
+		filters = append(filters, repositories.LoanRepositoryFilter{IDs: touched})
+	}
+
+	listed := map[uint]bool{}
+	for _, filter := range filters {
+		cursor := ""
+		for {
+			page, _, nextCursor, err := u.loanRepository.List(ctx, 1, 100, &cursor, "created_at", filter)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, loan := range *page {
+				if !listed[loan.ID] {
+					listed[loan.ID] = true
+					loans = append(loans, loan)
+				}
+			}
+
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+	}
+
+	positions := make([]models.LoanPosition, 0, len(loans))
+	for index := range loans {
+		installments, err := u.installmentRepository.ListByLoan(ctx, loans[index].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		positions = append(positions, models.NewLoanPosition(&loans[index], installments))
+	}
+
+	return positions, nil
+}
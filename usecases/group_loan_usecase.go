@@ -0,0 +1,331 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
+)
+
+type GroupLoanUsecaseInterface interface {
+	Propose(ctx context.Context, dto *dto_request.ProposeGroupLoanDTO) (*models.GroupLoan, error)
+	Detail(ctx context.Context, dto *dto_request.GroupLoanDetailDTO) (*models.GroupLoan, error)
+	Approve(ctx context.Context, dto *dto_request.ApproveGroupLoanDTO) (*models.GroupLoan, error)
+	Collect(ctx context.Context, dto *dto_request.CollectGroupLoanDTO) (*models.GroupCollection, error)
+}
+
+type groupLoanUsecase struct {
+	groupLoanRepository     repositories.GroupLoanRepositoryInterface
+	borrowerGroupRepository repositories.BorrowerGroupRepositoryInterface
+	installmentRepository   repositories.InstallmentRepositoryInterface
+	loanUsecase             LoanUsecaseInterface
+	collectionUsecase       CollectionUsecaseInterface
+	auditService            services.AuditServiceInterface
+}
+
+func NewGroupLoanUsecase(
+	groupLoanRepository repositories.GroupLoanRepositoryInterface,
+	borrowerGroupRepository repositories.BorrowerGroupRepositoryInterface,
+	installmentRepository repositories.InstallmentRepositoryInterface,
+	loanUsecase LoanUsecaseInterface,
+	collectionUsecase CollectionUsecaseInterface,
+	auditService services.AuditServiceInterface,
+) GroupLoanUsecaseInterface {
+	return &groupLoanUsecase{
+		groupLoanRepository:     groupLoanRepository,
+		borrowerGroupRepository: borrowerGroupRepository,
+		installmentRepository:   installmentRepository,
+		loanUsecase:             loanUsecase,
+		collectionUsecase:       collectionUsecase,
+		auditService:            auditService,
+	}
+}
+
+// Propose opens a group loan with one sub-loan per member, each going through
+// the credit checks and pricing of a single loan. Proposing again while the
+// group loan waits for approval adds the members that have no sub-loan yet,
+// so a proposal failing halfway can simply be retried.
+func (u *groupLoanUsecase) Propose(ctx context.Context, dto *dto_request.ProposeGroupLoanDTO) (*models.GroupLoan, error) {
+	group, err := borrowerGroupFor(ctx, u.borrowerGroupRepository, dto.GroupID, dto.FieldOfficerID, models.RoleFieldOfficer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Joint liability needs at least two members to share it
+	if len(dto.Members) < 2 {
+		return nil, errors.New("invalid_group_loan")
+	}
+
+	proposed := map[uint]bool{}
+	for _, member := range dto.Members {
+		if proposed[member.BorrowerID] {
+			return nil, errors.New("invalid_group_loan")
+		}
+		proposed[member.BorrowerID] = true
+
+		if !group.HasMember(member.BorrowerID) {
+			return nil, errors.New("borrower_not_group_member")
+		}
+	}
+
+	groupLoan, err := u.groupLoanRepository.DetailProposed(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var before json.RawMessage
+	if groupLoan == nil {
+		groupLoan, err = u.groupLoanRepository.Save(ctx, models.NewGroupLoan(group, dto.FieldOfficerID))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		before = models.AuditSnapshot(dto_response.GroupLoanDetailResponse(groupLoan))
+	}
+
+	for _, member := range dto.Members {
+		if groupLoan.Loan(member.BorrowerID) != nil {
+			continue
+		}
+
+		loan, err := u.loanUsecase.Propose(ctx, &dto_request.ProposeLoanDTO{
+			BorowwerID:  member.BorrowerID,
+			ProductID:   dto.ProductID,
+			Amount:      member.Amount,
+			Tenor:       member.Tenor,
+			GroupLoanID: &groupLoan.ID,
+			ProposedBy:  groupLoan.ProposedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		groupLoan.Loans = append(groupLoan.Loans, *loan)
+	}
+
+	recordAudit(ctx, u.auditService, groupLoanAuditEntry(models.AuditActionGroupLoanPropose, groupLoan, before))
+
+	return groupLoan, nil
+}
+
+// Detail shows the group loan with its sub-loans, a field officer only sees
+// the loans of their own groups.
+func (u *groupLoanUsecase) Detail(ctx context.Context, dto *dto_request.GroupLoanDetailDTO) (*models.GroupLoan, error) {
+	return u.groupLoanFor(ctx, dto.GroupLoanID, dto.UserID, dto.Role)
+}
+
+// Approve approves the group as a whole, every proposed sub-loan is approved
+// with the same evidence. Sub-loans already approved are skipped, so an
+// approval failing halfway can simply be retried.
+func (u *groupLoanUsecase) Approve(ctx context.Context, dto *dto_request.ApproveGroupLoanDTO) (*models.GroupLoan, error) {
+	groupLoan, err := u.groupLoanFor(ctx, dto.GroupLoanID, dto.FieldValidatorID, models.RoleFieldValidator)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupLoan.Status != models.GroupLoanProposed {
+		return nil, errors.New("only_proposed_group_loan_allowed")
+	}
+
+	before := models.AuditSnapshot(dto_response.GroupLoanDetailResponse(groupLoan))
+
+	approved := 0
+	for index, loan := range groupLoan.Loans {
+		if loan.Status == models.LoanStatusApproved || loan.Status == models.LoanStatusPendingApproval {
+			approved++
+		}
+
+		if loan.Status != models.LoanStatusProposed {
+			continue
+		}
+
+		subLoan, err := u.loanUsecase.Approve(ctx, &dto_request.ApproveLoanDTO{
+			LoanID:           loan.UUID.String(),
+			FieldValidatorID: dto.FieldValidatorID,
+			Documents:        dto.Documents,
+			Visit:            dto.Visit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		groupLoan.Loans[index] = *subLoan
+		approved++
+	}
+
+	if approved == 0 {
+		return nil, errors.New("only_proposed_loan_allowed")
+	}
+
+	groupLoan.Approve(dto.FieldValidatorID)
+
+	groupLoan, err = u.groupLoanRepository.Save(ctx, groupLoan)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, groupLoanAuditEntry(models.AuditActionGroupLoanApprove, groupLoan, before))
+
+	return groupLoan, nil
+}
+
+// Collect records the cash collected at a group meeting and repays the
+// sub-loans with it. Without explicit allocations the amount is split in
+// proportion to what each member owes as of the meeting, so members who
+// cannot pay are covered by the group. Recording the same reference again
+// applies the original allocations once more, which repays nothing twice.
+func (u *groupLoanUsecase) Collect(ctx context.Context, dto *dto_request.CollectGroupLoanDTO) (*models.GroupCollection, error) {
+	groupLoan, err := u.groupLoanFor(ctx, dto.GroupLoanID, dto.FieldOfficerID, models.RoleFieldOfficer)
+	if err != nil {
+		return nil, err
+	}
+
+	meetingDate := time.Now()
+	if dto.MeetingDate != "" {
+		meetingDate, err = time.ParseInLocation(time.DateOnly, dto.MeetingDate, time.Local)
+		if err != nil || meetingDate.After(time.Now()) {
+			return nil, errors.New("invalid_meeting_date")
+		}
+	}
+
+	collection, err := models.NewGroupCollection(groupLoan, dto.FieldOfficerID, dto.Reference, dto.Amount, meetingDate)
+	if err != nil {
+		return nil, err
+	}
+
+	loans := []models.Loan{}
+	dues := []float64{}
+	outstanding := map[uint]float64{}
+	for _, loan := range groupLoan.Loans {
+		if !loan.Repaying() {
+			continue
+		}
+
+		installments, err := u.installmentRepository.ListByLoan(ctx, loan.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		due := 0.0
+		for _, installment := range installments {
+			outstanding[loan.BorrowerID] += installment.Outstanding()
+			if !installment.DueDate.After(collection.MeetingDate) {
+				due += installment.Outstanding()
+			}
+		}
+
+		loans = append(loans, loan)
+		dues = append(dues, due)
+	}
+
+	if len(loans) == 0 {
+		return nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	if len(dto.Allocations) == 0 {
+		err = collection.Allocate(loans, dues)
+	} else {
+		err = allocateGroupCollection(collection, loans, outstanding, dto.Allocations)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err = u.groupLoanRepository.SaveCollection(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, &models.AuditEntry{
+		Action:     models.AuditActionGroupLoanCollect,
+		EntityType: models.AuditEntityGroupCollection,
+		EntityID:   collection.UUID.String(),
+		After:      dto_response.GroupCollectionDetailResponse(collection),
+	})
+
+	for _, allocation := range collection.Allocations {
+		loan := groupLoan.Loan(allocation.BorrowerID)
+		if loan == nil {
+			continue
+		}
+
+		err = u.collectionUsecase.ApplyRepayment(ctx, loan, collection.Payment(&allocation, payment_services.PaymentMethodCash))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return collection, nil
+}
+
+func groupLoanAuditEntry(action string, groupLoan *models.GroupLoan, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityGroupLoan,
+		EntityID:   groupLoan.UUID.String(),
+		Before:     before,
+		After:      dto_response.GroupLoanDetailResponse(groupLoan),
+	}
+}
+
+func (u *groupLoanUsecase) groupLoanFor(ctx context.Context, groupLoanID string, userID uint, role models.UserRole) (*models.GroupLoan, error) {
+	groupLoan, err := u.groupLoanRepository.Detail(ctx, groupLoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupLoan == nil || (role == models.RoleFieldOfficer && groupLoan.Group.FieldOfficerID != userID) {
+		return nil, errors.New("group_loan_not_found")
+	}
+
+	return groupLoan, nil
+}
+
+// allocateGroupCollection applies the split the field officer recorded, it
+// has to add up to the collection and no member may pay beyond what they owe.
+func allocateGroupCollection(collection *models.GroupCollection, loans []models.Loan, outstanding map[uint]float64, allocations []dto_request.GroupCollectionAllocationDTO) error {
+	total := 0.0
+	allocated := map[uint]bool{}
+	for _, allocation := range allocations {
+		if allocation.Amount <= 0 || allocated[allocation.BorrowerID] {
+			return errors.New("invalid_group_collection")
+		}
+		allocated[allocation.BorrowerID] = true
+
+		var loan *models.Loan
+		for index := range loans {
+			if loans[index].BorrowerID == allocation.BorrowerID {
+				loan = &loans[index]
+			}
+		}
+
+		if loan == nil {
+			return errors.New("borrower_not_group_member")
+		}
+
+		if allocation.Amount > outstanding[allocation.BorrowerID] {
+			return errors.New("group_collection_exceeds_due")
+		}
+
+		collection.Allocations = append(collection.Allocations, models.GroupCollectionAllocation{
+			LoanID:     loan.ID,
+			BorrowerID: loan.BorrowerID,
+			Amount:     allocation.Amount,
+		})
+		total += allocation.Amount
+	}
+
+	if math.Abs(total-collection.Amount) >= 0.005 {
+		return errors.New("invalid_group_collection")
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type InvestmentMarketUsecaseInterface interface {
+	List(ctx context.Context, dto *dto_request.InvestmentListingListDTO) (*[]models.InvestmentListing, int, error)
+	Create(ctx context.Context, dto *dto_request.CreateInvestmentListingDTO) (*models.InvestmentListing, error)
+	Cancel(ctx context.Context, dto *dto_request.CancelInvestmentListingDTO) (*models.InvestmentListing, error)
+	Buy(ctx context.Context, dto *dto_request.BuyInvestmentListingDTO) (*models.InvestmentTransfer, error)
+	ListTransfers(ctx context.Context, dto *dto_request.InvestmentTransferListDTO) (*[]models.InvestmentTransfer, int, error)
+}
+
+type investmentMarketUsecase struct {
+	investmentListingRepository repositories.InvestmentListingRepositoryInterface
+	investmentRepository        repositories.InvestmentRepositoryInterface
+	loanRepository              repositories.LoanRepositoryInterface
+	auditService                services.AuditServiceInterface
+	investmentLimits            models.InvestmentLimits
+}
+
+func NewInvestmentMarketUsecase(
+	investmentListingRepository repositories.InvestmentListingRepositoryInterface,
+	investmentRepository repositories.InvestmentRepositoryInterface,
+	loanRepository repositories.LoanRepositoryInterface,
+	auditService services.AuditServiceInterface,
+	investmentLimits models.InvestmentLimits,
+) InvestmentMarketUsecaseInterface {
+	return &investmentMarketUsecase{
+		investmentListingRepository: investmentListingRepository,
+		investmentRepository:        investmentRepository,
+		loanRepository:              loanRepository,
+		auditService:                auditService,
+		investmentLimits:            investmentLimits,
+	}
+}
+
+// List shows the open listings of the market, or every listing of the
+// investor when they look at their own.
+func (u *investmentMarketUsecase) List(ctx context.Context, dto *dto_request.InvestmentListingListDTO) (*[]models.InvestmentListing, int, error) {
+	filter := repositories.InvestmentListingRepositoryFilter{}
+	if dto.Mine {
+		filter.SellerID = &dto.UserID
+	} else {
+		status := models.InvestmentListingOpen
+		filter.Status = &status
+	}
+
+	if dto.LoanID != "" {
+		loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if loan == nil {
+			return nil, 0, errors.New("loan_not_found")
+		}
+		filter.LoanID = &loan.ID
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.investmentListingRepository.List(ctx, page, perPage, "-created_at", filter)
+}
+
+// Create puts a whole investment up for sale. Only positions on disbursed
+// loans trade, before that the money is still reserved in the wallet.
+func (u *investmentMarketUsecase) Create(ctx context.Context, dto *dto_request.CreateInvestmentListingDTO) (*models.InvestmentListing, error) {
+	investment, err := u.investmentRepository.Detail(ctx, dto.InvestmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if investment == nil || investment.InvestorID != dto.InvestorID {
+		return nil, errors.New("investment_not_found")
+	}
+
+	if investment.Loan.Status != models.LoanStatusDisbursed {
+		return nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	if dto.Price <= 0 {
+		return nil, errors.New("invalid_amount")
+	}
+
+	listing, err := u.investmentListingRepository.Save(ctx, models.NewInvestmentListing(investment, dto.Price))
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, listingAuditEntry(models.AuditActionInvestmentListingCreate, listing, nil))
+
+	return listing, nil
+}
+
+func (u *investmentMarketUsecase) Cancel(ctx context.Context, dto *dto_request.CancelInvestmentListingDTO) (*models.InvestmentListing, error) {
+	listing, err := u.investmentListingRepository.Detail(ctx, dto.ListingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if listing == nil || listing.SellerID != dto.InvestorID {
+		return nil, errors.New("investment_listing_not_found")
+	}
+
+	if listing.Status != models.InvestmentListingOpen {
+		return nil, errors.New("investment_listing_not_available")
+	}
+
+	before := models.AuditSnapshot(dto_response.InvestmentListingDetailResponse(listing))
+	listing.Cancel()
+
+	listing, err = u.investmentListingRepository.Save(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, listingAuditEntry(models.AuditActionInvestmentListingCancel, listing, before))
+
+	return listing, nil
+}
+
+// Buy transfers the listed investment to the buyer. The position counts
+// against the buyer concentration limits as if it were a new ticket, the
+// ticket size rules do not apply since the amount was sized at origination.
+func (u *investmentMarketUsecase) Buy(ctx context.Context, dto *dto_request.BuyInvestmentListingDTO) (*models.InvestmentTransfer, error) {
+	listing, err := u.investmentListingRepository.Detail(ctx, dto.ListingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if listing == nil {
+		return nil, errors.New("investment_listing_not_found")
+	}
+
+	if listing.Status != models.InvestmentListingOpen {
+		return nil, errors.New("investment_listing_not_available")
+	}
+
+	if listing.SellerID == dto.InvestorID {
+		return nil, errors.New("cannot_buy_own_listing")
+	}
+
+	loan := listing.Investment.Loan
+	if loan.Status != models.LoanStatusDisbursed {
+		return nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	position, err := u.investmentRepository.Position(ctx, dto.InvestorID, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.investmentLimits.CheckConcentration(listing.Investment.Amount, loan, *position)
+	if err != nil {
+		return nil, err
+	}
+
+	before := models.AuditSnapshot(dto_response.InvestmentListingDetailResponse(listing))
+	transfer := listing.Sell(dto.InvestorID)
+	err = u.investmentListingRepository.Settle(ctx, listing, transfer)
+	if err != nil {
+		return nil, err
+	}
+	transfer.Investment = listing.Investment
+
+	recordAudit(ctx, u.auditService, listingAuditEntry(models.AuditActionInvestmentListingBuy, listing, before))
+
+	return transfer, nil
+}
+
+func listingAuditEntry(action string, listing *models.InvestmentListing, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:       action,
+		EntityType:   models.AuditEntityInvestmentListing,
+		EntityID:     listing.UUID.String(),
+		LoanID:       &listing.Investment.LoanID,
+		InvestmentID: &listing.InvestmentID,
+		Before:       before,
+		After:        dto_response.InvestmentListingDetailResponse(listing),
+	}
+}
+
+func (u *investmentMarketUsecase) ListTransfers(ctx context.Context, dto *dto_request.InvestmentTransferListDTO) (*[]models.InvestmentTransfer, int, error) {
+	filter := repositories.InvestmentTransferRepositoryFilter{}
+	if dto.Role != models.RoleAdmin {
+		filter.InvestorID = &dto.UserID
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.investmentListingRepository.ListTransfers(ctx, page, perPage, "-created_at", filter)
+}
@@ -0,0 +1,194 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type LoanProductUsecaseInterface interface {
+	Create(ctx context.Context, dto *dto_request.CreateLoanProductDTO) (*models.LoanProduct, error)
+	Update(ctx context.Context, dto *dto_request.UpdateLoanProductDTO) (*models.LoanProduct, error)
+	List(ctx context.Context, dto *dto_request.LoanProductListDTO) (*[]models.LoanProduct, int, error)
+}
+
+type loanProductUsecase struct {
+	loanProductRepository repositories.LoanProductRepositoryInterface
+	auditService          services.AuditServiceInterface
+}
+
+func NewLoanProductUsecase(loanProductRepository repositories.LoanProductRepositoryInterface, auditService services.AuditServiceInterface) LoanProductUsecaseInterface {
+	return &loanProductUsecase{
+		loanProductRepository: loanProductRepository,
+		auditService:          auditService,
+	}
+}
+
+func (u *loanProductUsecase) Create(ctx context.Context, dto *dto_request.CreateLoanProductDTO) (*models.LoanProduct, error) {
+	existing, err := u.loanProductRepository.DetailByCode(ctx, dto.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, errors.New("loan_product_code_taken")
+	}
+
+	product := models.NewLoanProduct(dto.Code, dto.Name, dto.MinAmount, dto.MaxAmount, dto.Tenors, dto.InstallmentFrequency, dto.GracePeriodDays)
+	product.EarlySettlementFeePercent = dto.EarlySettlementFeePercent
+	product.EligibleRiskGrades = dto.EligibleRiskGrades
+	product.EligibleSectors = dto.EligibleSectors
+	product.EligibleRegions = dto.EligibleRegions
+
+	if err := validateLoanProduct(product); err != nil {
+		return nil, err
+	}
+
+	product, err = u.loanProductRepository.Save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, loanProductAuditEntry(models.AuditActionLoanProductCreate, product, nil))
+
+	return product, nil
+}
+
+func (u *loanProductUsecase) Update(ctx context.Context, dto *dto_request.UpdateLoanProductDTO) (*models.LoanProduct, error) {
+	product, err := u.loanProductRepository.Detail(ctx, dto.LoanProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("loan_product_not_found")
+	}
+
+	before := models.AuditSnapshot(dto_response.LoanProductDetailResponse(product))
+
+	if dto.Name != nil {
+		product.Name = *dto.Name
+	}
+
+	if dto.MinAmount != nil {
+		product.MinAmount = *dto.MinAmount
+	}
+
+	if dto.MaxAmount != nil {
+		product.MaxAmount = *dto.MaxAmount
+	}
+
+	if dto.Tenors != nil {
+		product.Tenors = dto.Tenors
+	}
+
+	if dto.InstallmentFrequency != nil {
+		product.InstallmentFrequency = *dto.InstallmentFrequency
+	}
+
+	if dto.GracePeriodDays != nil {
+		product.GracePeriodDays = *dto.GracePeriodDays
+	}
+
+	if dto.EarlySettlementFeePercent != nil {
+		product.EarlySettlementFeePercent = *dto.EarlySettlementFeePercent
+	}
+
+	if dto.EligibleRiskGrades != nil {
+		product.EligibleRiskGrades = dto.EligibleRiskGrades
+	}
+
+	if dto.EligibleSectors != nil {
+		product.EligibleSectors = dto.EligibleSectors
+	}
+
+	if dto.EligibleRegions != nil {
+		product.EligibleRegions = dto.EligibleRegions
+	}
+
+	if dto.Active != nil {
+		product.Active = *dto.Active
+	}
+
+	if err := validateLoanProduct(product); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	product.UpdatedAt = &now
+
+	product, err = u.loanProductRepository.Save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, loanProductAuditEntry(models.AuditActionLoanProductUpdate, product, before))
+
+	return product, nil
+}
+
+func (u *loanProductUsecase) List(ctx context.Context, dto *dto_request.LoanProductListDTO) (*[]models.LoanProduct, int, error) {
+	filter := repositories.LoanProductRepositoryFilter{}
+	if dto.ActiveOnly {
+		active := true
+		filter.Active = &active
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.loanProductRepository.List(ctx, page, perPage, "code", filter)
+}
+
+func loanProductAuditEntry(action string, product *models.LoanProduct, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityLoanProduct,
+		EntityID:   product.UUID.String(),
+		Before:     before,
+		After:      dto_response.LoanProductDetailResponse(product),
+	}
+}
+
+func validateLoanProduct(product *models.LoanProduct) error {
+	if product.Code == "" || product.Name == "" {
+		return errors.New("invalid_loan_product")
+	}
+
+	if product.MinAmount <= 0 || product.MaxAmount < product.MinAmount {
+		return errors.New("invalid_loan_product")
+	}
+
+	if len(product.Tenors) == 0 || product.GracePeriodDays < 0 {
+		return errors.New("invalid_loan_product")
+	}
+
+	if product.EarlySettlementFeePercent < 0 || product.EarlySettlementFeePercent > 100 {
+		return errors.New("invalid_loan_product")
+	}
+
+	for _, tenor := range product.Tenors {
+		if tenor <= 0 {
+			return errors.New("invalid_loan_product")
+		}
+	}
+
+	if !models.InstallmentFrequencies[product.InstallmentFrequency] {
+		return errors.New("invalid_installment_frequency")
+	}
+
+	for _, grade := range product.EligibleRiskGrades {
+		if !models.RiskGrades[grade] {
+			return errors.New("invalid_risk_grade")
+		}
+	}
+
+	return nil
+}
@@ -2,60 +2,202 @@ package usecases
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 	"sync"
+	"time"
 
 	"github.com/gotidy/ptr"
 	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
 	"github.com/peang/amartha-loan-service/models"
 	"github.com/peang/amartha-loan-service/repositories"
 	"github.com/peang/amartha-loan-service/services"
 	"github.com/peang/amartha-loan-service/services/file_services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+// Auto invest matching runs after the approval request, these bound how long
+// it and the recording of its failures may take.
+const (
+	autoInvestMatchTimeout   = 2 * time.Minute
+	autoInvestFailureTimeout = 10 * time.Second
 )
 
 type LoanUsecaseInterface interface {
 	Propose(ctx context.Context, dto *dto_request.ProposeLoanDTO) (*models.Loan, error)
 	Approve(ctx context.Context, dto *dto_request.ApproveLoanDTO) (*models.Loan, error)
-	GetAvailableLoans(ctx context.Context, dto *dto_request.ApprovedLoanListDTO) (*[]models.Loan, int, error)
+	SignOff(ctx context.Context, dto *dto_request.SignOffLoanDTO) (*models.Loan, error)
+	PendingApprovals(ctx context.Context, dto *dto_request.PendingApprovalListDTO) (*[]models.Loan, int, error)
+	GetAvailableLoans(ctx context.Context, dto *dto_request.ApprovedLoanListDTO) (*[]models.Loan, *utils.Meta, error)
 	Invest(ctx context.Context, dto *dto_request.InvestLoanDTO) (*models.Investment, error)
+	ListInvestments(ctx context.Context, dto *dto_request.InvestmentListDTO) (*[]models.Investment, *utils.Meta, error)
 	Disburse(ctx context.Context, dto *dto_request.DisburseLoanDTO) (*models.Loan, error)
+	Cancel(ctx context.Context, dto *dto_request.CancelLoanDTO) (*models.Loan, error)
+	CompleteDisbursement(ctx context.Context, payment *models.Payment) error
+	ExpireLoans(ctx context.Context, approvedBefore time.Time) (int, error)
+	MatchAutoInvestPlans(ctx context.Context, loanID string) error
+	Detail(ctx context.Context, dto *dto_request.LoanDetailDTO) (*models.Loan, error)
+}
+
+// LoanDocumentRequirements lists the document types that must be uploaded
+// before a loan may move to the matching state.
+type LoanDocumentRequirements struct {
+	Approval     []models.DocumentType
+	Disbursement []models.DocumentType
 }
 
 type loanUsecase struct {
-	loanRepository       repositories.LoanRepositoryInterface
-	investmentRepository repositories.InvestmentRepositoryInterface
-	fileService          file_services.FileServiceInterface
+	loanRepository            repositories.LoanRepositoryInterface
+	loanProductRepository     repositories.LoanProductRepositoryInterface
+	userRepository            repositories.UserRepositoryInterface
+	investmentRepository      repositories.InvestmentRepositoryInterface
+	walletRepository          repositories.WalletRepositoryInterface
+	paymentRepository         repositories.PaymentRepositoryInterface
+	autoInvestPlanRepository  repositories.AutoInvestPlanRepositoryInterface
+	installmentRepository     repositories.InstallmentRepositoryInterface
+	documentRepository        repositories.DocumentRepositoryInterface
+	approvalSignOffRepository repositories.ApprovalSignOffRepositoryInterface
+	fileService               file_services.FileServiceInterface
+	webhookService            services.WebhookServiceInterface
+	auditService              services.AuditServiceInterface
+	paymentGateway            payment_services.PaymentGateway
+	pricingService            services.PricingServiceInterface
+	creditScorer              services.CreditScorer
+	documentRequirements      LoanDocumentRequirements
+	fieldVisitPolicy          models.FieldVisitPolicy
+	approvalPolicy            models.ApprovalPolicy
+	investmentLimits          models.InvestmentLimits
 }
 
 func NewLoanUsecase(
 	loanRepository repositories.LoanRepositoryInterface,
+	loanProductRepository repositories.LoanProductRepositoryInterface,
+	userRepository repositories.UserRepositoryInterface,
 	investmentRepository repositories.InvestmentRepositoryInterface,
+	walletRepository repositories.WalletRepositoryInterface,
+	paymentRepository repositories.PaymentRepositoryInterface,
+	autoInvestPlanRepository repositories.AutoInvestPlanRepositoryInterface,
+	installmentRepository repositories.InstallmentRepositoryInterface,
+	documentRepository repositories.DocumentRepositoryInterface,
+	approvalSignOffRepository repositories.ApprovalSignOffRepositoryInterface,
 	fileService file_services.FileServiceInterface,
+	webhookService services.WebhookServiceInterface,
+	auditService services.AuditServiceInterface,
+	paymentGateway payment_services.PaymentGateway,
+	pricingService services.PricingServiceInterface,
+	creditScorer services.CreditScorer,
+	documentRequirements LoanDocumentRequirements,
+	fieldVisitPolicy models.FieldVisitPolicy,
+	approvalPolicy models.ApprovalPolicy,
+	investmentLimits models.InvestmentLimits,
 ) LoanUsecaseInterface {
 	return &loanUsecase{
-		loanRepository:       loanRepository,
-		investmentRepository: investmentRepository,
-		fileService:          fileService,
+		loanRepository:            loanRepository,
+		loanProductRepository:     loanProductRepository,
+		userRepository:            userRepository,
+		investmentRepository:      investmentRepository,
+		walletRepository:          walletRepository,
+		paymentRepository:         paymentRepository,
+		autoInvestPlanRepository:  autoInvestPlanRepository,
+		installmentRepository:     installmentRepository,
+		documentRepository:        documentRepository,
+		approvalSignOffRepository: approvalSignOffRepository,
+		fileService:               fileService,
+		webhookService:            webhookService,
+		auditService:              auditService,
+		paymentGateway:            paymentGateway,
+		pricingService:            pricingService,
+		creditScorer:              creditScorer,
+		documentRequirements:      documentRequirements,
+		fieldVisitPolicy:          fieldVisitPolicy,
+		approvalPolicy:            approvalPolicy,
+		investmentLimits:          investmentLimits,
 	}
 }
 
 func (u *loanUsecase) Propose(ctx context.Context, dto *dto_request.ProposeLoanDTO) (*models.Loan, error) {
-	loan := models.NewPropose(dto.BorowwerID, dto.Amount)
+	borrower, err := u.userRepository.Detail(ctx, dto.BorowwerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if borrower == nil {
+		return nil, errors.New("borrower_not_found")
+	}
+
+	product, err := u.loanProductRepository.Detail(ctx, dto.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("loan_product_not_found")
+	}
+
+	assessment, err := u.creditScorer.Score(ctx, borrower)
+	if err != nil {
+		return nil, err
+	}
 
-	aggreementPdfUrl, err := services.GenerateAgreementPDF(loan.UUID.String())
+	err = dto.ValidateProduct(product, borrower, assessment.Grade)
 	if err != nil {
 		return nil, err
 	}
 
-	loan.AgreementFileURL = *aggreementPdfUrl
+	loan := models.NewPropose(dto.BorowwerID, dto.Amount, product, dto.Tenor, assessment.Score, assessment.Grade)
+	loan.GroupLoanID = dto.GroupLoanID
+	if dto.ProposedBy != 0 {
+		loan.ProposedBy = dto.ProposedBy
+	}
+	if assessment.AutoReject {
+		loan.Reject(models.LoanStatusReasonCreditScore)
+
+		loan, err = u.loanRepository.Save(nil, ctx, loan)
+		if err != nil {
+			return nil, err
+		}
+
+		u.recordLoanAudit(ctx, models.AuditActionLoanPropose, loan, nil)
+		u.publishLoanEvent(ctx, models.WebhookEventLoanRejected, loan)
+
+		return loan, nil
+	}
+
+	rateCard, err := u.pricingService.Price(ctx, product.Code, assessment.Grade, dto.Tenor)
+	if err != nil {
+		return nil, err
+	}
+	loan.ApplyRateCard(rateCard)
+
+	aggreementPdf, err := services.GenerateAgreementPDF(loan.UUID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	aggreementFile, err := u.fileService.UploadContent(aggreementPdf)
+	if err != nil {
+		return nil, err
+	}
+
+	loan.AgreementFileURL = aggreementFile.URL
 
 	loan, err = u.loanRepository.Save(nil, ctx, loan)
 	if err != nil {
 		return nil, err
 	}
 
+	document := models.NewDocument(loan.ID, models.DocumentTypeLoanAgreement, aggreementFile.URL, aggreementFile.Checksum, aggreementFile.ContentType, aggreementFile.Size, dto.BorowwerID)
+	err = u.saveDocuments(ctx, loan, []*models.Document{document})
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordLoanAudit(ctx, models.AuditActionLoanPropose, loan, nil)
+	u.publishLoanEvent(ctx, models.WebhookEventLoanProposed, loan)
+
 	return loan, nil
 }
 
@@ -73,42 +215,312 @@ func (u *loanUsecase) Approve(ctx context.Context, dto *dto_request.ApproveLoanD
 		return nil, errors.New("only_proposed_loan_allowed")
 	}
 
-	approvalFileUrl, err := u.fileService.Upload(dto.ProveImage)
+	if dto.FieldValidatorID == loan.ProposedBy || dto.FieldValidatorID == loan.BorrowerID {
+		return nil, errors.New("approver_is_proposer")
+	}
+
+	visit, err := fieldVisit(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	borrower, err := u.userRepository.Detail(ctx, loan.BorrowerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if borrower == nil {
+		return nil, errors.New("borrower_not_found")
+	}
+
+	documents, err := u.uploadDocuments(loan, dto.Documents, models.ApprovalDocumentTypes, u.documentRequirements.Approval, dto.FieldValidatorID)
 	if err != nil {
 		return nil, err
 	}
 
-	loan.Approve(dto.FieldValidatorID, approvalFileUrl)
+	before := loanSnapshot(loan)
+
+	// The first file stays the primary proof for clients reading the approval directly
+	loan.Approve(dto.FieldValidatorID, documents[0].FileURL, documents[0].Checksum, u.approvalPolicy.RequiredRoles(loan))
+	loan.Approval.RecordVisit(*visit, models.NewGeoPoint(borrower.AddressLatitude, borrower.AddressLongitude), u.fieldVisitPolicy)
 
 	loan, err = u.loanRepository.Save(nil, ctx, loan)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, document := range documents {
+		document.ApprovalID = loan.ApprovalID
+	}
+
+	err = u.saveDocuments(ctx, loan, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordLoanAudit(ctx, models.AuditActionLoanApprove, loan, before)
+
+	if loan.Status == models.LoanStatusPendingApproval {
+		u.publishLoanEvent(ctx, models.WebhookEventLoanPendingApproval, loan)
+		return loan, nil
+	}
+
+	u.releaseApprovedLoan(ctx, loan)
+
 	return loan, nil
 }
 
-func (u *loanUsecase) GetAvailableLoans(ctx context.Context, dto *dto_request.ApprovedLoanListDTO) (*[]models.Loan, int, error) {
+// SignOff adds a senior validator or credit committee approval to a large
+// loan, the last sign-off it waits for releases it to investors.
+func (u *loanUsecase) SignOff(ctx context.Context, dto *dto_request.SignOffLoanDTO) (*models.Loan, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil {
+		return nil, errors.New("loan_not_found")
+	}
+
+	// Detail leaves the approval out, the sign-off rules need it
+	loan, err = u.loanRepository.DetailByID(ctx, loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.Approval == nil {
+		return nil, errors.New("only_pending_approval_loan_allowed")
+	}
+
+	signOffs, err := u.approvalSignOffRepository.ListByApproval(ctx, loan.Approval.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	signOff, err := models.NewApprovalSignOff(loan, signOffs, dto.ApproverID, dto.Role, dto.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	before := loanSnapshot(loan)
+
+	err = u.approvalSignOffRepository.Save(ctx, loan, signOff)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordLoanAudit(ctx, models.AuditActionLoanSignOff, loan, before)
+
+	if loan.Status == models.LoanStatusApproved {
+		u.releaseApprovedLoan(ctx, loan)
+	}
+
+	return loan, nil
+}
+
+// PendingApprovals lists the loans still waiting for a sign-off by the role
+func (u *loanUsecase) PendingApprovals(ctx context.Context, dto *dto_request.PendingApprovalListDTO) (*[]models.Loan, int, error) {
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	loans, count, _, err := u.loanRepository.List(ctx, page, perPage, nil, "created_at", repositories.LoanRepositoryFilter{
+		Status:       ptr.Of(models.LoanStatusPendingApproval),
+		AwaitingRole: &dto.Role,
+	})
+
+	return loans, count, err
+}
+
+// releaseApprovedLoan announces a fully approved loan and lets the auto
+// invest plans at it.
+func (u *loanUsecase) releaseApprovedLoan(ctx context.Context, loan *models.Loan) {
+	u.publishLoanEvent(ctx, models.WebhookEventLoanApproved, loan)
+
+	// Matching outlives the request, it must not be cancelled with it
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), autoInvestMatchTimeout)
+		defer cancel()
+
+		if err := u.MatchAutoInvestPlans(ctx, loan.UUID.String()); err != nil {
+			u.recordMatchFailure(loan, nil, 0, err)
+		}
+	}()
+}
+
+// recordMatchFailure keeps a failed match for follow up. It runs on its own
+// context since the matching one may be the reason of the failure.
+func (u *loanUsecase) recordMatchFailure(loan *models.Loan, plan *models.AutoInvestPlan, amount float64, matchErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), autoInvestFailureTimeout)
+	defer cancel()
+
+	failure := &models.AutoInvestMatchFailure{
+		LoanID:    loan.ID,
+		Amount:    amount,
+		Error:     matchErr.Error(),
+		CreatedAt: time.Now(),
+	}
+	if plan != nil {
+		failure.PlanID = &plan.ID
+	}
+
+	if err := u.autoInvestPlanRepository.SaveMatchFailure(ctx, failure); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// MatchAutoInvestPlans places one ticket per matching plan on a newly
+// approved loan. Plans are served in order of their last ticket so the same
+// investors do not take every loan, and every ticket goes through Invest so
+// wallet and investment limits apply exactly as for a manual investment.
+func (u *loanUsecase) MatchAutoInvestPlans(ctx context.Context, loanID string) error {
+	loan, err := u.loanRepository.Detail(ctx, loanID)
+	if err != nil {
+		return err
+	}
+
+	if loan == nil {
+		return errors.New("loan_not_found")
+	}
+
+	borrower, err := u.userRepository.Detail(ctx, loan.BorrowerID)
+	if err != nil {
+		return err
+	}
+
+	if borrower == nil {
+		return errors.New("borrower_not_found")
+	}
+
+	plans, err := u.autoInvestPlanRepository.ListForMatching(ctx)
+	if err != nil {
+		return err
+	}
+
+	served := map[uint]bool{}
+	for _, plan := range *plans {
+		if loan.Status != models.LoanStatusApproved {
+			return nil
+		}
+
+		if served[plan.InvestorID] || !plan.Matches(loan, borrower) {
+			continue
+		}
+
+		amount := plan.TicketFor(loan)
+		if amount <= 0 {
+			continue
+		}
+
+		err = u.autoInvestPlanRepository.ConsumeBudget(ctx, &plan, amount)
+		if err != nil {
+			continue
+		}
+
+		investment, err := u.Invest(ctx, &dto_request.InvestLoanDTO{
+			LoanID:     loan.UUID.String(),
+			InvestorID: plan.InvestorID,
+			Amount:     amount,
+		})
+		if err != nil {
+			// Limits or balance refused the ticket, the plan keeps its budget
+			u.recordMatchFailure(loan, &plan, amount, err)
+			if refundErr := u.autoInvestPlanRepository.RefundBudget(ctx, &plan, amount); refundErr != nil {
+				u.recordMatchFailure(loan, &plan, amount, refundErr)
+			}
+			continue
+		}
+
+		served[plan.InvestorID] = true
+		loan = investment.Loan
+	}
+
+	return nil
+}
+
+func (u *loanUsecase) GetAvailableLoans(ctx context.Context, dto *dto_request.ApprovedLoanListDTO) (*[]models.Loan, *utils.Meta, error) {
 	filter := repositories.LoanRepositoryFilter{
 		Status: ptr.Of(models.LoanStatusApproved),
+		Sector: utils.ParseStringParam(dto.Sector),
+		Region: utils.ParseStringParam(dto.Region),
+	}
+
+	var err error
+	floatFilters := []struct {
+		value  string
+		target **float64
+	}{
+		{dto.MinAmount, &filter.MinAmount},
+		{dto.MaxAmount, &filter.MaxAmount},
+		{dto.MinRate, &filter.MinRate},
+		{dto.MaxRate, &filter.MaxRate},
+		{dto.MinRemaining, &filter.MinRemaining},
+		{dto.MaxRemaining, &filter.MaxRemaining},
+	}
+	for _, floatFilter := range floatFilters {
+		if *floatFilter.target, err = utils.ParseFloatParam(floatFilter.value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if filter.Tenor, err = utils.ParseIntParam(dto.Tenor); err != nil {
+		return nil, nil, err
+	}
+
+	sort := dto.Sort
+	if sort == "" {
+		sort = "created_at"
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	loans, count, nextCursor, err := u.loanRepository.List(ctx, page, perPage, dto.Cursor, sort, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dto.Cursor != nil {
+		return loans, utils.GenerateCursorMeta(dto.PerPage, nextCursor), nil
 	}
 
-	page, err := strconv.Atoi(dto.Page)
-	if err != nil || page < 1 {
-		page = 1
+	return loans, utils.GenerateMeta(dto.Page, dto.PerPage, count), nil
+}
+
+// ListInvestments shows an investor their own investments, an admin sees
+// every investment, of one loan when loan_id is given.
+func (u *loanUsecase) ListInvestments(ctx context.Context, dto *dto_request.InvestmentListDTO) (*[]models.Investment, *utils.Meta, error) {
+	filter := repositories.InvestmentRepositoryFilter{}
+	if dto.Role != models.RoleAdmin {
+		filter.InvestorID = &dto.UserID
+	}
+
+	if dto.LoanID != "" {
+		loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if loan == nil {
+			return nil, nil, errors.New("loan_not_found")
+		}
+		filter.LoanID = &loan.ID
 	}
 
-	perPage, err := strconv.Atoi(dto.PerPage)
-	if err != nil || perPage < 1 || perPage > 100 {
-		perPage = 10
+	sort := dto.Sort
+	if sort == "" {
+		sort = "-created_at"
 	}
 
-	loans, count, err := u.loanRepository.List(ctx, page, perPage, "created_at", filter)
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	investments, count, nextCursor, err := u.investmentRepository.List(ctx, page, perPage, dto.Cursor, sort, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
-	return loans, count, nil
+	if dto.Cursor != nil {
+		return investments, utils.GenerateCursorMeta(dto.PerPage, nextCursor), nil
+	}
+
+	return investments, utils.GenerateMeta(dto.Page, dto.PerPage, count), nil
 }
 
 func (u *loanUsecase) Invest(ctx context.Context, dto *dto_request.InvestLoanDTO) (*models.Investment, error) {
@@ -125,17 +537,24 @@ func (u *loanUsecase) Invest(ctx context.Context, dto *dto_request.InvestLoanDTO
 		return nil, errors.New("only_approved_loan_allowed")
 	}
 
-	investment, err := models.NewInvestment(dto.InvestorID, dto.Amount, loan)
+	investment, err := u.investmentRepository.Place(ctx, loan.ID, dto.InvestorID, dto.Amount, u.investmentLimits)
 	if err != nil {
 		return nil, err
 	}
+	loan = investment.Loan
 
-	investment, err = u.investmentRepository.Save(ctx, investment)
-	if err != nil {
-		return nil, err
-	}
+	recordAudit(ctx, u.auditService, &models.AuditEntry{
+		Action:       models.AuditActionLoanInvest,
+		EntityType:   models.AuditEntityInvestment,
+		EntityID:     investment.UUID.String(),
+		LoanID:       &investment.LoanID,
+		InvestmentID: &investment.ID,
+		After:        dto_response.InvestmentDetailResponse(investment),
+	})
 
 	if loan.Status == models.LoanStatusInvested {
+		u.publishLoanEvent(ctx, models.WebhookEventLoanInvested, loan)
+
 		go u.SendEmailToInvestors(ctx, loan)
 	}
 
@@ -143,7 +562,8 @@ func (u *loanUsecase) Invest(ctx context.Context, dto *dto_request.InvestLoanDTO
 }
 
 func (u *loanUsecase) SendEmailToInvestors(ctx context.Context, loan *models.Loan) error {
-	page := 1
+	cursor := ""
+	notified := map[uint]bool{}
 
 	investorChan := make(chan models.Investment, 10)
 	var wg sync.WaitGroup
@@ -153,22 +573,28 @@ func (u *loanUsecase) SendEmailToInvestors(ctx context.Context, loan *models.Loa
 	}
 
 	for {
-		investments, _, err := u.investmentRepository.List(ctx, page, 10, "created_at", repositories.InvestmentRepositoryFilter{
+		investments, _, nextCursor, err := u.investmentRepository.List(ctx, 1, 10, &cursor, "id", repositories.InvestmentRepositoryFilter{
 			LoanID: &loan.ID,
 		})
 		if err != nil {
 			fmt.Println(err)
-		}
-
-		if investments != nil && len(*investments) == 0 {
 			break
 		}
 
+		// An investor with several tickets on the loan still gets a single email
 		for _, investment := range *investments {
+			if notified[investment.InvestorID] {
+				continue
+			}
+			notified[investment.InvestorID] = true
+
 			investorChan <- investment
 		}
 
-		page++
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
 	}
 
 	close(investorChan)
@@ -201,21 +627,408 @@ func (u *loanUsecase) Disburse(ctx context.Context, dto *dto_request.DisburseLoa
 		return nil, errors.New("loan_not_found")
 	}
 
-	if loan.Status != models.LoanStatusProposed {
-		return nil, errors.New("only_proposed_loan_allowed")
+	if loan.Status != models.LoanStatusInvested {
+		return nil, errors.New("only_invested_loan_allowed")
 	}
 
-	disburseAggreementUrl, err := u.fileService.Upload(dto.AggreementLetter)
+	borrower, err := u.userRepository.Detail(ctx, loan.BorrowerID)
 	if err != nil {
 		return nil, err
 	}
 
-	loan.Disburse(dto.FieldOfficerID, disburseAggreementUrl)
+	if borrower == nil || borrower.BankAccountNumber == "" {
+		return nil, errors.New("borrower_bank_account_missing")
+	}
 
-	loan, err = u.loanRepository.Save(nil, ctx, loan)
+	documents, err := u.uploadDocuments(loan, dto.Documents, models.DisbursementDocumentTypes, u.documentRequirements.Disbursement, dto.FieldOfficerID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := loanSnapshot(loan)
+	loan.Disburse(dto.FieldOfficerID, documents[0].FileURL, documents[0].Checksum)
+
+	payment := models.NewPayment(loan.ID, models.PaymentDirectionDisbursement, payment_services.PaymentMethodBankTransfer, loan.ProposedAmount)
+	payment.BankCode = borrower.BankCode
+	payment.BankAccountNumber = borrower.BankAccountNumber
+
+	// Claiming the loan before the transfer keeps concurrent requests from
+	// paying the borrower twice
+	err = u.loanRepository.ClaimDisbursement(ctx, loan, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, document := range documents {
+		document.DisbursementID = loan.DisbursmentID
+	}
+
+	err = u.saveDocuments(ctx, loan, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordLoanAudit(ctx, models.AuditActionLoanDisburse, loan, before)
+
+	// The loan stays disbursing until the gateway confirms the transfer
+	result, err := u.paymentGateway.Transfer(ctx, &payment_services.Transfer{
+		Reference:         payment.Reference,
+		Amount:            payment.Amount,
+		BankCode:          borrower.BankCode,
+		BankAccountNumber: borrower.BankAccountNumber,
+		BankAccountName:   borrower.BankAccountName,
+	})
+	if err != nil {
+		payment.Fail(err.Error())
+		if err := u.settleDisbursement(ctx, loan, payment); err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("disbursement_transfer_failed")
+	}
+
+	// The callback may have come first, only the gateway reference is written
+	payment.GatewayReference = result.GatewayReference
+	err = u.paymentRepository.SetGatewayReference(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// CompleteDisbursement applies the outcome of a disbursement transfer
+// reported by the payment gateway. Callbacks may be delivered more than once,
+// a loan that is no longer disbursing is left untouched.
+func (u *loanUsecase) CompleteDisbursement(ctx context.Context, payment *models.Payment) error {
+	loan, err := u.loanRepository.DetailByID(ctx, payment.LoanID)
+	if err != nil {
+		return err
+	}
+
+	if loan == nil {
+		return errors.New("loan_not_found")
+	}
+
+	if loan.Status != models.LoanStatusDisbursing {
+		_, err = u.paymentRepository.Save(ctx, payment)
+		return err
+	}
+
+	return u.settleDisbursement(ctx, loan, payment)
+}
+
+// settleDisbursement moves the loan according to the final transfer status.
+// The payment is stored last so that a failure halfway leaves it pending and
+// the gateway callback, when retried, runs the whole settlement again.
+func (u *loanUsecase) settleDisbursement(ctx context.Context, loan *models.Loan, payment *models.Payment) error {
+	before := loanSnapshot(loan)
+	eventType := models.WebhookEventLoanDisbursed
+	if payment.Status == models.PaymentStatusSuccess {
+		err := u.moveInvestmentFunds(ctx, loan, models.WalletTransactionSettle)
+		if err != nil {
+			return err
+		}
+
+		loan.CompleteDisbursement()
+
+		err = u.installmentRepository.SaveSchedule(ctx, models.NewRepaymentSchedule(loan, *loan.UpdatedAt))
+		if err != nil {
+			return err
+		}
+	} else {
+		loan.FailDisbursement(payment.FailureReason)
+		eventType = models.WebhookEventLoanDisbursementFailed
+	}
+
+	_, err := u.loanRepository.Save(nil, ctx, loan)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.paymentRepository.Save(ctx, payment)
+	if err != nil {
+		return err
+	}
+
+	u.recordLoanAudit(ctx, models.AuditActionLoanCompleteDisbursement, loan, before)
+	u.publishLoanEvent(ctx, eventType, loan)
+
+	return nil
+}
+
+// Detail shows the loan with its repayment schedule, a borrower only sees
+// their own loans.
+func (u *loanUsecase) Detail(ctx context.Context, dto *dto_request.LoanDetailDTO) (*models.Loan, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil || (dto.Role == models.RoleBorower && loan.BorrowerID != dto.UserID) {
+		return nil, errors.New("loan_not_found")
+	}
+
+	// Superseded and written off installments stay visible as history
+	loan.Installments, err = u.installmentRepository.ListHistory(ctx, loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	loan.StatusHistory, err = u.loanRepository.ListStatusHistory(ctx, loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Borrowers and investors see how long each stage took, not which staff member moved it
+	if dto.Role == models.RoleBorower || dto.Role == models.RoleInvestor {
+		for index := range loan.StatusHistory {
+			loan.StatusHistory[index].ActorID = nil
+		}
+	}
+
+	// The visit evidence, its flags and the sign-offs are for staff reviewing the approval
+	if loan.ApprovalID != nil && dto.Role != models.RoleBorower && dto.Role != models.RoleInvestor {
+		approved, err := u.loanRepository.DetailByID(ctx, loan.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if approved != nil && approved.Approval != nil {
+			loan.Approval = approved.Approval
+			loan.Approval.SignOffs, err = u.approvalSignOffRepository.ListByApproval(ctx, loan.Approval.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return loan, nil
+}
+
+func (u *loanUsecase) Cancel(ctx context.Context, dto *dto_request.CancelLoanDTO) (*models.Loan, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil {
+		return nil, errors.New("loan_not_found")
+	}
+
+	if dto.Role != models.RoleAdmin && loan.BorrowerID != dto.UserID {
+		return nil, errors.New("loan_not_found")
+	}
+
+	if !loan.Cancellable() {
+		return nil, errors.New("loan_not_cancellable")
+	}
+
+	before := loanSnapshot(loan)
+	from := loan.Status
+	loan.Cancel(dto.Reason)
+
+	err = u.loanRepository.Release(ctx, loan, []models.LoanStatus{from})
 	if err != nil {
 		return nil, err
 	}
 
+	u.recordLoanAudit(ctx, models.AuditActionLoanCancel, loan, before)
+
+	u.publishLoanEvent(ctx, models.WebhookEventLoanCancelled, loan)
+
 	return loan, nil
 }
+
+// ExpireLoans closes approved loans still not fully funded and gives the
+// investors their reserved money back, it returns how many loans expired.
+func (u *loanUsecase) ExpireLoans(ctx context.Context, approvedBefore time.Time) (int, error) {
+	expired := 0
+	for {
+		loans, _, _, err := u.loanRepository.List(ctx, 1, 50, ptr.Of(""), "created_at", repositories.LoanRepositoryFilter{
+			Status:         ptr.Of(models.LoanStatusApproved),
+			ApprovedBefore: &approvedBefore,
+		})
+		if err != nil {
+			return expired, err
+		}
+
+		if len(*loans) == 0 {
+			return expired, nil
+		}
+
+		// Expired loans leave the filter, so the first page is always the next batch
+		for _, loan := range *loans {
+			before := loanSnapshot(&loan)
+			loan.Expire()
+
+			// A loan funded since the listing is no longer expired, skip it
+			err = u.loanRepository.Release(ctx, &loan, []models.LoanStatus{models.LoanStatusApproved})
+			if err != nil {
+				if err.Error() == "loan_not_cancellable" {
+					continue
+				}
+
+				return expired, err
+			}
+			expired++
+
+			u.recordLoanAudit(ctx, models.AuditActionLoanExpire, &loan, before)
+
+			u.publishLoanEvent(ctx, models.WebhookEventLoanExpired, &loan)
+		}
+	}
+}
+
+// moveInvestmentFunds releases or settles the wallet reservation of every
+// investment in the loan. Transactions already booked for an investment are
+// skipped, so a run interrupted halfway can simply be retried.
+func (u *loanUsecase) moveInvestmentFunds(ctx context.Context, loan *models.Loan, transactionType models.WalletTransactionType) error {
+	cursor := ""
+	for {
+		investments, _, nextCursor, err := u.investmentRepository.List(ctx, 1, 50, &cursor, "id", repositories.InvestmentRepositoryFilter{
+			LoanID: &loan.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, investment := range *investments {
+			_, err = u.walletRepository.Apply(ctx, investment.InvestorID, models.NewInvestmentTransaction(transactionType, &investment))
+			if err != nil && err.Error() != "duplicate_wallet_transaction" {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// uploadDocuments checks the evidence against the allowed and required types
+// before storing anything, so a rejected request leaves no orphan files.
+func (u *loanUsecase) uploadDocuments(
+	loan *models.Loan,
+	uploads []dto_request.DocumentUploadDTO,
+	allowed map[models.DocumentType]bool,
+	required []models.DocumentType,
+	uploadedBy uint,
+) ([]*models.Document, error) {
+	if len(uploads) == 0 {
+		return nil, errors.New("missing_required_documents")
+	}
+
+	provided := map[models.DocumentType]bool{}
+	for _, upload := range uploads {
+		if !allowed[upload.Type] {
+			return nil, errors.New("invalid_document_type")
+		}
+		provided[upload.Type] = true
+	}
+
+	for _, documentType := range required {
+		if !provided[documentType] {
+			return nil, errors.New("missing_required_documents")
+		}
+	}
+
+	documents := make([]*models.Document, 0, len(uploads))
+	for _, upload := range uploads {
+		file, err := u.fileService.Upload(upload.File)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, models.NewDocument(loan.ID, upload.Type, file.URL, file.Checksum, file.ContentType, file.Size, uploadedBy))
+	}
+
+	return documents, nil
+}
+
+// fieldVisit gathers the visit evidence of an approval, the position and
+// time the field app reported and the EXIF location of the uploaded photos.
+func fieldVisit(dto *dto_request.ApproveLoanDTO) (*models.FieldVisit, error) {
+	visit := &models.FieldVisit{
+		Location:  models.NewGeoPoint(dto.Visit.Latitude, dto.Visit.Longitude),
+		VisitedAt: dto.Visit.VisitedAt,
+	}
+
+	if visit.Location != nil && !visit.Location.Valid() {
+		return nil, errors.New("invalid_visit_location")
+	}
+
+	for _, upload := range dto.Documents {
+		metadata, err := file_services.ReadPhotoMetadata(upload.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata == nil {
+			continue
+		}
+
+		visit.Photos = append(visit.Photos, models.FieldPhoto{
+			Location: models.NewGeoPoint(metadata.Latitude, metadata.Longitude),
+			TakenAt:  metadata.TakenAt,
+		})
+	}
+
+	return visit, nil
+}
+
+func (u *loanUsecase) saveDocuments(ctx context.Context, loan *models.Loan, documents []*models.Document) error {
+	for _, document := range documents {
+		document.LoanID = loan.ID
+
+		_, err := u.documentRepository.Save(ctx, document)
+		if err != nil {
+			return err
+		}
+
+		loan.Documents = append(loan.Documents, *document)
+	}
+
+	return nil
+}
+
+func (u *loanUsecase) publishLoanEvent(ctx context.Context, eventType string, loan *models.Loan) {
+	publishLoanWebhook(ctx, u.webhookService, eventType, loan)
+}
+
+func (u *loanUsecase) recordLoanAudit(ctx context.Context, action string, loan *models.Loan, before json.RawMessage) {
+	recordAudit(ctx, u.auditService, loanAuditEntry(action, loan, before))
+}
+
+func loanAuditEntry(action string, loan *models.Loan, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityLoan,
+		EntityID:   loan.UUID.String(),
+		LoanID:     &loan.ID,
+		Before:     before,
+		After:      dto_response.LoanDetailResponse(loan),
+	}
+}
+
+// loanSnapshot captures the loan before a usecase changes it in place
+func loanSnapshot(loan *models.Loan) json.RawMessage {
+	return models.AuditSnapshot(dto_response.LoanDetailResponse(loan))
+}
+
+// recordAudit appends the entry to the audit log. The change it records is
+// committed already, so a failed append is logged with what identifies the
+// entry instead of failing a request that did succeed.
+func recordAudit(ctx context.Context, auditService services.AuditServiceInterface, entry *models.AuditEntry) {
+	if err := auditService.Record(ctx, entry); err != nil {
+		fmt.Printf("audit log append failed for %s of %s %s: %v\n", entry.Action, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+func publishLoanWebhook(ctx context.Context, webhookService services.WebhookServiceInterface, eventType string, loan *models.Loan) {
+	// Partner notification must never fail the loan transition itself
+	if err := webhookService.Publish(ctx, eventType, dto_response.LoanDetailResponse(loan)); err != nil {
+		fmt.Println(err)
+	}
+}
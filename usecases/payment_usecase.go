@@ -0,0 +1,225 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type PaymentUsecaseInterface interface {
+	HandleCallback(ctx context.Context, dto *dto_request.PaymentCallbackDTO) error
+	CreateVirtualAccount(ctx context.Context, dto *dto_request.CreateVirtualAccountDTO) (*models.Loan, error)
+	List(ctx context.Context, dto *dto_request.PaymentListDTO) (*[]models.Payment, int, error)
+}
+
+type paymentUsecase struct {
+	paymentRepository repositories.PaymentRepositoryInterface
+	loanRepository    repositories.LoanRepositoryInterface
+	userRepository    repositories.UserRepositoryInterface
+	walletRepository  repositories.WalletRepositoryInterface
+	auditService      services.AuditServiceInterface
+	paymentGateway    payment_services.PaymentGateway
+	loanUsecase       LoanUsecaseInterface
+	collectionUsecase CollectionUsecaseInterface
+}
+
+func NewPaymentUsecase(
+	paymentRepository repositories.PaymentRepositoryInterface,
+	loanRepository repositories.LoanRepositoryInterface,
+	userRepository repositories.UserRepositoryInterface,
+	walletRepository repositories.WalletRepositoryInterface,
+	auditService services.AuditServiceInterface,
+	paymentGateway payment_services.PaymentGateway,
+	loanUsecase LoanUsecaseInterface,
+	collectionUsecase CollectionUsecaseInterface,
+) PaymentUsecaseInterface {
+	return &paymentUsecase{
+		paymentRepository: paymentRepository,
+		loanRepository:    loanRepository,
+		userRepository:    userRepository,
+		walletRepository:  walletRepository,
+		auditService:      auditService,
+		paymentGateway:    paymentGateway,
+		loanUsecase:       loanUsecase,
+		collectionUsecase: collectionUsecase,
+	}
+}
+
+// HandleCallback applies a payment gateway notification. Gateways retry until
+// they get a success response, so a callback for a payment that is already
+// final is acknowledged without doing anything.
+func (u *paymentUsecase) HandleCallback(ctx context.Context, dto *dto_request.PaymentCallbackDTO) error {
+	callback, err := u.paymentGateway.ParseCallback(dto.Body, dto.Timestamp, dto.Signature)
+	if err != nil {
+		return err
+	}
+
+	switch callback.Event {
+	case payment_services.CallbackTransferCompleted, payment_services.CallbackTransferFailed:
+		return u.handleTransfer(ctx, callback)
+	case payment_services.CallbackVirtualAccountPaid:
+		return u.handleCollection(ctx, callback)
+	default:
+		return errors.New("invalid_callback_payload")
+	}
+}
+
+func (u *paymentUsecase) handleTransfer(ctx context.Context, callback *payment_services.Callback) error {
+	payment, err := u.paymentRepository.DetailByReference(ctx, callback.Reference)
+	if err != nil {
+		return err
+	}
+
+	if payment == nil {
+		return u.handleWithdrawal(ctx, callback)
+	}
+
+	if payment.Final() {
+		return nil
+	}
+
+	payment.GatewayReference = callback.GatewayReference
+	if callback.Status == payment_services.PaymentStatusSuccess {
+		payment.Complete()
+	} else {
+		payment.Fail(callback.FailureReason)
+	}
+
+	return u.loanUsecase.CompleteDisbursement(ctx, payment)
+}
+
+// handleWithdrawal refunds an investor withdrawal the bank did not accept. The
+// refund is booked once, a repeated callback finds it already there.
+func (u *paymentUsecase) handleWithdrawal(ctx context.Context, callback *payment_services.Callback) error {
+	withdrawal, err := u.walletRepository.DetailTransactionByReference(ctx, models.WalletTransactionWithdrawal, callback.Reference)
+	if err != nil {
+		return err
+	}
+
+	if withdrawal == nil {
+		return errors.New("payment_not_found")
+	}
+
+	if callback.Status == payment_services.PaymentStatusSuccess {
+		return nil
+	}
+
+	refund, err := u.walletRepository.Refund(ctx, withdrawal, callback.FailureReason)
+	if err != nil {
+		if err.Error() == "duplicate_wallet_transaction" {
+			return nil
+		}
+
+		return err
+	}
+
+	recordAudit(ctx, u.auditService, walletAuditEntry(models.AuditActionWalletRefund, refund))
+
+	return nil
+}
+
+// handleCollection records a borrower repayment into the loan virtual
+// account, the gateway reference identifies the repayment.
+func (u *paymentUsecase) handleCollection(ctx context.Context, callback *payment_services.Callback) error {
+	existing, err := u.paymentRepository.DetailByGatewayReference(ctx, callback.GatewayReference)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return nil
+	}
+
+	loan, err := u.loanRepository.Detail(ctx, callback.Reference)
+	if err != nil {
+		return err
+	}
+
+	if loan == nil || loan.VirtualAccount != callback.VirtualAccountNumber {
+		return errors.New("payment_not_found")
+	}
+
+	if callback.Amount <= 0 {
+		return errors.New("invalid_callback_payload")
+	}
+
+	payment := models.NewPayment(loan.ID, models.PaymentDirectionCollection, payment_services.PaymentMethodVirtualAccount, callback.Amount)
+	payment.GatewayReference = callback.GatewayReference
+	payment.VirtualAccountNumber = callback.VirtualAccountNumber
+	payment.Complete()
+
+	return u.collectionUsecase.ApplyRepayment(ctx, loan, payment)
+}
+
+// CreateVirtualAccount opens the account the borrower repays into, asking
+// again returns the account already assigned to the loan.
+func (u *paymentUsecase) CreateVirtualAccount(ctx context.Context, dto *dto_request.CreateVirtualAccountDTO) (*models.Loan, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil || loan.BorrowerID != dto.BorrowerID {
+		return nil, errors.New("loan_not_found")
+	}
+
+	if !loan.Repaying() {
+		return nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	if loan.VirtualAccount != "" {
+		return loan, nil
+	}
+
+	borrower, err := u.userRepository.Detail(ctx, loan.BorrowerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if borrower == nil {
+		return nil, errors.New("borrower_not_found")
+	}
+
+	virtualAccount, err := u.paymentGateway.CreateVirtualAccount(ctx, &payment_services.VirtualAccountRequest{
+		Reference: loan.UUID.String(),
+		Name:      borrower.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	before := loanSnapshot(loan)
+	loan.VirtualAccount = virtualAccount.Number
+
+	loan, err = u.loanRepository.Save(nil, ctx, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, loanAuditEntry(models.AuditActionLoanCreateVirtualAccount, loan, before))
+
+	return loan, nil
+}
+
+func (u *paymentUsecase) List(ctx context.Context, dto *dto_request.PaymentListDTO) (*[]models.Payment, int, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if loan == nil || (dto.Role != models.RoleAdmin && loan.BorrowerID != dto.UserID) {
+		return nil, 0, errors.New("loan_not_found")
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.paymentRepository.List(ctx, page, perPage, "-id", repositories.PaymentRepositoryFilter{
+		LoanID: &loan.ID,
+	})
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type RateCardUsecaseInterface interface {
+	Create(ctx context.Context, dto *dto_request.CreateRateCardDTO) (*models.RateCard, error)
+	Update(ctx context.Context, dto *dto_request.UpdateRateCardDTO) (*models.RateCard, error)
+	Deactivate(ctx context.Context, rateCardID string) (*models.RateCard, error)
+	List(ctx context.Context, dto *dto_request.RateCardListDTO) (*[]models.RateCard, int, error)
+}
+
+type rateCardUsecase struct {
+	rateCardRepository repositories.RateCardRepositoryInterface
+	auditService       services.AuditServiceInterface
+}
+
+func NewRateCardUsecase(rateCardRepository repositories.RateCardRepositoryInterface, auditService services.AuditServiceInterface) RateCardUsecaseInterface {
+	return &rateCardUsecase{
+		rateCardRepository: rateCardRepository,
+		auditService:       auditService,
+	}
+}
+
+func (u *rateCardUsecase) Create(ctx context.Context, dto *dto_request.CreateRateCardDTO) (*models.RateCard, error) {
+	rateCard := models.NewRateCard(dto.Product, dto.RiskGrade, dto.MinTenor, dto.MaxTenor, dto.Rate, dto.PlatformFeeRate, dto.AdminID)
+	if err := validateRateCard(rateCard); err != nil {
+		return nil, err
+	}
+
+	rateCard, err := u.rateCardRepository.Save(ctx, rateCard)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, rateCardAuditEntry(models.AuditActionRateCardCreate, rateCard, nil))
+
+	return rateCard, nil
+}
+
+// Update never touches the stored card, it publishes a new version so loans
+// priced with the old one keep an exact record of their terms.
+func (u *rateCardUsecase) Update(ctx context.Context, dto *dto_request.UpdateRateCardDTO) (*models.RateCard, error) {
+	rateCard, err := u.rateCardRepository.Detail(ctx, dto.RateCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rateCard == nil {
+		return nil, errors.New("rate_card_not_found")
+	}
+
+	if !rateCard.Active {
+		return nil, errors.New("rate_card_inactive")
+	}
+
+	next := rateCard.NextVersion(dto.AdminID)
+	if dto.MinTenor != nil {
+		next.MinTenor = *dto.MinTenor
+	}
+
+	if dto.MaxTenor != nil {
+		next.MaxTenor = *dto.MaxTenor
+	}
+
+	if dto.Rate != nil {
+		next.Rate = *dto.Rate
+	}
+
+	if dto.PlatformFeeRate != nil {
+		next.PlatformFeeRate = *dto.PlatformFeeRate
+	}
+
+	if err := validateRateCard(next); err != nil {
+		return nil, err
+	}
+
+	// The new version is recorded against the one it replaces
+	before := models.AuditSnapshot(dto_response.RateCardDetailResponse(rateCard))
+
+	next, err = u.rateCardRepository.SaveVersion(ctx, rateCard, next)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, rateCardAuditEntry(models.AuditActionRateCardUpdate, next, before))
+
+	return next, nil
+}
+
+func (u *rateCardUsecase) Deactivate(ctx context.Context, rateCardID string) (*models.RateCard, error) {
+	rateCard, err := u.rateCardRepository.Detail(ctx, rateCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rateCard == nil {
+		return nil, errors.New("rate_card_not_found")
+	}
+
+	before := models.AuditSnapshot(dto_response.RateCardDetailResponse(rateCard))
+
+	now := time.Now()
+	rateCard.Active = false
+	rateCard.UpdatedAt = &now
+
+	rateCard, err = u.rateCardRepository.Save(ctx, rateCard)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, rateCardAuditEntry(models.AuditActionRateCardDeactivate, rateCard, before))
+
+	return rateCard, nil
+}
+
+func (u *rateCardUsecase) List(ctx context.Context, dto *dto_request.RateCardListDTO) (*[]models.RateCard, int, error) {
+	filter := repositories.RateCardRepositoryFilter{
+		Product:   utils.ParseStringParam(dto.Product),
+		RiskGrade: utils.ParseStringParam(dto.RiskGrade),
+	}
+
+	if dto.Active != "" {
+		active, err := strconv.ParseBool(dto.Active)
+		if err != nil {
+			return nil, 0, errors.New("invalid_filter")
+		}
+		filter.Active = &active
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.rateCardRepository.List(ctx, page, perPage, "-created_at", filter)
+}
+
+func rateCardAuditEntry(action string, rateCard *models.RateCard, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityRateCard,
+		EntityID:   rateCard.UUID.String(),
+		Before:     before,
+		After:      dto_response.RateCardDetailResponse(rateCard),
+	}
+}
+
+func validateRateCard(rateCard *models.RateCard) error {
+	if rateCard.Product == "" || !models.RiskGrades[rateCard.RiskGrade] {
+		return errors.New("invalid_rate_card")
+	}
+
+	if rateCard.MinTenor < 0 || rateCard.MaxTenor < rateCard.MinTenor {
+		return errors.New("invalid_rate_card")
+	}
+
+	if rateCard.Rate <= 0 || rateCard.PlatformFeeRate < 0 || rateCard.PlatformFeeRate > 100 {
+		return errors.New("invalid_rate_card")
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type ReportUsecaseInterface interface {
+	LoansByStatus(ctx context.Context, dto *dto_request.ReportDTO) ([]models.StatusReport, error)
+	Disbursements(ctx context.Context, dto *dto_request.DisbursementReportDTO) ([]models.DisbursementReport, error)
+	TimeToFund(ctx context.Context, dto *dto_request.ReportDTO) (*models.TimeToFundReport, error)
+	Funding(ctx context.Context, dto *dto_request.ReportDTO) (*models.FundingReport, error)
+	PortfolioAtRisk(ctx context.Context, dto *dto_request.ReportDTO) (*models.PortfolioAtRiskReport, error)
+	InvestorReturns(ctx context.Context, dto *dto_request.InvestorReturnReportDTO) ([]models.InvestorReturnReport, int, error)
+	Branches(ctx context.Context, dto *dto_request.ReportDTO) ([]models.BranchReport, error)
+}
+
+type reportUsecase struct {
+	reportRepository repositories.ReportRepositoryInterface
+}
+
+func NewReportUsecase(
+	reportRepository repositories.ReportRepositoryInterface,
+) ReportUsecaseInterface {
+	return &reportUsecase{
+		reportRepository: reportRepository,
+	}
+}
+
+func (u *reportUsecase) LoansByStatus(ctx context.Context, dto *dto_request.ReportDTO) ([]models.StatusReport, error) {
+	filter, err := reportFilter(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reportRepository.LoansByStatus(ctx, filter)
+}
+
+func (u *reportUsecase) Disbursements(ctx context.Context, dto *dto_request.DisbursementReportDTO) ([]models.DisbursementReport, error) {
+	filter, err := reportFilter(&dto.ReportDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	period := dto.Period
+	if period == "" {
+		period = "month"
+	}
+
+	if !models.ReportPeriods[period] {
+		return nil, errors.New("invalid_report_period")
+	}
+
+	return u.reportRepository.Disbursements(ctx, period, filter)
+}
+
+func (u *reportUsecase) TimeToFund(ctx context.Context, dto *dto_request.ReportDTO) (*models.TimeToFundReport, error) {
+	filter, err := reportFilter(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reportRepository.TimeToFund(ctx, filter)
+}
+
+func (u *reportUsecase) Funding(ctx context.Context, dto *dto_request.ReportDTO) (*models.FundingReport, error) {
+	filter, err := reportFilter(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reportRepository.Funding(ctx, filter)
+}
+
+func (u *reportUsecase) PortfolioAtRisk(ctx context.Context, dto *dto_request.ReportDTO) (*models.PortfolioAtRiskReport, error) {
+	filter, err := reportFilter(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reportRepository.PortfolioAtRisk(ctx, filter)
+}
+
+func (u *reportUsecase) InvestorReturns(ctx context.Context, dto *dto_request.InvestorReturnReportDTO) ([]models.InvestorReturnReport, int, error) {
+	filter, err := reportFilter(&dto.ReportDTO)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort := dto.Sort
+	if sort == "" {
+		sort = "-invested_amount"
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.reportRepository.InvestorReturns(ctx, page, perPage, sort, filter)
+}
+
+func (u *reportUsecase) Branches(ctx context.Context, dto *dto_request.ReportDTO) ([]models.BranchReport, error) {
+	filter, err := reportFilter(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reportRepository.Branches(ctx, filter)
+}
+
+func reportFilter(dto *dto_request.ReportDTO) (repositories.ReportRepositoryFilter, error) {
+	filter := repositories.ReportRepositoryFilter{
+		Region: utils.ParseStringParam(dto.Region),
+	}
+
+	var err error
+	if filter.From, err = utils.ParseTimeParam(dto.From); err != nil {
+		return filter, err
+	}
+
+	if filter.To, err = utils.ParseTimeParam(dto.To); err != nil {
+		return filter, err
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("invalid_filter")
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type RestructuringUsecaseInterface interface {
+	Request(ctx context.Context, dto *dto_request.RequestRestructuringDTO) (*models.Restructuring, error)
+	Approve(ctx context.Context, dto *dto_request.DecideRestructuringDTO) (*models.Restructuring, error)
+	Reject(ctx context.Context, dto *dto_request.DecideRestructuringDTO) (*models.Restructuring, error)
+	List(ctx context.Context, dto *dto_request.RestructuringListDTO) (*[]models.Restructuring, int, error)
+}
+
+type restructuringUsecase struct {
+	restructuringRepository repositories.RestructuringRepositoryInterface
+	loanRepository          repositories.LoanRepositoryInterface
+	installmentRepository   repositories.InstallmentRepositoryInterface
+	webhookService          services.WebhookServiceInterface
+	auditService            services.AuditServiceInterface
+}
+
+func NewRestructuringUsecase(
+	restructuringRepository repositories.RestructuringRepositoryInterface,
+	loanRepository repositories.LoanRepositoryInterface,
+	installmentRepository repositories.InstallmentRepositoryInterface,
+	webhookService services.WebhookServiceInterface,
+	auditService services.AuditServiceInterface,
+) RestructuringUsecaseInterface {
+	return &restructuringUsecase{
+		restructuringRepository: restructuringRepository,
+		loanRepository:          loanRepository,
+		installmentRepository:   installmentRepository,
+		webhookService:          webhookService,
+		auditService:            auditService,
+	}
+}
+
+// Request files a restructuring for a supervisor to decide on, the schedule
+// does not change until it is approved.
+func (u *restructuringUsecase) Request(ctx context.Context, dto *dto_request.RequestRestructuringDTO) (*models.Restructuring, error) {
+	loan, err := u.loanRepository.Detail(ctx, dto.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan == nil {
+		return nil, errors.New("loan_not_found")
+	}
+
+	if !loan.Repaying() {
+		return nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	installments, err := u.installmentRepository.ListByLoan(ctx, loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasUnpaidInstallment(installments) {
+		return nil, errors.New("nothing_to_restructure")
+	}
+
+	restructuring, err := models.NewRestructuring(loan, dto.UserID, dto.ExtraInstallments, dto.HolidayPeriods, dto.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	restructuring, err = u.restructuringRepository.Save(ctx, restructuring)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, restructuringAuditEntry(models.AuditActionRestructuringRequest, restructuring, nil))
+
+	return restructuring, nil
+}
+
+func (u *restructuringUsecase) Approve(ctx context.Context, dto *dto_request.DecideRestructuringDTO) (*models.Restructuring, error) {
+	restructuring, err := u.pendingRestructuring(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if !restructuring.Loan.Repaying() {
+		return nil, errors.New("only_disbursed_loan_allowed")
+	}
+
+	installments, err := u.installmentRepository.ListByLoan(ctx, restructuring.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := models.AuditSnapshot(dto_response.RestructuringDetailResponse(restructuring))
+	changed := restructuring.Approve(dto.SupervisorID, dto.Note, installments, time.Now())
+
+	err = u.restructuringRepository.SaveDecision(ctx, restructuring, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, restructuringAuditEntry(models.AuditActionRestructuringApprove, restructuring, before))
+
+	publishLoanWebhook(ctx, u.webhookService, models.WebhookEventLoanRestructured, restructuring.Loan)
+
+	return restructuring, nil
+}
+
+func (u *restructuringUsecase) Reject(ctx context.Context, dto *dto_request.DecideRestructuringDTO) (*models.Restructuring, error) {
+	restructuring, err := u.pendingRestructuring(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	before := models.AuditSnapshot(dto_response.RestructuringDetailResponse(restructuring))
+	restructuring.Reject(dto.SupervisorID, dto.Note)
+
+	err = u.restructuringRepository.SaveDecision(ctx, restructuring, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, restructuringAuditEntry(models.AuditActionRestructuringReject, restructuring, before))
+
+	return restructuring, nil
+}
+
+func (u *restructuringUsecase) List(ctx context.Context, dto *dto_request.RestructuringListDTO) (*[]models.Restructuring, int, error) {
+	filter := repositories.RestructuringRepositoryFilter{}
+	if dto.Status != "" {
+		status := models.RestructuringStatus(dto.Status)
+		filter.Status = &status
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.restructuringRepository.List(ctx, page, perPage, "-created_at", filter)
+}
+
+// pendingRestructuring loads a request still waiting for a decision. The
+// supervisor deciding cannot be the one who asked for it.
+func (u *restructuringUsecase) pendingRestructuring(ctx context.Context, dto *dto_request.DecideRestructuringDTO) (*models.Restructuring, error) {
+	restructuring, err := u.restructuringRepository.Detail(ctx, dto.RestructuringID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restructuring == nil {
+		return nil, errors.New("restructuring_not_found")
+	}
+
+	if restructuring.Status != models.RestructuringRequested {
+		return nil, errors.New("restructuring_already_decided")
+	}
+
+	if restructuring.RequestedBy == dto.SupervisorID {
+		return nil, errors.New("restructuring_requester_cannot_decide")
+	}
+
+	return restructuring, nil
+}
+
+func restructuringAuditEntry(action string, restructuring *models.Restructuring, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityRestructuring,
+		EntityID:   restructuring.UUID.String(),
+		LoanID:     &restructuring.LoanID,
+		Before:     before,
+		After:      dto_response.RestructuringDetailResponse(restructuring),
+	}
+}
+
+func hasUnpaidInstallment(installments []models.Installment) bool {
+	for _, installment := range installments {
+		if !installment.Paid() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,168 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/services/payment_services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type WalletUsecaseInterface interface {
+	Detail(ctx context.Context, userID uint) (*models.Wallet, error)
+	Deposit(ctx context.Context, dto *dto_request.DepositDTO) (*models.WalletTransaction, error)
+	Withdraw(ctx context.Context, dto *dto_request.WithdrawDTO) (*models.WalletTransaction, error)
+	ListTransactions(ctx context.Context, dto *dto_request.WalletTransactionListDTO) (*[]models.WalletTransaction, int, error)
+}
+
+type walletUsecase struct {
+	walletRepository repositories.WalletRepositoryInterface
+	userRepository   repositories.UserRepositoryInterface
+	auditService     services.AuditServiceInterface
+	paymentGateway   payment_services.PaymentGateway
+}
+
+func NewWalletUsecase(
+	walletRepository repositories.WalletRepositoryInterface,
+	userRepository repositories.UserRepositoryInterface,
+	auditService services.AuditServiceInterface,
+	paymentGateway payment_services.PaymentGateway,
+) WalletUsecaseInterface {
+	return &walletUsecase{
+		walletRepository: walletRepository,
+		userRepository:   userRepository,
+		auditService:     auditService,
+		paymentGateway:   paymentGateway,
+	}
+}
+
+func (u *walletUsecase) Detail(ctx context.Context, userID uint) (*models.Wallet, error) {
+	return u.walletRepository.FindOrCreate(ctx, userID)
+}
+
+// Deposit charges the investor through the payment gateway and credits the
+// wallet once the charge succeeds. Failed charges are kept in the history.
+func (u *walletUsecase) Deposit(ctx context.Context, dto *dto_request.DepositDTO) (*models.WalletTransaction, error) {
+	if dto.Amount <= 0 {
+		return nil, errors.New("invalid_amount")
+	}
+
+	if !payment_services.PaymentMethods[dto.Method] {
+		return nil, errors.New("invalid_payment_method")
+	}
+
+	result, err := u.paymentGateway.Charge(ctx, &payment_services.Charge{
+		Reference:  uuid.NewString(),
+		CustomerID: dto.UserID,
+		Amount:     dto.Amount,
+		Method:     dto.Method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := models.NewWalletTransaction(models.WalletTransactionDeposit, dto.Amount, result.GatewayReference)
+	if result.Status != payment_services.PaymentStatusSuccess {
+		transaction.Status = models.WalletTransactionFailed
+		transaction.FailureReason = result.FailureReason
+	}
+
+	_, err = u.walletRepository.Apply(ctx, dto.UserID, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, walletAuditEntry(models.AuditActionWalletDeposit, transaction))
+
+	if transaction.Status != models.WalletTransactionSuccess {
+		return nil, errors.New("deposit_failed")
+	}
+
+	return transaction, nil
+}
+
+// Withdraw pays the investor out to their bank account. The wallet is debited
+// before the transfer is sent so the money cannot be spent twice, a transfer
+// the gateway refuses is refunded right away and one the bank rejects later is
+// refunded when its callback arrives.
+func (u *walletUsecase) Withdraw(ctx context.Context, dto *dto_request.WithdrawDTO) (*models.WalletTransaction, error) {
+	if dto.Amount <= 0 {
+		return nil, errors.New("invalid_amount")
+	}
+
+	user, err := u.userRepository.Detail(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.BankAccountNumber == "" {
+		return nil, errors.New("bank_account_missing")
+	}
+
+	transaction := models.NewWalletTransaction(models.WalletTransactionWithdrawal, dto.Amount, uuid.NewString())
+
+	_, err = u.walletRepository.Apply(ctx, dto.UserID, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, walletAuditEntry(models.AuditActionWalletWithdraw, transaction))
+
+	_, err = u.paymentGateway.Transfer(ctx, &payment_services.Transfer{
+		Reference:         transaction.Reference,
+		Amount:            transaction.Amount,
+		BankCode:          user.BankCode,
+		BankAccountNumber: user.BankAccountNumber,
+		BankAccountName:   user.BankAccountName,
+	})
+	if err != nil {
+		refund, err := u.walletRepository.Refund(ctx, transaction, err.Error())
+		if err != nil {
+			return nil, err
+		}
+
+		recordAudit(ctx, u.auditService, walletAuditEntry(models.AuditActionWalletRefund, refund))
+
+		return nil, errors.New("withdrawal_failed")
+	}
+
+	return transaction, nil
+}
+
+func (u *walletUsecase) ListTransactions(ctx context.Context, dto *dto_request.WalletTransactionListDTO) (*[]models.WalletTransaction, int, error) {
+	wallet, err := u.walletRepository.FindOrCreate(ctx, dto.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter := repositories.WalletTransactionRepositoryFilter{
+		WalletID: &wallet.ID,
+	}
+
+	if dto.Type != "" {
+		transactionType := models.WalletTransactionType(dto.Type)
+		if !models.WalletTransactionTypes[transactionType] {
+			return nil, 0, errors.New("invalid_filter")
+		}
+		filter.Type = &transactionType
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.walletRepository.ListTransactions(ctx, page, perPage, "-id", filter)
+}
+
+func walletAuditEntry(action string, transaction *models.WalletTransaction) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityWalletTransaction,
+		EntityID:   transaction.UUID.String(),
+		After:      dto_response.WalletTransactionDetailResponse(transaction),
+	}
+}
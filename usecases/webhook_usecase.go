@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	dto_request "github.com/peang/amartha-loan-service/dto/request"
+	dto_response "github.com/peang/amartha-loan-service/dto/response"
+	"github.com/peang/amartha-loan-service/models"
+	"github.com/peang/amartha-loan-service/repositories"
+	"github.com/peang/amartha-loan-service/services"
+	"github.com/peang/amartha-loan-service/utils"
+)
+
+type WebhookUsecaseInterface interface {
+	Create(ctx context.Context, dto *dto_request.CreateWebhookDTO) (*models.WebhookSubscription, error)
+	Update(ctx context.Context, dto *dto_request.UpdateWebhookDTO) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, webhookID string) error
+	List(ctx context.Context, dto *dto_request.WebhookListDTO) (*[]models.WebhookSubscription, int, error)
+	ListDeliveries(ctx context.Context, dto *dto_request.WebhookDeliveryListDTO) (*[]models.WebhookDelivery, int, error)
+	Redeliver(ctx context.Context, dto *dto_request.RedeliverWebhookDTO) (*models.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	webhookRepository repositories.WebhookRepositoryInterface
+	webhookService    services.WebhookServiceInterface
+	auditService      services.AuditServiceInterface
+}
+
+func NewWebhookUsecase(
+	webhookRepository repositories.WebhookRepositoryInterface,
+	webhookService services.WebhookServiceInterface,
+	auditService services.AuditServiceInterface,
+) WebhookUsecaseInterface {
+	return &webhookUsecase{
+		webhookRepository: webhookRepository,
+		webhookService:    webhookService,
+		auditService:      auditService,
+	}
+}
+
+func (u *webhookUsecase) Create(ctx context.Context, dto *dto_request.CreateWebhookDTO) (*models.WebhookSubscription, error) {
+	if err := validateWebhook(dto.URL, dto.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := dto.Secret
+	if secret == "" {
+		bytes := make([]byte, 32)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(bytes)
+	}
+
+	subscription, err := u.webhookRepository.Save(ctx, models.NewWebhookSubscription(dto.URL, dto.EventTypes, secret))
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, webhookAuditEntry(models.AuditActionWebhookCreate, subscription, nil))
+
+	return subscription, nil
+}
+
+func (u *webhookUsecase) Update(ctx context.Context, dto *dto_request.UpdateWebhookDTO) (*models.WebhookSubscription, error) {
+	subscription, err := u.webhookRepository.Detail(ctx, dto.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription == nil {
+		return nil, errors.New("webhook_not_found")
+	}
+
+	before := models.AuditSnapshot(dto_response.WebhookDetailResponse(subscription, false))
+
+	if dto.URL != nil {
+		subscription.URL = *dto.URL
+	}
+
+	if dto.EventTypes != nil {
+		subscription.EventTypes = *dto.EventTypes
+	}
+
+	if dto.Secret != nil && *dto.Secret != "" {
+		subscription.Secret = *dto.Secret
+	}
+
+	if dto.Active != nil {
+		subscription.Active = *dto.Active
+	}
+
+	if err := validateWebhook(subscription.URL, subscription.EventTypes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription.UpdatedAt = &now
+
+	subscription, err = u.webhookRepository.Save(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, webhookAuditEntry(models.AuditActionWebhookUpdate, subscription, before))
+
+	return subscription, nil
+}
+
+func (u *webhookUsecase) Delete(ctx context.Context, webhookID string) error {
+	subscription, err := u.webhookRepository.Detail(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if subscription == nil {
+		return errors.New("webhook_not_found")
+	}
+
+	err = u.webhookRepository.Delete(ctx, subscription)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.auditService, &models.AuditEntry{
+		Action:     models.AuditActionWebhookDelete,
+		EntityType: models.AuditEntityWebhook,
+		EntityID:   subscription.UUID.String(),
+		Before:     dto_response.WebhookDetailResponse(subscription, false),
+	})
+
+	return nil
+}
+
+func (u *webhookUsecase) List(ctx context.Context, dto *dto_request.WebhookListDTO) (*[]models.WebhookSubscription, int, error) {
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.webhookRepository.List(ctx, page, perPage, "-created_at", repositories.WebhookRepositoryFilter{})
+}
+
+func (u *webhookUsecase) ListDeliveries(ctx context.Context, dto *dto_request.WebhookDeliveryListDTO) (*[]models.WebhookDelivery, int, error) {
+	subscription, err := u.webhookRepository.Detail(ctx, dto.WebhookID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if subscription == nil {
+		return nil, 0, errors.New("webhook_not_found")
+	}
+
+	page, perPage := utils.ParsePagination(dto.Page, dto.PerPage)
+
+	return u.webhookRepository.ListDeliveries(ctx, page, perPage, "-created_at", repositories.WebhookDeliveryRepositoryFilter{
+		SubscriptionID: &subscription.ID,
+	})
+}
+
+func (u *webhookUsecase) Redeliver(ctx context.Context, dto *dto_request.RedeliverWebhookDTO) (*models.WebhookDelivery, error) {
+	delivery, err := u.webhookRepository.DetailDelivery(ctx, dto.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery == nil {
+		return nil, errors.New("webhook_delivery_not_found")
+	}
+
+	redelivery, err := u.webhookService.Redeliver(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.auditService, &models.AuditEntry{
+		Action:     models.AuditActionWebhookRedeliver,
+		EntityType: models.AuditEntityWebhookDelivery,
+		EntityID:   redelivery.UUID.String(),
+		After:      dto_response.WebhookDeliveryResponse(redelivery),
+	})
+
+	return redelivery, nil
+}
+
+// webhookAuditEntry leaves the signing secret out of the log
+func webhookAuditEntry(action string, subscription *models.WebhookSubscription, before json.RawMessage) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityWebhook,
+		EntityID:   subscription.UUID.String(),
+		Before:     before,
+		After:      dto_response.WebhookDetailResponse(subscription, false),
+	}
+}
+
+func validateWebhook(rawURL string, eventTypes []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("invalid_webhook_url")
+	}
+
+	if len(eventTypes) == 0 {
+		return errors.New("invalid_webhook_event_type")
+	}
+
+	for _, eventType := range eventTypes {
+		if !models.WebhookEventTypes[eventType] {
+			return errors.New("invalid_webhook_event_type")
+		}
+	}
+
+	return nil
+}
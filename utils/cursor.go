@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// cursor is the keyset position of the last row of a page. It is handed to
+// clients base64 encoded and must be treated as opaque by them.
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"i"`
+}
+
+func EncodeCursor(sort string, value interface{}, id uint) (string, error) {
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(cursor{
+		Sort:  sort,
+		Value: encodedValue,
+		ID:    id,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// DecodeCursor reads the sort value into value and returns the row id. A
+// cursor produced for another sort order is rejected, it would skip rows.
+func DecodeCursor(encoded string, sort string, value interface{}) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, errors.New("invalid_cursor")
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort {
+		return 0, errors.New("invalid_cursor")
+	}
+
+	if err := json.Unmarshal(c.Value, value); err != nil {
+		return 0, errors.New("invalid_cursor")
+	}
+
+	return c.ID, nil
+}
+
+// KeysetCondition compares (column, id) against the cursor position in the
+// direction of the sort, so it needs the sort value and id as arguments.
+func KeysetCondition(column string, idColumn string, desc bool) string {
+	operator := ">"
+	if desc {
+		operator = "<"
+	}
+
+	return fmt.Sprintf("(%s, %s) %s (?, ?)", column, idColumn, operator)
+}
+
+func KeysetOrder(column string, idColumn string, desc bool) []string {
+	direction := sortDirection(desc)
+
+	return []string{
+		fmt.Sprintf("%s %s", column, direction),
+		fmt.Sprintf("%s %s", idColumn, direction),
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)
+	encoded, err := EncodeCursor("-created_at", createdAt, 42)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var value time.Time
+	id, err := DecodeCursor(encoded, "-created_at", &value)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if id != 42 || !value.Equal(createdAt) {
+		t.Errorf("decoded (%v, %d), want (%v, 42)", value, id, createdAt)
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	encoded, err := EncodeCursor("rate", 12.5, 7)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+		sort    string
+		value   interface{}
+	}{
+		{name: "another sort order", encoded: encoded, sort: "-rate", value: new(float64)},
+		{name: "not base64", encoded: "%%%", sort: "rate", value: new(float64)},
+		{name: "not json", encoded: base64.RawURLEncoding.EncodeToString([]byte("rate")), sort: "rate", value: new(float64)},
+		{name: "value of another type", encoded: encoded, sort: "rate", value: new(time.Time)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.encoded, tt.sort, tt.value); err == nil || err.Error() != "invalid_cursor" {
+				t.Errorf("error = %v, want invalid_cursor", err)
+			}
+		})
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	tests := []struct {
+		name          string
+		desc          bool
+		wantCondition string
+		wantOrder     []string
+	}{
+		{
+			name:          "ascending",
+			wantCondition: "(loan.rate, loan.id) > (?, ?)",
+			wantOrder:     []string{"loan.rate asc", "loan.id asc"},
+		},
+		{
+			name:          "descending",
+			desc:          true,
+			wantCondition: "(loan.rate, loan.id) < (?, ?)",
+			wantOrder:     []string{"loan.rate desc", "loan.id desc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeysetCondition("loan.rate", "loan.id", tt.desc); got != tt.wantCondition {
+				t.Errorf("condition = %q, want %q", got, tt.wantCondition)
+			}
+
+			order := KeysetOrder("loan.rate", "loan.id", tt.desc)
+			if len(order) != len(tt.wantOrder) || order[0] != tt.wantOrder[0] || order[1] != tt.wantOrder[1] {
+				t.Errorf("order = %v, want %v", order, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestGenerateCursorMeta(t *testing.T) {
+	meta := GenerateCursorMeta("25", "next")
+	if meta.PerPage != 25 || meta.NextCursor != "next" || meta.Total != 0 {
+		t.Errorf("meta = %+v, want per page 25, the next cursor and no total", meta)
+	}
+}
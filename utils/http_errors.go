@@ -1,9 +1,146 @@
 package utils
 
 var httpErrors = map[string]int{
+	// Query Error
+	"invalid_sort":   400,
+	"invalid_filter": 400,
+	"invalid_cursor": 400,
+
 	// Loans Error
-	"loan_not_found":             404,
-	"only_proposed_loan_allowed": 400,
+	"loan_not_found":               404,
+	"only_proposed_loan_allowed":   400,
+	"only_approved_loan_allowed":   400,
+	"only_invested_loan_allowed":   400,
+	"loan_not_cancellable":         409,
+	"only_disbursed_loan_allowed":  400,
+	"borrower_not_found":           404,
+	"only_delinquent_loan_allowed": 400,
+	"loan_already_closed":          409,
+
+	"loan_invested_amount_exceeds_proposed_amount": 400,
+
+	// Approvals Error
+	"invalid_visit_location":             400,
+	"invalid_visit_time":                 400,
+	"only_pending_approval_loan_allowed": 400,
+	"approver_is_proposer":               403,
+	"loan_already_approved_by_approver":  409,
+	"sign_off_not_required":              409,
+
+	// Loan Products Error
+	"loan_product_not_found":        422,
+	"loan_product_inactive":         422,
+	"loan_product_code_taken":       409,
+	"invalid_loan_product":          400,
+	"invalid_installment_frequency": 400,
+	"amount_below_product_minimum":  400,
+	"amount_above_product_maximum":  400,
+	"tenor_not_allowed":             400,
+	"borrower_not_eligible":         403,
+
+	// Investments Error
+	"investment_below_minimum_ticket":      400,
+	"investment_above_maximum_ticket":      400,
+	"investment_not_multiple_of_lot_size":  400,
+	"investment_exceeds_loan_share":        422,
+	"investment_exceeds_borrower_exposure": 422,
+	"investment_exceeds_investor_exposure": 422,
+
+	// Wallets Error
+	"invalid_amount":               400,
+	"invalid_payment_method":       400,
+	"insufficient_balance":         422,
+	"deposit_failed":               402,
+	"withdrawal_failed":            502,
+	"bank_account_missing":         422,
+	"duplicate_wallet_transaction": 409,
+
+	// Borrower Groups Error
+	"borrower_group_not_found":         404,
+	"invalid_borrower_group":           400,
+	"group_leader_not_member":          400,
+	"group_leader_cannot_leave":        409,
+	"borrower_already_in_group":        409,
+	"borrower_not_group_member":        400,
+	"group_member_has_open_loan":       409,
+	"group_loan_not_found":             404,
+	"invalid_group_loan":               400,
+	"group_loan_already_proposed":      409,
+	"only_proposed_group_loan_allowed": 400,
+	"invalid_meeting_date":             400,
+	"invalid_group_collection":         400,
+	"group_collection_exceeds_due":     422,
+	"group_collection_reference_taken": 409,
+
+	// Field Sync Error
+	"field_sync_disabled":        503,
+	"invalid_bundle_signature":   401,
+	"invalid_bundle_payload":     400,
+	"invalid_collection_record":  400,
+	"amount_exceeds_outstanding": 422,
+
+	// Payoffs Error
+	"invalid_payoff_date":          400,
+	"repayment_schedule_not_found": 422,
+	"payoff_amount_mismatch":       422,
+	"payoff_charge_failed":         402,
+	"payoff_in_progress":           409,
+
+	// Restructurings Error
+	"restructuring_not_found":               404,
+	"invalid_restructuring":                 400,
+	"nothing_to_restructure":                422,
+	"restructuring_already_requested":       409,
+	"restructuring_already_decided":         409,
+	"restructuring_requester_cannot_decide": 403,
+
+	// Investment Listings Error
+	"investment_not_found":             404,
+	"investment_listing_not_found":     404,
+	"investment_listing_not_available": 409,
+	"investment_already_listed":        409,
+	"cannot_buy_own_listing":           400,
+
+	// Auto Invest Plans Error
+	"auto_invest_plan_not_found":   404,
+	"invalid_auto_invest_plan":     400,
+	"auto_invest_budget_exhausted": 422,
+
+	// Payments Error
+	"payment_not_found":             404,
+	"invalid_callback_signature":    401,
+	"invalid_callback_payload":      400,
+	"borrower_bank_account_missing": 422,
+	"disbursement_transfer_failed":  502,
+
+	// Pricing Error
+	"invalid_risk_grade":  400,
+	"invalid_rate_card":   400,
+	"rate_card_not_found": 422,
+	"rate_card_inactive":  409,
+
+	// Files Error
+	"invalid_file_name":      400,
+	"empty_file":             400,
+	"file_type_not_allowed":  400,
+	"file_too_large":         413,
+	"file_storage_forbidden": 502,
+
+	// Documents Error
+	"document_not_found":         404,
+	"document_access_denied":     403,
+	"invalid_document_type":      400,
+	"missing_required_documents": 400,
+
+	// Webhooks Error
+	"webhook_not_found":          404,
+	"webhook_delivery_not_found": 404,
+	"invalid_webhook_url":        400,
+	"invalid_webhook_event_type": 400,
+
+	// Reports Error
+	"invalid_report_period": 400,
+	"invalid_report_format": 400,
 }
 
 func GetErrorCode(err string) int {
@@ -1,26 +1,50 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 )
 
 type Meta struct {
-	Page      int `json:"page"`
-	PerPage   int `json:"per_page"`
-	Total     int `json:"total"`
-	TotalPage int `json:"total_page"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Total      int    `json:"total"`
+	TotalPage  int    `json:"total_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
-func GenerateSort(str string) string {
-	len := len(str)
-	flag := string(str[0])
-	if flag == "-" {
-		return fmt.Sprintf("%v %v", str[1:len], "desc")
-	} else {
-		return fmt.Sprintf("%v %v", str[0:len], "asc")
+// GenerateSort turns "column" or "-column" into an ORDER BY expression. Only
+// keys of columns are accepted and their mapped expression is what reaches
+// the query, so user input is never interpolated into SQL.
+func GenerateSort(str string, columns map[string]string) (string, error) {
+	column, desc, err := ParseSort(str, columns)
+	if err != nil {
+		return "", err
 	}
+
+	return fmt.Sprintf("%v %v", column, sortDirection(desc)), nil
+}
+
+// ParseSort resolves a whitelisted sort key to its column expression and direction
+func ParseSort(str string, columns map[string]string) (column string, desc bool, err error) {
+	desc = strings.HasPrefix(str, "-")
+	column, ok := columns[strings.TrimPrefix(str, "-")]
+	if !ok {
+		return "", false, errors.New("invalid_sort")
+	}
+
+	return column, desc, nil
+}
+
+func sortDirection(desc bool) string {
+	if desc {
+		return "desc"
+	}
+
+	return "asc"
 }
 
 func GenerateOffsetLimit(page, perPage int) (offset, limit int) {
@@ -38,17 +62,23 @@ func GenerateOffsetLimit(page, perPage int) (offset, limit int) {
 	return offset, limit
 }
 
-func GenerateMeta(pageString string, perPageString string, count int) *Meta {
+func ParsePagination(pageString string, perPageString string) (page, perPage int) {
 	page, err := strconv.Atoi(pageString)
 	if err != nil || page < 1 {
 		page = 1
 	}
 
-	perPage, err := strconv.Atoi(perPageString)
+	perPage, err = strconv.Atoi(perPageString)
 	if err != nil || perPage < 1 || perPage > 100 {
 		perPage = 10
 	}
 
+	return page, perPage
+}
+
+func GenerateMeta(pageString string, perPageString string, count int) *Meta {
+	page, perPage := ParsePagination(pageString, perPageString)
+
 	return &Meta{
 		Page:      page,
 		PerPage:   perPage,
@@ -56,3 +86,14 @@ func GenerateMeta(pageString string, perPageString string, count int) *Meta {
 		TotalPage: int(math.Ceil(float64(count) / float64(perPage))),
 	}
 }
+
+// GenerateCursorMeta builds the meta of a cursor page, totals are not
+// computed in cursor mode as avoiding the COUNT is the point of it.
+func GenerateCursorMeta(perPageString string, nextCursor string) *Meta {
+	_, perPage := ParsePagination("", perPageString)
+
+	return &Meta{
+		PerPage:    perPage,
+		NextCursor: nextCursor,
+	}
+}
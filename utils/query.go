@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ParseFloatParam returns nil for an absent query param, so it can feed an
+// optional repository filter directly.
+func ParseFloatParam(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errors.New("invalid_filter")
+	}
+
+	return &parsed, nil
+}
+
+func ParseIntParam(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, errors.New("invalid_filter")
+	}
+
+	return &parsed, nil
+}
+
+// ParseTimeParam reads an RFC 3339 timestamp
+func ParseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("invalid_filter")
+	}
+
+	return &parsed, nil
+}
+
+func ParseStringParam(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/peang/amartha-loan-service/models"
+)
+
+type requestMetaKey struct{}
+
+// RequestMeta tells who made a request and from where, it travels in the
+// request context down to the usecases recording what the request changed.
+// A nil actor is the system itself, a background job or a gateway callback.
+type RequestMeta struct {
+	ActorID   *uint
+	ActorRole models.UserRole
+	RequestID string
+	ClientIP  string
+}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func RequestMetaFrom(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}